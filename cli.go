@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// command is a CLI subcommand such as "cards create", acting on Planka through the same
+// client as the MCP server
+type command struct {
+	// usage lists the flags of the command, e.g. "--list ID --name NAME"
+	usage   string
+	summary string
+	run     func(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error
+}
+
+// commands are the CLI subcommands by their resource and action
+var commands = map[string]command{
+	"projects list": {
+		summary: "List the projects",
+		run:     runProjectsList,
+	},
+	"boards list": {
+		usage:   "--project ID",
+		summary: "List the boards of a project",
+		run:     runBoardsList,
+	},
+	"boards export": {
+		usage:   "--board ID [--output FILE]",
+		summary: "Export a board with its lists, cards and labels as JSON",
+		run:     runBoardsExport,
+	},
+	"lists list": {
+		usage:   "--board ID",
+		summary: "List the lists of a board",
+		run:     runListsList,
+	},
+	"cards list": {
+		usage:   "--list ID",
+		summary: "List the cards of a list",
+		run:     runCardsList,
+	},
+	"cards create": {
+		usage:   "--list ID --name NAME [--description TEXT] [--due DATE]",
+		summary: "Create a card at the bottom of a list",
+		run:     runCardsCreate,
+	},
+	"cards move": {
+		usage:   "--card ID --list ID",
+		summary: "Move a card to the bottom of another list",
+		run:     runCardsMove,
+	},
+}
+
+// isCommand reports whether the arguments left after the global flags name a subcommand
+// rather than the server modes
+func isCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	for name := range commands {
+		if resource, _, _ := strings.Cut(name, " "); resource == args[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommand runs the subcommand named by args and returns the exit code of the process
+func runCommand(client *planka.Client, args []string) int {
+	if len(args) < 2 {
+		printCommands(os.Stderr)
+		return 2
+	}
+	name := args[0] + " " + args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		printCommands(os.Stderr)
+		return 2
+	}
+	if client == nil {
+		fmt.Fprintln(os.Stderr, "Commands require PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+		return 1
+	}
+
+	flags := flag.NewFlagSet("planka-mcp "+name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: planka-mcp %s %s\n\n%s\n", name, cmd.usage, cmd.summary)
+		flags.PrintDefaults()
+	}
+	if err := cmd.run(context.Background(), client, flags, args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "planka-mcp %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// printCommands lists the subcommands
+func printCommands(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: planka-mcp [flags] <command> [command flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", name, commands[name].usage, commands[name].summary)
+	}
+	fmt.Fprintf(tw, "  init\tSet up the Planka URL and credentials and print the MCP host configuration\n")
+	fmt.Fprintf(tw, "  doctor\tCheck the Planka URL, version and credentials, and list the detected capabilities\n")
+	tw.Flush()
+	fmt.Fprintln(w, "\nList commands accept --json to print JSON instead of a table.")
+}
+
+// parseFlags parses the flags of a command, reporting the flags it requires that are unset
+func parseFlags(flags *flag.FlagSet, args []string, required ...string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	for _, name := range required {
+		if flags.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("--%s is required", name)
+		}
+	}
+	return nil
+}
+
+// printTable prints rows under header as aligned columns, or v as JSON if asJSON is set
+func printTable(asJSON bool, v interface{}, header []string, rows [][]string) error {
+	if asJSON {
+		return printJSON(os.Stdout, v)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printJSON writes v as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// formatDue formats a due date for tables; cards without one show nothing
+func formatDue(due *time.Time) string {
+	if due == nil {
+		return ""
+	}
+	return due.Local().Format("2006-01-02 15:04")
+}
+
+func runProjectsList(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, project := range projects {
+		rows = append(rows, []string{project.ID, project.Name})
+	}
+	return printTable(*asJSON, projects, []string{"ID", "NAME"}, rows)
+}
+
+func runBoardsList(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	projectID := flags.String("project", "", "The project ID")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if err := parseFlags(flags, args, "project"); err != nil {
+		return err
+	}
+	boards, err := client.GetBoards(ctx, *projectID)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, board := range boards {
+		rows = append(rows, []string{board.ID, board.Name})
+	}
+	return printTable(*asJSON, boards, []string{"ID", "NAME"}, rows)
+}
+
+func runBoardsExport(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	boardID := flags.String("board", "", "The board ID")
+	output := flags.String("output", "", "Write the export to this file instead of standard output")
+	if err := parseFlags(flags, args, "board"); err != nil {
+		return err
+	}
+	board, err := exportBoard(ctx, client, *boardID)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return printJSON(os.Stdout, board)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := printJSON(file, board); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// exportBoard returns a board with its lists, and their cards with their labels, in
+// board order
+func exportBoard(ctx context.Context, client *planka.Client, boardID string) (*planka.Board, error) {
+	board, err := client.GetBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := client.GetLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	cardLabels, err := client.GetCardLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	labelsByID := map[string]planka.Label{}
+	for _, label := range labels {
+		labelsByID[label.ID] = label
+	}
+	cardLabelsByCard := map[string][]planka.Label{}
+	for _, cardLabel := range cardLabels {
+		if label, ok := labelsByID[cardLabel.LabelID]; ok {
+			cardLabelsByCard[cardLabel.CardID] = append(cardLabelsByCard[cardLabel.CardID], label)
+		}
+	}
+	cardsByList := map[string][]planka.Card{}
+	for _, card := range cards {
+		card.Labels = cardLabelsByCard[card.ID]
+		cardsByList[card.ListID] = append(cardsByList[card.ListID], card)
+	}
+
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Position < lists[j].Position })
+	for i := range lists {
+		listCards := cardsByList[lists[i].ID]
+		sort.SliceStable(listCards, func(a, b int) bool { return listCards[a].Position < listCards[b].Position })
+		lists[i].Cards = listCards
+	}
+	board.Lists = lists
+	return board, nil
+}
+
+func runListsList(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	boardID := flags.String("board", "", "The board ID")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if err := parseFlags(flags, args, "board"); err != nil {
+		return err
+	}
+	lists, err := client.GetLists(ctx, *boardID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Position < lists[j].Position })
+	var rows [][]string
+	for _, list := range lists {
+		rows = append(rows, []string{list.ID, list.Name})
+	}
+	return printTable(*asJSON, lists, []string{"ID", "NAME"}, rows)
+}
+
+func runCardsList(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	listID := flags.String("list", "", "The list ID")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if err := parseFlags(flags, args, "list"); err != nil {
+		return err
+	}
+	cards, err := client.GetCards(ctx, *listID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Position < cards[j].Position })
+	var rows [][]string
+	for _, card := range cards {
+		rows = append(rows, []string{card.ID, card.Name, formatDue(card.DueDate)})
+	}
+	return printTable(*asJSON, cards, []string{"ID", "NAME", "DUE"}, rows)
+}
+
+func runCardsCreate(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	listID := flags.String("list", "", "The list ID")
+	name := flags.String("name", "", "The card name")
+	description := flags.String("description", "", "The card description")
+	due := flags.String("due", "", "The due date: YYYY-MM-DD or an RFC 3339 timestamp")
+	if err := parseFlags(flags, args, "list", "name"); err != nil {
+		return err
+	}
+	req := planka.CreateCardRequest{
+		Name:        *name,
+		ListID:      *listID,
+		Description: *description,
+	}
+	if *due != "" {
+		dueDate, err := parseDue(*due)
+		if err != nil {
+			return err
+		}
+		req.DueDate = &dueDate
+	}
+	// Without a position the client adds the card at the bottom of the list
+	card, err := client.CreateCard(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, card)
+}
+
+func runCardsMove(ctx context.Context, client *planka.Client, flags *flag.FlagSet, args []string) error {
+	cardID := flags.String("card", "", "The card ID")
+	listID := flags.String("list", "", "The list to move the card to")
+	if err := parseFlags(flags, args, "card", "list"); err != nil {
+		return err
+	}
+	position, err := bottomPosition(ctx, client, *listID)
+	if err != nil {
+		return err
+	}
+	card, err := client.MoveCard(ctx, *cardID, *listID, position)
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, card)
+}
+
+// bottomPosition returns the position after the last card of a list
+func bottomPosition(ctx context.Context, client *planka.Client, listID string) (float64, error) {
+	cards, err := client.GetCards(ctx, listID)
+	if err != nil {
+		return 0, err
+	}
+	position := 0.0
+	for _, card := range cards {
+		if card.Position > position {
+			position = card.Position
+		}
+	}
+	return position + 65535, nil
+}
+
+// parseDue parses a due date given as a day, taken as the end of that day in local
+// time, or as an RFC 3339 timestamp
+func parseDue(value string) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return day.Add(24*time.Hour - time.Second).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --due %q: expected YYYY-MM-DD or an RFC 3339 timestamp", value)
+	}
+	return t.UTC(), nil
+}
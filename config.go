@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// fileConfig is the --config file. Each setting is the default of an environment variable
+// or flag, which override it when set.
+type fileConfig struct {
+	Planka plankaConfig `json:"planka,omitzero"`
+	// Instances configures several Planka servers, like an --instances file
+	Instances *instancesConfig `json:"instances,omitempty"`
+	HTTP      httpConfig       `json:"http,omitzero"`
+	// ReadOnly exposes only the tools that do not change Planka (--read-only)
+	ReadOnly bool        `json:"readOnly,omitempty"`
+	Tools    toolsConfig `json:"tools,omitzero"`
+	// Flags sets any other command-line flag by name, e.g. "recurrences-file"
+	Flags map[string]interface{} `json:"flags,omitempty"`
+}
+
+// plankaConfig is the connection to Planka, set by the PLANKA_* environment variables
+type plankaConfig struct {
+	URL      string `json:"url,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	AuthMode string `json:"authMode,omitempty"`
+	Probe    bool   `json:"probe,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// Timeout and CacheTTL are durations such as "30s"
+	Timeout             string `json:"timeout,omitempty"`
+	CacheTTL            string `json:"cacheTTL,omitempty"`
+	MaxItems            int    `json:"maxItems,omitempty"`
+	MaxIdleConnsPerHost int    `json:"maxIdleConnsPerHost,omitempty"`
+	DisableKeepAlives   bool   `json:"disableKeepAlives,omitempty"`
+	Proxy               string `json:"proxy,omitempty"`
+	CACert              string `json:"caCert,omitempty"`
+	TLSSkipVerify       bool   `json:"tlsSkipVerify,omitempty"`
+}
+
+// httpConfig is the HTTP server
+type httpConfig struct {
+	// Enabled runs the HTTP server instead of stdio (--http)
+	Enabled     bool     `json:"enabled,omitempty"`
+	Addr        string   `json:"addr,omitempty"`
+	Port        int      `json:"port,omitempty"`
+	BasePath    string   `json:"basePath,omitempty"`
+	APIKeys     []string `json:"apiKeys,omitempty"`
+	CORSOrigins []string `json:"corsOrigins,omitempty"`
+}
+
+// toolsConfig narrows the exposed tools, like PLANKA_MCP_TOOLS_ALLOW and PLANKA_MCP_TOOLS_DENY
+type toolsConfig struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// loadConfig reads the --config file at path. Values may reference environment variables
+// as ${VAR} so secrets can stay out of the file.
+func loadConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var config fileConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// apply makes the settings of the config file the defaults of the environment variables
+// and of the flags of flags, leaving alone those already set
+func (c *fileConfig) apply(flags *flag.FlagSet) error {
+	setEnv := func(name, value string) {
+		if _, ok := os.LookupEnv(name); !ok && value != "" {
+			os.Setenv(name, os.ExpandEnv(value))
+		}
+	}
+	setEnvBool := func(name string, value bool) {
+		if value {
+			setEnv(name, "true")
+		}
+	}
+	setEnvInt := func(name string, value int) {
+		if value != 0 {
+			setEnv(name, strconv.Itoa(value))
+		}
+	}
+
+	p := c.Planka
+	setEnv("PLANKA_URL", p.URL)
+	setEnv("PLANKA_TOKEN", p.Token)
+	setEnv("PLANKA_USERNAME", p.Username)
+	setEnv("PLANKA_PASSWORD", p.Password)
+	setEnv("PLANKA_AUTH_MODE", p.AuthMode)
+	setEnvBool("PLANKA_PROBE", p.Probe)
+	setEnv("PLANKA_TIMEZONE", p.Timezone)
+	setEnv("PLANKA_TIMEOUT", p.Timeout)
+	setEnv("PLANKA_CACHE_TTL", p.CacheTTL)
+	setEnvInt("PLANKA_MAX_ITEMS", p.MaxItems)
+	setEnvInt("PLANKA_MAX_IDLE_CONNS_PER_HOST", p.MaxIdleConnsPerHost)
+	setEnvBool("PLANKA_DISABLE_KEEP_ALIVES", p.DisableKeepAlives)
+	setEnv("PLANKA_PROXY", p.Proxy)
+	setEnv("PLANKA_CA_CERT", p.CACert)
+	setEnvBool("PLANKA_TLS_SKIP_VERIFY", p.TLSSkipVerify)
+	setEnv("PLANKA_MCP_TOOLS_ALLOW", strings.Join(c.Tools.Allow, ","))
+	setEnv("PLANKA_MCP_TOOLS_DENY", strings.Join(c.Tools.Deny, ","))
+	setEnv("MCP_API_KEYS", strings.Join(c.HTTP.APIKeys, ","))
+
+	values := map[string]interface{}{}
+	for name, value := range c.Flags {
+		values[name] = value
+	}
+	if c.HTTP.Enabled {
+		values["http"] = true
+	}
+	if c.HTTP.Addr != "" {
+		values["http-addr"] = c.HTTP.Addr
+	}
+	if c.HTTP.Port != 0 {
+		values["http-port"] = c.HTTP.Port
+	}
+	if c.HTTP.BasePath != "" {
+		values["base-path"] = c.HTTP.BasePath
+	}
+	if len(c.HTTP.CORSOrigins) > 0 {
+		values["cors-origins"] = c.HTTP.CORSOrigins
+	}
+	if c.ReadOnly {
+		values["read-only"] = true
+	}
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for name, value := range values {
+		// The config and env files are read before the config file takes effect
+		if flags.Lookup(name) == nil || name == "config" || name == "env-file" {
+			return fmt.Errorf("unknown flag %q", name)
+		}
+		if set[name] {
+			continue
+		}
+		if err := flags.Set(name, flagValue(value)); err != nil {
+			return fmt.Errorf("flag %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// flagValue formats a JSON value as the text of a flag; lists become comma-separated
+func flagValue(value interface{}) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = flagValue(item)
+		}
+		return strings.Join(items, ",")
+	case string:
+		return os.ExpandEnv(v)
+	case float64:
+		// JSON numbers decode as floats; whole ones are formatted without an exponent
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
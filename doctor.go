@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+const (
+	// doctorTimeout bounds the checks of one Planka server
+	doctorTimeout = 30 * time.Second
+	// tokenExpiryWarning is how long before a token that cannot be renewed expires the
+	// doctor starts warning about it
+	tokenExpiryWarning = 7 * 24 * time.Hour
+)
+
+// doctorTarget is a Planka server the doctor subcommand checks, with the settings it is
+// configured by so fixes can name them
+type doctorTarget struct {
+	title    string
+	url      string
+	token    string
+	username string
+	password string
+	cookie   bool
+	// urlSetting and the others name where each setting comes from, e.g. PLANKA_URL
+	urlSetting      string
+	tokenSetting    string
+	passwordSetting string
+}
+
+// doctorCheck is the outcome of one check
+type doctorCheck struct {
+	name string
+	// status is "ok", "warn" or "fail"
+	status string
+	detail string
+	// fix tells how to resolve a warning or failure
+	fix string
+}
+
+// doctorReport is the outcome of the checks of one Planka server
+type doctorReport struct {
+	checks       []doctorCheck
+	capabilities []string
+}
+
+func (r *doctorReport) ok(name, detail string) {
+	r.checks = append(r.checks, doctorCheck{name: name, status: "ok", detail: detail})
+}
+
+func (r *doctorReport) warn(name, detail, fix string) {
+	r.checks = append(r.checks, doctorCheck{name: name, status: "warn", detail: detail, fix: fix})
+}
+
+func (r *doctorReport) fail(name, detail, fix string) {
+	r.checks = append(r.checks, doctorCheck{name: name, status: "fail", detail: detail, fix: fix})
+}
+
+// failed reports whether any check failed
+func (r *doctorReport) failed() bool {
+	for _, check := range r.checks {
+		if check.status == "fail" {
+			return true
+		}
+	}
+	return false
+}
+
+// runDoctor checks every configured Planka server, given by the PLANKA_* environment
+// variables or instances read from path, prints a report to w and returns the exit code
+// of the process: 1 if a check failed
+func runDoctor(w io.Writer, instances *instancesConfig, path string, opts []planka.Option) int {
+	targets, err := doctorTargets(instances, path)
+	if err != nil {
+		fmt.Fprintf(w, "Configuration: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	for i, target := range targets {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		report := diagnose(ctx, target, opts)
+		cancel()
+		printDoctorReport(w, target.title, report)
+		if report.failed() {
+			code = 1
+		}
+	}
+	return code
+}
+
+// doctorTargets lists the Planka servers to check: the instances if any, otherwise the
+// server configured by the PLANKA_* environment variables
+func doctorTargets(instances *instancesConfig, path string) ([]doctorTarget, error) {
+	if instances == nil {
+		return []doctorTarget{{
+			title:           "Planka",
+			url:             os.Getenv("PLANKA_URL"),
+			token:           os.Getenv("PLANKA_TOKEN"),
+			username:        os.Getenv("PLANKA_USERNAME"),
+			password:        os.Getenv("PLANKA_PASSWORD"),
+			cookie:          os.Getenv("PLANKA_AUTH_MODE") == "cookie",
+			urlSetting:      "PLANKA_URL",
+			tokenSetting:    "PLANKA_TOKEN",
+			passwordSetting: "PLANKA_USERNAME and PLANKA_PASSWORD",
+		}}, nil
+	}
+	if len(instances.Instances) == 0 {
+		return nil, fmt.Errorf("%s defines no instances", path)
+	}
+
+	names := make([]string, 0, len(instances.Instances))
+	for name := range instances.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	targets := make([]doctorTarget, 0, len(names))
+	for _, name := range names {
+		instance := instances.Instances[name]
+		setting := func(field string) string {
+			return fmt.Sprintf("the %s of instance %q in %s", field, name, path)
+		}
+		targets = append(targets, doctorTarget{
+			title:           fmt.Sprintf("Instance %q", name),
+			url:             os.ExpandEnv(instance.URL),
+			token:           os.ExpandEnv(instance.Token),
+			username:        os.ExpandEnv(instance.Username),
+			password:        os.ExpandEnv(instance.Password),
+			cookie:          instance.Auth == "cookie",
+			urlSetting:      setting("url"),
+			tokenSetting:    setting("token"),
+			passwordSetting: setting("username and password"),
+		})
+	}
+	return targets, nil
+}
+
+// diagnose checks that target is reachable, serves a supported Planka version and
+// accepts its credentials, stopping at the first check the later ones depend on
+func diagnose(ctx context.Context, target doctorTarget, opts []planka.Option) doctorReport {
+	var report doctorReport
+
+	url, err := planka.NormalizeBaseURL(target.url)
+	if err != nil {
+		report.fail("URL", err.Error(), fmt.Sprintf("Set %s to the root URL Planka is served at, e.g. https://planka.example.com", target.urlSetting))
+		return report
+	}
+
+	start := time.Now()
+	info, err := planka.NewClient(url, "", opts...).ServerInfo(ctx)
+	if err != nil {
+		report.fail("URL", err.Error(), reachabilityFix(err))
+		return report
+	}
+	report.ok("URL", fmt.Sprintf("%s serves the Planka API (%s)", url, time.Since(start).Round(time.Millisecond)))
+
+	major := 1
+	if info.Version != "" {
+		major, _ = strconv.Atoi(strings.SplitN(strings.TrimPrefix(info.Version, "v"), ".", 2)[0])
+	}
+	switch {
+	case info.Version == "":
+		report.ok("Version", "Planka 1 (it does not report its version), supported")
+	case major == 1 || major == 2:
+		report.ok("Version", fmt.Sprintf("Planka %s, supported", info.Version))
+	default:
+		report.warn("Version", fmt.Sprintf("Planka %s has not been tested with planka-mcp", info.Version),
+			"planka-mcp supports Planka 1 and 2; check for a newer planka-mcp release if tools fail")
+	}
+	report.capabilities = []string{"projects, boards, lists, cards, labels, tasks, comments and stopwatches"}
+	if major >= 2 {
+		report.capabilities = append(report.capabilities, "custom fields", "archive and trash lists", "card list-change times")
+	}
+	if info.OIDC != nil {
+		sso := "single sign-on (OIDC)"
+		if info.OIDC.IsEnforced {
+			sso = "single sign-on (OIDC, enforced)"
+		}
+		report.capabilities = append(report.capabilities, sso)
+	}
+
+	client, ok := authenticate(target, url, info, opts, &report)
+	if !ok {
+		return report
+	}
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		var apiErr *planka.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			report.fail("Authentication", "Planka rejected the access token",
+				fmt.Sprintf("Create a new API token in Planka and set it as %s", target.tokenSetting))
+		} else {
+			report.fail("Authentication", err.Error(), "Check that the user still exists and is not deactivated")
+		}
+		return report
+	}
+	report.ok("Authentication", fmt.Sprintf("Acting as %s (%s)", me.Name, me.Username))
+
+	switch expiresAt, ok := client.TokenExpiry(); {
+	case client.CanRenewToken():
+		report.ok("Token", "Renewed by logging in again when it expires")
+	case !ok:
+		report.ok("Token", "Does not expire")
+	case time.Until(expiresAt) <= 0:
+		report.fail("Token", fmt.Sprintf("Expired on %s", expiresAt.Format(time.DateTime)), tokenFix(target))
+	case time.Until(expiresAt) < tokenExpiryWarning:
+		report.warn("Token", fmt.Sprintf("Expires on %s (in %d hours) and cannot be renewed automatically",
+			expiresAt.Format(time.DateTime), int(time.Until(expiresAt).Hours())), tokenFix(target))
+	default:
+		report.ok("Token", fmt.Sprintf("Expires on %s", expiresAt.Format(time.DateTime)))
+	}
+
+	projects, err := client.GetProjects(ctx)
+	switch {
+	case err != nil:
+		report.fail("Access", err.Error(), "Check that the user may list projects")
+	case len(projects) == 0:
+		report.warn("Access", "The user is not a member of any project",
+			"Add the user to the projects the agent should work on")
+	default:
+		report.ok("Access", fmt.Sprintf("%d projects visible", len(projects)))
+	}
+	return report
+}
+
+// authenticate creates the client of target, logging in if it has no token
+func authenticate(target doctorTarget, url string, info *planka.ServerInfo, opts []planka.Option, report *doctorReport) (*planka.Client, bool) {
+	if target.token != "" {
+		if target.cookie {
+			report.capabilities = append(report.capabilities, "cookie authentication")
+		}
+		return planka.NewClient(url, target.token, opts...), true
+	}
+	if target.username == "" || target.password == "" {
+		report.fail("Authentication", "No credentials are configured",
+			fmt.Sprintf("Set %s to an API token, or set %s", target.tokenSetting, target.passwordSetting))
+		return nil, false
+	}
+
+	if target.cookie {
+		opts = append(opts[:len(opts):len(opts)], planka.WithCookieAuth())
+		report.capabilities = append(report.capabilities, "cookie authentication")
+	}
+	client, err := planka.NewClientWithPassword(url, target.username, target.password, opts...)
+	if err != nil {
+		fix := fmt.Sprintf("Check %s", target.passwordSetting)
+		if info.OIDC != nil && info.OIDC.IsEnforced {
+			fix = fmt.Sprintf("Planka enforces single sign-on, so passwords are rejected; set %s to an API token instead", target.tokenSetting)
+		}
+		report.fail("Authentication", err.Error(), fix)
+		return nil, false
+	}
+	return client, true
+}
+
+// reachabilityFix suggests how to resolve a failure to reach Planka
+func reachabilityFix(err error) string {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "does not look like a Planka server"):
+		return "Use the URL you open Planka at in the browser, without /api or any page path"
+	case strings.Contains(message, "certificate"):
+		return "Set PLANKA_CA_CERT to the CA that signed Planka's certificate (or PLANKA_TLS_SKIP_VERIFY=true for testing only)"
+	case strings.Contains(message, "no such host"):
+		return "Check the host name of the URL"
+	case strings.Contains(message, "connection refused"):
+		return "Check the port of the URL and that Planka is running"
+	case strings.Contains(message, "deadline exceeded") || strings.Contains(message, "timeout"):
+		return "Check that this machine can reach Planka, setting PLANKA_PROXY if it needs a proxy, or raise PLANKA_TIMEOUT"
+	}
+	return "Check that Planka is running and reachable from this machine"
+}
+
+// tokenFix suggests how to replace a token that cannot be renewed
+func tokenFix(target doctorTarget) string {
+	return fmt.Sprintf("Replace %s with a new token, or set %s so the server logs in again on its own",
+		target.tokenSetting, target.passwordSetting)
+}
+
+// printDoctorReport prints the checks of one Planka server with the fixes of those that
+// did not pass
+func printDoctorReport(w io.Writer, title string, report doctorReport) {
+	fmt.Fprintln(w, title)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, check := range report.checks {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", check.status, check.name, check.detail)
+		if check.fix != "" {
+			fmt.Fprintf(tw, "  \t\t→ %s\n", check.fix)
+		}
+	}
+	tw.Flush()
+	if len(report.capabilities) > 0 {
+		fmt.Fprintf(w, "  Capabilities: %s\n", strings.Join(report.capabilities, ", "))
+	}
+}
@@ -1,3 +1,18 @@
 module github.com/ayushgarg/mcp-planka
 
-go 1.21
+go 1.25.0
+
+require (
+	github.com/coder/websocket v1.8.14
+	github.com/modelcontextprotocol/go-sdk v1.6.1
+	golang.org/x/time v0.15.0
+)
+
+require (
+	github.com/google/jsonschema-go v0.4.3 // indirect
+	github.com/segmentio/asm v1.1.3 // indirect
+	github.com/segmentio/encoding v0.5.4 // indirect
+	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/oauth2 v0.35.0 // indirect
+	golang.org/x/sys v0.41.0 // indirect
+)
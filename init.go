@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// defaultConfigFile is the config file init writes unless told otherwise
+const defaultConfigFile = "planka-mcp.json"
+
+// hostServer is the entry that starts the server in the configuration of an MCP host
+type hostServer struct {
+	Type    string   `json:"type,omitempty"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// wizard asks the questions of the init subcommand
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for an answer, returning fallback for an empty one
+func (w *wizard) ask(question, fallback string) (string, error) {
+	if fallback != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, fallback)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return fallback, nil
+}
+
+// confirm asks a yes/no question, no unless answered otherwise
+func (w *wizard) confirm(question string) (bool, error) {
+	answer, err := w.ask(question+" (y/N)", "")
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes"), err
+}
+
+// runInit asks for the Planka URL and credentials, checks them against Planka, writes a
+// --config file and prints the configuration MCP hosts need to start the server with it.
+// It returns the exit code of the process.
+func runInit(in io.Reader, out io.Writer, opts []planka.Option) int {
+	w := &wizard{in: bufio.NewReader(in), out: out}
+	if err := w.run(opts); err != nil {
+		fmt.Fprintf(out, "\nplanka-mcp init: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (w *wizard) run(opts []planka.Option) error {
+	fmt.Fprintln(w.out, "This sets up planka-mcp to act on your Planka server.")
+	fmt.Fprintln(w.out)
+	ctx := context.Background()
+
+	// Questions are asked again until Planka accepts the answers
+	var url string
+	for {
+		answer, err := w.ask("Planka URL, e.g. https://planka.example.com", os.Getenv("PLANKA_URL"))
+		if err != nil {
+			return err
+		}
+		if url, err = planka.NormalizeBaseURL(answer); err == nil {
+			_, err = planka.NewClient(url, "", opts...).ServerInfo(ctx)
+		}
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(w.out, "  %v\n", err)
+	}
+
+	var config plankaConfig
+	for {
+		config = plankaConfig{URL: url}
+		method, err := w.ask("Log in with an API token (t) or a username and password (p)", "t")
+		if err != nil {
+			return err
+		}
+		var client *planka.Client
+		switch strings.ToLower(method) {
+		case "t", "token":
+			fmt.Fprintln(w.out, "  Create a token in Planka under your user settings.")
+			if config.Token, err = w.ask("API token", ""); err != nil {
+				return err
+			}
+			client = planka.NewClient(url, config.Token, opts...)
+		case "p", "password":
+			if config.Username, err = w.ask("Username or email", ""); err != nil {
+				return err
+			}
+			fmt.Fprintln(w.out, "  The password is shown as you type it.")
+			if config.Password, err = w.ask("Password", ""); err != nil {
+				return err
+			}
+			client, err = planka.NewClientWithPassword(url, config.Username, config.Password, opts...)
+		default:
+			fmt.Fprintln(w.out, "  Answer t or p.")
+			continue
+		}
+		if err == nil {
+			var me *planka.User
+			if me, err = client.GetMe(ctx); err == nil {
+				fmt.Fprintf(w.out, "  Logged in as %s (%s).\n", me.Name, me.Username)
+				break
+			}
+		}
+		fmt.Fprintf(w.out, "  Planka did not accept the credentials: %v\n", err)
+	}
+
+	path, err := w.ask("Config file to write", defaultConfigFile)
+	if err != nil {
+		return err
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		overwrite, err := w.confirm(path + " exists. Overwrite it?")
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return errors.New("the config file was left as it is")
+		}
+	}
+	data, err := json.MarshalIndent(fileConfig{Planka: config}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The file holds credentials, so only its owner may read it
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "\nWrote %s.\n", path)
+
+	command, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	server := hostServer{Command: command, Args: []string{"--config", path}}
+	claude, _ := json.MarshalIndent(map[string]interface{}{
+		"mcpServers": map[string]hostServer{"planka": server},
+	}, "", "  ")
+	server.Type = "stdio"
+	vscode, _ := json.MarshalIndent(map[string]interface{}{
+		"servers": map[string]hostServer{"planka": server},
+	}, "", "  ")
+	fmt.Fprintf(w.out, "\nFor Claude Desktop, add this to %s:\n\n%s\n", claudeDesktopConfig(), claude)
+	fmt.Fprintf(w.out, "\nFor VS Code, add this to .vscode/mcp.json in your workspace:\n\n%s\n", vscode)
+	fmt.Fprintf(w.out, "\nThen restart the host. Run %s --config %s doctor to check the setup at any time.\n", command, path)
+	return nil
+}
+
+// claudeDesktopConfig returns where Claude Desktop keeps its configuration on this system
+func claudeDesktopConfig() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json")
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Claude", "claude_desktop_config.json")
+	}
+	return filepath.Join(home, ".config", "Claude", "claude_desktop_config.json")
+}
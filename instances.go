@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// instanceConfig describes one Planka server in the --instances config file.
+// Either token or username and password are required.
+type instanceConfig struct {
+	URL      string `json:"url"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Auth is "cookie" to authenticate with Planka's httpOnly cookie after logging in
+	Auth string `json:"auth"`
+}
+
+// instancesConfig is the --instances config file
+type instancesConfig struct {
+	// Default names the instance used when a tool call does not pick one;
+	// it may be omitted when there is only one instance
+	Default   string                    `json:"default"`
+	Instances map[string]instanceConfig `json:"instances"`
+}
+
+// loadInstances reads the --instances config file and connects to every Planka instance in it
+// using opts, after checking that each URL serves the Planka API when probe is set.
+// Values may reference environment variables as ${VAR} so secrets can stay out of the file.
+func loadInstances(path string, probe bool, opts ...planka.Option) (map[string]*planka.Client, string, error) {
+	config, err := readInstances(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return connectInstances(config, path, probe, opts...)
+}
+
+// readInstances reads the --instances config file
+func readInstances(path string) (instancesConfig, error) {
+	var config instancesConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// connectInstances connects to every Planka instance of config, read from path, using
+// opts, after checking that each URL serves the Planka API when probe is set
+func connectInstances(config instancesConfig, path string, probe bool, opts ...planka.Option) (map[string]*planka.Client, string, error) {
+	if len(config.Instances) == 0 {
+		return nil, "", fmt.Errorf("%s defines no instances", path)
+	}
+
+	defaultName := config.Default
+	if defaultName == "" {
+		if len(config.Instances) > 1 {
+			return nil, "", fmt.Errorf("%s defines several instances but no default", path)
+		}
+		for name := range config.Instances {
+			defaultName = name
+		}
+	}
+	if _, ok := config.Instances[defaultName]; !ok {
+		return nil, "", fmt.Errorf("default instance %q is not defined in %s", defaultName, path)
+	}
+
+	clients := make(map[string]*planka.Client, len(config.Instances))
+	for name, instance := range config.Instances {
+		url := os.ExpandEnv(instance.URL)
+		if url == "" {
+			return nil, "", fmt.Errorf("instance %q has no url", name)
+		}
+		url, err := planka.NormalizeBaseURL(url)
+		if err != nil {
+			return nil, "", fmt.Errorf("instance %q: invalid url: %w", name, err)
+		}
+		if probe {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err := planka.NewClient(url, "", opts...).Probe(ctx)
+			cancel()
+			if err != nil {
+				return nil, "", fmt.Errorf("instance %q: %w", name, err)
+			}
+		}
+
+		if token := os.ExpandEnv(instance.Token); token != "" {
+			clients[name] = planka.NewClient(url, token, opts...)
+			continue
+		}
+
+		username := os.ExpandEnv(instance.Username)
+		password := os.ExpandEnv(instance.Password)
+		if username == "" || password == "" {
+			return nil, "", fmt.Errorf("instance %q needs either a token or a username and password", name)
+		}
+		clientOpts := opts
+		if instance.Auth == "cookie" {
+			clientOpts = append(clientOpts[:len(clientOpts):len(clientOpts)], planka.WithCookieAuth())
+		}
+		client, err := planka.NewClientWithPassword(url, username, password, clientOpts...)
+		if err != nil {
+			return nil, "", fmt.Errorf("instance %q: %w", name, err)
+		}
+		clients[name] = client
+	}
+
+	return clients, defaultName, nil
+}
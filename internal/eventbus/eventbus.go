@@ -0,0 +1,72 @@
+// Package eventbus fans out Planka change events, whether pushed over Planka's socket or
+// delivered by its webhooks, to the parts of the server that act on them
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// Bus delivers every published event to all current subscribers
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events published on a Bus until it is closed
+type Subscription struct {
+	// C delivers the events; it is closed by Close
+	C <-chan planka.Event
+
+	bus    *Bus
+	events chan planka.Event
+}
+
+// New creates an empty Bus
+func New() *Bus {
+	return &Bus{subscribers: map[*Subscription]struct{}{}}
+}
+
+// Subscribe returns a subscription buffering up to backlog events. Events published while
+// its buffer is full are dropped for it, so a slow subscriber never holds up the others.
+func (b *Bus) Subscribe(backlog int) *Subscription {
+	events := make(chan planka.Event, backlog)
+	sub := &Subscription{C: events, bus: b, events: events}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Publish delivers event to every subscriber and returns how many had to drop it
+func (b *Bus) Publish(event planka.Event) (dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Subscribers returns the number of current subscriptions
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close ends the subscription and closes C; closing it again does nothing
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subscribers[s]; ok {
+		delete(s.bus.subscribers, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,154 @@
+// Package gitlab is a minimal client for the GitLab REST API, covering what the issue
+// import needs
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultURL is the GitLab instance used when none is configured
+	DefaultURL = "https://gitlab.com"
+	// pageSize is the number of issues fetched per request, GitLab's maximum
+	pageSize = 100
+	// defaultTimeout bounds each request to GitLab
+	defaultTimeout = 30 * time.Second
+)
+
+// Client is a GitLab API client authenticated with a personal, project or group access token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// User is a GitLab user as embedded in issues
+type User struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// Issue is a GitLab issue
+type Issue struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	Labels      []string `json:"labels"`
+	Assignees   []User   `json:"assignees"`
+	// DueDate is a date such as "2024-05-31", or empty
+	DueDate string `json:"due_date"`
+	WebURL  string `json:"web_url"`
+}
+
+// IssueQuery filters the issues returned by ListIssues
+type IssueQuery struct {
+	// State is "opened", "closed" or "all"; empty lists all issues
+	State string
+	// Labels only returns issues that have all of these labels
+	Labels []string
+	// Limit caps the number of issues; zero returns all of them
+	Limit int
+}
+
+// Error is a failed response from GitLab
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("GitLab API error (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("GitLab API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// NewClient creates a GitLab client for the instance at baseURL, e.g. https://gitlab.com
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// ListIssues returns the issues of a project, oldest first. project is the numeric ID
+// or the full path of the project, e.g. "group/project".
+func (c *Client) ListIssues(ctx context.Context, project string, query IssueQuery) ([]Issue, error) {
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(pageSize))
+	params.Set("order_by", "created_at")
+	params.Set("sort", "asc")
+	if query.State != "" && query.State != "all" {
+		params.Set("state", query.State)
+	}
+	if len(query.Labels) > 0 {
+		params.Set("labels", strings.Join(query.Labels, ","))
+	}
+
+	issues := []Issue{}
+	page := "1"
+	for page != "" {
+		params.Set("page", page)
+		endpoint := fmt.Sprintf("/api/v4/projects/%s/issues?%s", url.PathEscape(project), params.Encode())
+		var batch []Issue
+		next, err := c.get(ctx, endpoint, &batch)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+		if query.Limit > 0 && len(issues) >= query.Limit {
+			return issues[:query.Limit], nil
+		}
+		page = next
+	}
+	return issues, nil
+}
+
+// get performs a GET request and returns the next page GitLab announced, if any
+func (c *Client) get(ctx context.Context, endpoint string, result interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach GitLab: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var details struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		json.Unmarshal(body, &details)
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: details.Error}
+		if details.Message != nil {
+			apiErr.Message = fmt.Sprint(details.Message)
+		}
+		return "", apiErr
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return "", fmt.Errorf("failed to decode GitLab response: %w", err)
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
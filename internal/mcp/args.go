@@ -0,0 +1,301 @@
+package mcp
+
+// Tool arguments are declared as structs; their inputSchema is generated from the
+// json tags (fields without omitempty are required) and jsonschema tags (descriptions).
+
+type getProjectsArgs struct {
+	pageArgs
+}
+
+type projectArgs struct {
+	ProjectID string `json:"projectId" jsonschema:"The project ID"`
+}
+
+type createProjectArgs struct {
+	Name        string `json:"name" jsonschema:"The project name"`
+	Description string `json:"description,omitempty" jsonschema:"The project description"`
+}
+
+type boardArgs struct {
+	BoardID string `json:"boardId" jsonschema:"The board ID"`
+}
+
+type getBoardsArgs struct {
+	ProjectID string `json:"projectId" jsonschema:"The project ID"`
+	pageArgs
+}
+
+type findBoardArgs struct {
+	Name      string `json:"name" jsonschema:"The board name to look for; close and partial matches are returned too"`
+	ProjectID string `json:"projectId,omitempty" jsonschema:"Only search the boards of this project"`
+}
+
+type createBoardArgs struct {
+	Name        string `json:"name" jsonschema:"The board name"`
+	Description string `json:"description,omitempty" jsonschema:"The board description"`
+	ProjectID   string `json:"projectId" jsonschema:"The project ID"`
+}
+
+type listArgs struct {
+	ListID string `json:"listId" jsonschema:"The list ID"`
+}
+
+type getListByNameArgs struct {
+	BoardID string `json:"boardId" jsonschema:"The board ID"`
+	Name    string `json:"name" jsonschema:"The list name, e.g. Done or In Progress; matching ignores case and tolerates typos"`
+}
+
+type getListsArgs struct {
+	BoardID string `json:"boardId" jsonschema:"The board ID"`
+	pageArgs
+}
+
+type createListArgs struct {
+	Name     string   `json:"name" jsonschema:"The list name"`
+	BoardID  string   `json:"boardId" jsonschema:"The board ID"`
+	Position *float64 `json:"position,omitempty" jsonschema:"The list position (default: after the board's other lists)"`
+}
+
+type swapListPositionsArgs struct {
+	ListID      string `json:"listId" jsonschema:"The list ID"`
+	OtherListID string `json:"otherListId" jsonschema:"The list to swap places with, on the same board"`
+}
+
+type reorderListsArgs struct {
+	BoardID string   `json:"boardId" jsonschema:"The board ID"`
+	Lists   []string `json:"lists" jsonschema:"The lists in their new order from left to right, by name or ID; lists left out follow in their current order"`
+}
+
+type getCardsArgs struct {
+	ListID string `json:"listId" jsonschema:"The list ID"`
+	pageArgs
+}
+
+type cardArgs struct {
+	CardID string `json:"cardId" jsonschema:"The card ID"`
+}
+
+type getCardArgs struct {
+	CardID  string   `json:"cardId" jsonschema:"The card ID"`
+	Include []string `json:"include,omitempty" enum:"tasks,comments,labels,members,attachments,stopwatch" jsonschema:"Relations to return with the card in the same call: tasks, comments, labels, members, attachments and/or stopwatch"`
+}
+
+type createCardArgs struct {
+	Name        string    `json:"name" jsonschema:"The card name"`
+	Description string    `json:"description,omitempty" jsonschema:"The card description"`
+	ListID      string    `json:"listId" jsonschema:"The list ID"`
+	Position    *position `json:"position,omitempty" jsonschema:"The card position: a number, or top, bottom, after:<cardId> or before:<cardId> (default: bottom)"`
+	DueDate     *dueDate  `json:"dueDate,omitempty" jsonschema:"The due date: an ISO 8601 timestamp or date (e.g. 2025-03-01, due at 17:00), epoch milliseconds, or a phrase such as tomorrow 5pm, next friday or in 3 days; times without an offset are in the server's timezone"`
+}
+
+type updateCardArgs struct {
+	CardID      string    `json:"cardId" jsonschema:"The card ID"`
+	Name        *string   `json:"name,omitempty" jsonschema:"The card name"`
+	Description *string   `json:"description,omitempty" jsonschema:"The card description"`
+	ListID      *string   `json:"listId,omitempty" jsonschema:"The list ID (to move card)"`
+	Position    *position `json:"position,omitempty" jsonschema:"The card position: a number, or top, bottom, after:<cardId> or before:<cardId>"`
+	DueDate     *dueDate  `json:"dueDate,omitempty" jsonschema:"The due date: an ISO 8601 timestamp or date (e.g. 2025-03-01, due at 17:00), epoch milliseconds, or a phrase such as tomorrow 5pm, next friday or in 3 days; times without an offset are in the server's timezone"`
+	ClearFields []string  `json:"clearFields,omitempty" enum:"description,dueDate" jsonschema:"Fields to remove from the card: description and/or dueDate"`
+}
+
+type appendToDescriptionArgs struct {
+	CardID  string `json:"cardId" jsonschema:"The card ID"`
+	Text    string `json:"text" jsonschema:"The Markdown to add"`
+	Section string `json:"section,omitempty" jsonschema:"Add the text at the end of the section with this heading, e.g. Notes, creating it if missing (default: the end of the description)"`
+}
+
+type setDescriptionSectionArgs struct {
+	CardID  string `json:"cardId" jsonschema:"The card ID"`
+	Section string `json:"section" jsonschema:"The heading of the section, e.g. Acceptance Criteria"`
+	// Content is optional in the schema, as required arguments must not be empty; the handler
+	// tells a missing content from an empty one, which removes the section
+	Content *string `json:"content,omitempty" jsonschema:"The new Markdown content of the section (required); an empty string removes the section"`
+}
+
+type moveCardArgs struct {
+	CardID   string    `json:"cardId" jsonschema:"The card ID"`
+	ListID   string    `json:"listId" jsonschema:"The target list ID"`
+	Position *position `json:"position,omitempty" jsonschema:"The card position in the new list: a number, or top, bottom, after:<cardId> or before:<cardId>"`
+}
+
+type moveAllCardsArgs struct {
+	SourceListID string `json:"sourceListId" jsonschema:"The list to move the cards from"`
+	TargetListID string `json:"targetListId" jsonschema:"The list to move the cards to"`
+	Position     string `json:"position,omitempty" enum:"bottom,top" jsonschema:"Where the cards go in the target list, keeping their order: bottom (default) or top"`
+	Label        string `json:"label,omitempty" jsonschema:"Only move cards with this label, by name or ID"`
+	DueFrom      string `json:"dueFrom,omitempty" jsonschema:"Only move cards due on or after this date, e.g. 2024-05-01"`
+	DueTo        string `json:"dueTo,omitempty" jsonschema:"Only move cards due on or before this date, e.g. 2024-05-31"`
+	DryRun       bool   `json:"dryRun,omitempty" jsonschema:"Only report the cards that would be moved"`
+}
+
+type autoSortListArgs struct {
+	ListID     string `json:"listId" jsonschema:"The list ID"`
+	Strategy   string `json:"strategy,omitempty" enum:"dueDate,name,createdAt" jsonschema:"The order: dueDate (default; soonest first, cards without a due date last), name or createdAt (oldest first)"`
+	KeepSorted *bool  `json:"keepSorted,omitempty" jsonschema:"true keeps the list sorted in the background from now on, false stops that (requires --sorted-lists-file)"`
+}
+
+type getTasksArgs struct {
+	CardID string `json:"cardId" jsonschema:"The card ID"`
+	pageArgs
+}
+
+type createTaskArgs struct {
+	Name     string   `json:"name" jsonschema:"The task name"`
+	CardID   string   `json:"cardId" jsonschema:"The card ID"`
+	Position *float64 `json:"position,omitempty" jsonschema:"The task position (default: after the card's other tasks)"`
+}
+
+type taskArgs struct {
+	TaskID string `json:"taskId" jsonschema:"The task ID"`
+}
+
+type updateTaskArgs struct {
+	TaskID      string   `json:"taskId" jsonschema:"The task ID"`
+	Name        *string  `json:"name,omitempty" jsonschema:"The task name"`
+	IsCompleted *bool    `json:"isCompleted,omitempty" jsonschema:"Whether the task is completed"`
+	Position    *float64 `json:"position,omitempty" jsonschema:"The task position"`
+}
+
+type getCommentsArgs struct {
+	CardID string `json:"cardId" jsonschema:"The card ID"`
+	pageArgs
+}
+
+type createCommentArgs struct {
+	Text   string `json:"text" jsonschema:"The comment text"`
+	CardID string `json:"cardId" jsonschema:"The card ID"`
+}
+
+type createCommentsBulkArgs struct {
+	CardIDs []string `json:"cardIds" jsonschema:"The cards to comment on (at most 100)"`
+	Text    string   `json:"text" jsonschema:"The comment text; {{id}}, {{name}}, {{list}} and {{due}} are replaced with each card's ID, name, list name and due date"`
+}
+
+type commentArgs struct {
+	CommentID string `json:"commentId" jsonschema:"The comment ID"`
+}
+
+type logTimeArgs struct {
+	CardID   string `json:"cardId" jsonschema:"The card ID"`
+	Duration string `json:"duration" jsonschema:"The time spent, e.g. 1h30m, 45m or 2.5h; a bare number is minutes"`
+	Date     string `json:"date,omitempty" jsonschema:"The day the work was done, e.g. 2024-05-01 (default: today)"`
+	Note     string `json:"note,omitempty" jsonschema:"What the time was spent on"`
+}
+
+type getTimeEntriesArgs struct {
+	CardID string `json:"cardId" jsonschema:"The card ID"`
+	From   string `json:"from,omitempty" jsonschema:"Only include entries on or after this day, e.g. 2024-05-01"`
+	To     string `json:"to,omitempty" jsonschema:"Only include entries on or before this day, e.g. 2024-05-31"`
+}
+
+type getServerStatsArgs struct{}
+
+type undoLastArgs struct{}
+
+type addRecurrenceArgs struct {
+	ListID      string `json:"listId" jsonschema:"The list the cards are created in"`
+	Name        string `json:"name" jsonschema:"The name of each created card"`
+	Description string `json:"description,omitempty" jsonschema:"The description of each created card"`
+	Schedule    string `json:"schedule" jsonschema:"When to create the card: a cron expression such as 0 9 * * mon, or @hourly, @daily, @weekly, @monthly or @yearly"`
+}
+
+type listRecurrencesArgs struct{}
+
+type recurrenceArgs struct {
+	RecurrenceID string `json:"recurrenceId" jsonschema:"The recurrence ID"`
+}
+
+type addRuleArgs struct {
+	Name                string `json:"name,omitempty" jsonschema:"What the rule is for"`
+	BoardID             string `json:"boardId,omitempty" jsonschema:"Only apply the rule to cards of this board (default: all boards)"`
+	NameContains        string `json:"nameContains,omitempty" jsonschema:"Apply the rule to cards whose name contains this text, ignoring case"`
+	DescriptionContains string `json:"descriptionContains,omitempty" jsonschema:"Apply the rule to cards whose description contains this text, ignoring case"`
+	InListID            string `json:"inListId,omitempty" jsonschema:"Apply the rule to cards in this list"`
+	AddLabel            string `json:"addLabel,omitempty" jsonschema:"The name of a label to add to matching cards; it is created on boards that lack it"`
+	MoveToList          string `json:"moveToList,omitempty" jsonschema:"The name or ID of a list on the card's board to move matching cards to"`
+}
+
+type listRulesArgs struct{}
+
+type ruleArgs struct {
+	RuleID string `json:"ruleId" jsonschema:"The rule ID"`
+}
+
+type cleanupDoneCardsArgs struct {
+	ListIDs       []string `json:"listIds,omitempty" jsonschema:"The done lists to clean up (default: the lists configured on the server)"`
+	OlderThanDays int      `json:"olderThanDays" jsonschema:"Clean up cards unchanged for at least this many days"`
+	Action        string   `json:"action,omitempty" enum:"archive,delete" jsonschema:"archive (default) moves the cards to the board's archive list; delete deletes them"`
+	DryRun        bool     `json:"dryRun,omitempty" jsonschema:"Only report the cards that would be cleaned up"`
+}
+
+type auditBoardArgs struct {
+	BoardID   string `json:"boardId" jsonschema:"The board ID"`
+	StaleDays *int   `json:"staleDays,omitempty" jsonschema:"Report open cards untouched for at least this many days (default: 14)"`
+	formatArgs
+}
+
+type importGitLabIssuesArgs struct {
+	Project   string            `json:"project" jsonschema:"The GitLab project: its numeric ID or full path, e.g. group/project"`
+	ListID    string            `json:"listId" jsonschema:"The list the cards are created in"`
+	State     string            `json:"state,omitempty" enum:"opened,closed,all" jsonschema:"Which issues to import: opened (default), closed or all"`
+	Labels    []string          `json:"labels,omitempty" jsonschema:"Only import issues that have all of these GitLab labels"`
+	Limit     int               `json:"limit,omitempty" jsonschema:"The maximum number of issues to import (default: 100)"`
+	Assignees map[string]string `json:"assignees,omitempty" jsonschema:"Maps GitLab usernames to Planka user IDs or usernames; other assignees are matched to board members by username, then name"`
+	DryRun    bool              `json:"dryRun,omitempty" jsonschema:"Only report the cards that would be created"`
+	Token     string            `json:"token,omitempty" jsonschema:"A GitLab access token with read_api scope (default: the server's GITLAB_TOKEN)"`
+	GitLabURL string            `json:"gitlabUrl,omitempty" jsonschema:"The GitLab instance (default: the server's GITLAB_URL, else https://gitlab.com)"`
+}
+
+type standupReportArgs struct {
+	BoardID         string   `json:"boardId" jsonschema:"The board ID"`
+	LookbackHours   *int     `json:"lookbackHours,omitempty" jsonschema:"How many hours back the report covers (default: 24; use 72 on Mondays to cover the weekend)"`
+	InProgressLists []string `json:"inProgressLists,omitempty" jsonschema:"The lists whose cards are in progress (default: lists named like In Progress, Doing or Review)"`
+	formatArgs
+}
+
+type sprintReportArgs struct {
+	BoardID         string   `json:"boardId" jsonschema:"The board ID"`
+	From            string   `json:"from" jsonschema:"The first day of the sprint, e.g. 2024-05-01, or an RFC 3339 timestamp"`
+	To              string   `json:"to,omitempty" jsonschema:"The last day of the sprint, included (default: now)"`
+	InProgressLists []string `json:"inProgressLists,omitempty" jsonschema:"The lists whose open cards carry over (default: lists named like In Progress, Doing or Review); open cards updated during the sprint carry over too"`
+	ArchiveDone     bool     `json:"archiveDone,omitempty" jsonschema:"Move the cards of the board's done lists to its archive list after reporting"`
+	formatArgs
+}
+
+type getVelocityArgs struct {
+	BoardID string `json:"boardId" jsonschema:"The board ID"`
+	Sprints *int   `json:"sprints,omitempty" jsonschema:"How many of the most recent sprints to report (default: 6)"`
+	formatArgs
+}
+
+type snapshotBoardArgs struct {
+	BoardID string `json:"boardId" jsonschema:"The board ID"`
+	Save    bool   `json:"save,omitempty" jsonschema:"Also save the snapshot on the server, to compare against later by its ID (requires --snapshots-dir)"`
+}
+
+type diffBoardSnapshotsArgs struct {
+	From         string         `json:"from,omitempty" jsonschema:"The ID of the earlier saved snapshot"`
+	FromSnapshot *boardSnapshot `json:"fromSnapshot,omitempty" jsonschema:"The earlier snapshot itself, as returned by snapshot_board"`
+	To           string         `json:"to,omitempty" jsonschema:"The ID of the later saved snapshot (default: the board as it is now)"`
+	ToSnapshot   *boardSnapshot `json:"toSnapshot,omitempty" jsonschema:"The later snapshot itself, as returned by snapshot_board"`
+}
+
+type restoreBoardArgs struct {
+	SnapshotID  string         `json:"snapshotId,omitempty" jsonschema:"The ID of the saved snapshot to restore"`
+	Snapshot    *boardSnapshot `json:"snapshot,omitempty" jsonschema:"The snapshot to restore itself, as returned by snapshot_board"`
+	DeleteAdded bool           `json:"deleteAdded,omitempty" jsonschema:"Also delete the cards added to the board since the snapshot (default: keep them)"`
+	DryRun      bool           `json:"dryRun,omitempty" jsonschema:"Only report the changes a restore would make"`
+}
+
+type watchBoardArgs struct {
+	BoardID        string `json:"boardId" jsonschema:"The board ID"`
+	Since          string `json:"since,omitempty" jsonschema:"Report changes after this RFC 3339 timestamp; pass the cursor of the last call to continue where it left off (default: now)"`
+	TimeoutSeconds *int   `json:"timeoutSeconds,omitempty" jsonschema:"How long to wait for a change, at most 300, or 10 less than the HTTP write timeout (default: 30)"`
+}
+
+type getTimelineArgs struct {
+	BoardID    string `json:"boardId" jsonschema:"The board ID"`
+	StartField string `json:"startField,omitempty" jsonschema:"The custom field holding each card's start date, e.g. Start date (default: cards start when they were created)"`
+	From       string `json:"from,omitempty" jsonschema:"Only include cards scheduled on or after this date, e.g. 2024-05-01"`
+	To         string `json:"to,omitempty" jsonschema:"Only include cards scheduled on or before this date, e.g. 2024-06-30"`
+}
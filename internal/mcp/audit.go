@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+const (
+	// defaultStaleDays is how long a card must be untouched to be reported as stale
+	defaultStaleDays = 14
+	// duplicateScore is the similarity at which two card titles look like duplicates
+	duplicateScore = 0.85
+)
+
+// auditCard identifies a card reported by audit_board
+type auditCard struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ListID   string `json:"listId"`
+	ListName string `json:"listName"`
+	// IdleDays is how many days the card has been untouched; only set for stale cards
+	IdleDays int `json:"idleDays,omitempty"`
+}
+
+// auditList identifies a list reported by audit_board
+type auditList struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// duplicateCards are cards whose titles look alike
+type duplicateCards struct {
+	Cards []auditCard `json:"cards"`
+	Score float64     `json:"score"`
+}
+
+// boardAudit is the hygiene report of audit_board
+type boardAudit struct {
+	BoardID     string           `json:"boardId"`
+	BoardName   string           `json:"boardName"`
+	EmptyLists  []auditList      `json:"emptyLists"`
+	NoDueDate   []auditCard      `json:"noDueDate"`
+	Unassigned  []auditCard      `json:"unassigned"`
+	Stale       []auditCard      `json:"stale"`
+	Duplicates  []duplicateCards `json:"duplicates"`
+	Suggestions []string         `json:"suggestions"`
+	// Skipped names the checks that could not run, with the reason
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+func (s *Server) handleAuditBoard(ctx context.Context, args auditBoardArgs) (interface{}, error) {
+	if err := args.formatArgs.validate(); err != nil {
+		return nil, err
+	}
+	staleDays := defaultStaleDays
+	if args.StaleDays != nil {
+		if *args.StaleDays < 1 {
+			return nil, invalidParams("staleDays must be at least 1")
+		}
+		staleDays = *args.StaleDays
+	}
+
+	client := s.clientFor(ctx)
+	board, err := client.GetBoard(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := client.GetCardMemberships(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := s.auditBoard(board, lists, cards, memberships, staleDays, time.Now())
+	return formatReport(args.Format, audit, audit.document(client)), nil
+}
+
+// auditBoard checks the lists and cards of board. Cards in finished lists (closed and
+// archive lists, configured done lists and lists named Done or Archive) need neither a
+// due date nor an assignee and are never stale. A nil memberships skips the assignee check.
+func (s *Server) auditBoard(board *planka.Board, lists []planka.List, cards []planka.Card, memberships []planka.CardMembership, staleDays int, now time.Time) boardAudit {
+	report := boardAudit{
+		BoardID:     board.ID,
+		BoardName:   board.Name,
+		EmptyLists:  []auditList{},
+		NoDueDate:   []auditCard{},
+		Unassigned:  []auditCard{},
+		Stale:       []auditCard{},
+		Duplicates:  []duplicateCards{},
+		Suggestions: []string{},
+	}
+
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Position < lists[j].Position })
+	listNames := map[string]string{}
+	finished := map[string]bool{}
+	counts := map[string]int{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+		finished[list.ID] = s.isFinishedList(list)
+	}
+	for _, card := range cards {
+		counts[card.ListID]++
+	}
+	assigned := map[string]bool{}
+	for _, membership := range memberships {
+		assigned[membership.CardID] = true
+	}
+
+	for _, list := range lists {
+		if counts[list.ID] == 0 && list.Type != "archive" && list.Type != "trash" {
+			report.EmptyLists = append(report.EmptyLists, auditList{ID: list.ID, Name: list.Name})
+		}
+	}
+
+	sort.Slice(cards, func(i, j int) bool { return cards[i].UpdatedAt.Before(cards[j].UpdatedAt) })
+	for _, card := range cards {
+		if finished[card.ListID] {
+			continue
+		}
+		entry := auditCard{ID: card.ID, Name: card.Name, ListID: card.ListID, ListName: listNames[card.ListID]}
+		if card.DueDate == nil {
+			report.NoDueDate = append(report.NoDueDate, entry)
+		}
+		if memberships != nil && !assigned[card.ID] {
+			report.Unassigned = append(report.Unassigned, entry)
+		}
+		if idle := int(now.Sub(card.UpdatedAt).Hours() / 24); idle >= staleDays {
+			entry.IdleDays = idle
+			report.Stale = append(report.Stale, entry)
+		}
+	}
+	if memberships == nil {
+		report.Skipped = append(report.Skipped, "unassigned: Planka did not include card memberships in the board")
+	}
+
+	// Titles are compared pairwise, so each card joins at most one group of look-alikes
+	grouped := map[string]bool{}
+	for i, card := range cards {
+		if grouped[card.ID] {
+			continue
+		}
+		group := duplicateCards{Cards: []auditCard{{ID: card.ID, Name: card.Name, ListID: card.ListID, ListName: listNames[card.ListID]}}, Score: 1}
+		for _, other := range cards[i+1:] {
+			if grouped[other.ID] {
+				continue
+			}
+			if score := nameSimilarity(card.Name, other.Name); score >= duplicateScore {
+				group.Cards = append(group.Cards, auditCard{ID: other.ID, Name: other.Name, ListID: other.ListID, ListName: listNames[other.ListID]})
+				group.Score = min(group.Score, score)
+				grouped[other.ID] = true
+			}
+		}
+		if len(group.Cards) > 1 {
+			report.Duplicates = append(report.Duplicates, group)
+		}
+	}
+
+	report.Suggestions = auditSuggestions(report, staleDays)
+	return report
+}
+
+// isFinishedList reports whether the cards of list are done with
+func (s *Server) isFinishedList(list planka.List) bool {
+	switch {
+	case list.Type == "closed" || list.Type == "archive" || list.Type == "trash":
+		return true
+	case slices.Contains(s.doneLists, list.ID):
+		return true
+	default:
+		return strings.EqualFold(list.Name, "done") || strings.EqualFold(list.Name, archiveListName)
+	}
+}
+
+// auditSuggestions turns the findings of report into actions the model can take
+func auditSuggestions(report boardAudit, staleDays int) []string {
+	suggestions := []string{}
+	if n := len(report.EmptyLists); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%s empty: delete the ones no longer used with delete_list", plural(n, "list is", "lists are")))
+	}
+	if n := len(report.NoDueDate); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%s no due date: set one with update_card so deadlines are visible", plural(n, "open card has", "open cards have")))
+	}
+	if n := len(report.Unassigned); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%s no assignee: assign an owner in Planka", plural(n, "open card has", "open cards have")))
+	}
+	if n := len(report.Stale); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%s not changed for %d days or more: check whether they are still relevant, then update, move or archive them", plural(n, "card has", "cards have"), staleDays))
+	}
+	for _, group := range report.Duplicates {
+		ids := make([]string, len(group.Cards))
+		for i, card := range group.Cards {
+			ids[i] = card.ID
+		}
+		suggestions = append(suggestions, fmt.Sprintf("cards %s look like duplicates of %q: merge them and delete the extras with delete_card", strings.Join(ids, ", "), group.Cards[0].Name))
+	}
+	return suggestions
+}
+
+// document lays out the audit for the markdown and slack formats
+func (a boardAudit) document(client PlankaClient) report {
+	cardItems := func(cards []auditCard, detail func(auditCard) string) []reportItem {
+		items := make([]reportItem, len(cards))
+		for i, card := range cards {
+			items[i] = reportItem{Text: card.Name, URL: cardURL(client, card.ID), Detail: detail(card)}
+		}
+		return items
+	}
+	inList := func(card auditCard) string { return "in " + card.ListName }
+
+	doc := report{Title: "Board audit: " + a.BoardName, Empty: "No issues found."}
+	lists := make([]reportItem, len(a.EmptyLists))
+	for i, list := range a.EmptyLists {
+		lists[i] = reportItem{Text: list.Name}
+	}
+	duplicates := make([]reportItem, len(a.Duplicates))
+	for i, group := range a.Duplicates {
+		names := make([]string, len(group.Cards))
+		for j, card := range group.Cards {
+			names[j] = card.Name
+		}
+		duplicates[i] = reportItem{Text: strings.Join(names, " / "), Detail: fmt.Sprintf("%.0f%% alike", group.Score*100)}
+	}
+	suggestions := make([]reportItem, len(a.Suggestions))
+	for i, suggestion := range a.Suggestions {
+		suggestions[i] = reportItem{Text: suggestion}
+	}
+	skipped := make([]reportItem, len(a.Skipped))
+	for i, reason := range a.Skipped {
+		skipped[i] = reportItem{Text: reason}
+	}
+
+	doc.Sections = []reportSection{
+		{Heading: "Empty lists", Items: lists},
+		{Heading: "No due date", Items: cardItems(a.NoDueDate, inList)},
+		{Heading: "Unassigned", Items: cardItems(a.Unassigned, inList)},
+		{Heading: "Stale", Items: cardItems(a.Stale, func(card auditCard) string {
+			return fmt.Sprintf("in %s, untouched for %d days", card.ListName, card.IdleDays)
+		})},
+		{Heading: "Possible duplicates", Items: duplicates},
+		{Heading: "Suggestions", Items: suggestions},
+		{Heading: "Checks skipped", Items: skipped},
+	}
+	return doc
+}
+
+// plural formats n with the singular or plural form of a phrase
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
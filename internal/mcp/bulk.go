@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// maxBulkCards caps the cards a single bulk call acts on
+const maxBulkCards = 100
+
+// commentPlaceholderPattern matches the placeholders of a comment template, e.g. {{name}}
+var commentPlaceholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z]+)\s*\}\}`)
+
+// commentPlaceholders are the placeholders comment templates may use
+var commentPlaceholders = map[string]bool{"id": true, "name": true, "list": true, "due": true}
+
+// bulkCommentResult is the outcome of commenting on one card
+type bulkCommentResult struct {
+	CardID    string `json:"cardId"`
+	CommentID string `json:"commentId,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// bulkCommentsResult is the result of create_comments_bulk
+type bulkCommentsResult struct {
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Results []bulkCommentResult `json:"results"`
+}
+
+func (s *Server) handleCreateCommentsBulk(ctx context.Context, args createCommentsBulkArgs) (interface{}, error) {
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, invalidParams("text must not be empty")
+	}
+	cardIDs := uniqueStrings(args.CardIDs)
+	if len(cardIDs) == 0 {
+		return nil, invalidParams("cardIds must name at least one card")
+	}
+	if len(cardIDs) > maxBulkCards {
+		return nil, invalidParams("cardIds names %d cards; at most %d are allowed per call", len(cardIDs), maxBulkCards)
+	}
+	// Cards and lists are only fetched if the template needs them
+	needsCard, needsList := false, false
+	for _, m := range commentPlaceholderPattern.FindAllStringSubmatch(args.Text, -1) {
+		name := strings.ToLower(m[1])
+		if !commentPlaceholders[name] {
+			return nil, invalidParams("unknown placeholder %s in text; use {{id}}, {{name}}, {{list}} or {{due}}", m[0])
+		}
+		needsCard = needsCard || name != "id"
+		needsList = needsList || name == "list"
+	}
+
+	client := s.clientFor(ctx)
+	result := bulkCommentsResult{Results: []bulkCommentResult{}}
+	listNames := map[string]string{}
+	for _, cardID := range cardIDs {
+		entry := bulkCommentResult{CardID: cardID}
+		card := &planka.Card{ID: cardID}
+		var err error
+		if needsCard {
+			card, err = client.GetCard(ctx, cardID)
+		}
+		if err == nil && needsList {
+			if _, ok := listNames[card.ListID]; !ok {
+				var list *planka.List
+				if list, err = client.GetList(ctx, card.ListID); err == nil {
+					listNames[card.ListID] = list.Name
+				}
+			}
+		}
+		if err == nil {
+			entry.Text = s.renderComment(args.Text, card, listNames[card.ListID])
+			var comment *planka.Comment
+			if comment, err = client.CreateComment(ctx, planka.CreateCommentRequest{Text: entry.Text, CardID: cardID}); err == nil {
+				entry.CommentID = comment.ID
+			}
+		}
+
+		if err != nil {
+			entry.Error = describeError(err).Error()
+			result.Failed++
+		} else {
+			result.Created++
+		}
+		result.Results = append(result.Results, entry)
+	}
+	if result.Created > 0 {
+		s.recordUndo(ctx, "create_comments_bulk", fmt.Sprintf("%d created comments", result.Created), func(ctx context.Context) error {
+			for _, entry := range result.Results {
+				if entry.CommentID == "" {
+					continue
+				}
+				if err := client.DeleteComment(ctx, entry.CommentID); err != nil {
+					return fmt.Errorf("comment %s: %w", entry.CommentID, err)
+				}
+			}
+			return nil
+		})
+	}
+	return result, nil
+}
+
+// renderComment fills in the placeholders of a comment template for card
+func (s *Server) renderComment(template string, card *planka.Card, listName string) string {
+	return commentPlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		switch strings.ToLower(commentPlaceholderPattern.FindStringSubmatch(placeholder)[1]) {
+		case "id":
+			return card.ID
+		case "name":
+			return card.Name
+		case "list":
+			return listName
+		case "due":
+			if card.DueDate == nil {
+				return "no due date"
+			}
+			return card.DueDate.In(s.location()).Format("Jan 2, 2006 15:04")
+		}
+		return placeholder
+	})
+}
+
+// uniqueStrings returns values without empty strings and repeats, in their first order
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
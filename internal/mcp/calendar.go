@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// icalTime is the UTC date-time format of iCalendar
+const icalTime = "20060102T150405Z"
+
+// handleCalendar serves the due dates of a board's cards as an iCalendar feed at
+// /calendar/{boardId}.ics. Calendar apps cannot send headers, so the token may also
+// be passed as ?token=.
+func (h *httpServer) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = requestAPIKey(r)
+	}
+	if !h.validCalendarToken(token) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	boardID, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok || boardID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	client := h.server.client
+	board, err := client.GetBoard(r.Context(), boardID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+	lists, err := client.GetLists(r.Context(), boardID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+	cards, err := client.GetBoardCards(r.Context(), boardID)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	baseURL := ""
+	if c, ok := client.(interface{ BaseURL() string }); ok {
+		baseURL = c.BaseURL()
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", boardID+".ics"))
+	w.Write(renderCalendar(board, lists, cards, baseURL, time.Now()))
+}
+
+// validCalendarToken reports whether token is one of the configured calendar tokens
+func (h *httpServer) validCalendarToken(token string) bool {
+	valid := false
+	for _, calendarToken := range h.calendarTokens {
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(calendarToken)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// writeCalendarError reports a failure to fetch a board, keeping Planka's not found status
+func writeCalendarError(w http.ResponseWriter, err error) {
+	var apiErr *planka.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		http.Error(w, "Board not found", http.StatusNotFound)
+		return
+	}
+	log.Printf("Calendar feed: %v", err)
+	http.Error(w, "Failed to fetch the board from Planka", http.StatusBadGateway)
+}
+
+// renderCalendar renders the cards of board that have a due date as iCalendar events
+// (RFC 5545), one per card. Events start and end at the due date; their description
+// names the list and carries the card description.
+func renderCalendar(board *planka.Board, lists []planka.List, cards []planka.Card, baseURL string, now time.Time) []byte {
+	listNames := map[string]string{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].DueDate == nil || cards[j].DueDate == nil {
+			return cards[i].DueDate != nil
+		}
+		return cards[i].DueDate.Before(*cards[j].DueDate)
+	})
+
+	var buf bytes.Buffer
+	line := func(name, value string) { writeICalLine(&buf, name+":"+value) }
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//planka-mcp//Planka due dates//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICalText(board.Name))
+	for _, card := range cards {
+		if card.DueDate == nil {
+			continue
+		}
+		due := card.DueDate.UTC().Format(icalTime)
+		stamp := card.UpdatedAt
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		description := "List: " + listNames[card.ListID]
+		if card.Description != "" {
+			description += "\n\n" + card.Description
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", "card-"+card.ID+"@planka-mcp")
+		line("DTSTAMP", stamp.UTC().Format(icalTime))
+		line("DTSTART", due)
+		line("DTEND", due)
+		line("SUMMARY", escapeICalText(card.Name))
+		line("DESCRIPTION", escapeICalText(description))
+		if baseURL != "" {
+			line("URL", baseURL+"/cards/"+card.ID)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeICalText escapes a TEXT value: backslashes, semicolons, commas and newlines
+func escapeICalText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// writeICalLine writes a content line terminated by CRLF, folding it into lines of at
+// most 75 octets without splitting UTF-8 characters
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// isRuneStart reports whether b is not a UTF-8 continuation byte
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
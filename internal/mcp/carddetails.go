@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// Relations get_card can include with a card
+const (
+	includeTasks       = "tasks"
+	includeComments    = "comments"
+	includeLabels      = "labels"
+	includeMembers     = "members"
+	includeAttachments = "attachments"
+	includeStopwatch   = "stopwatch"
+)
+
+// cardDetails is a card with the relations get_card's include argument asked for.
+// Relations that were not asked for, or are empty, are left out.
+type cardDetails struct {
+	planka.Card
+	Members     []planka.User       `json:"members,omitempty"`
+	Attachments []planka.Attachment `json:"attachments,omitempty"`
+	Stopwatch   *planka.Stopwatch   `json:"stopwatch,omitempty"`
+	// CommentsTruncated is set when the card has more comments than the server fetches,
+	// so only the newest are included
+	CommentsTruncated bool `json:"commentsTruncated,omitempty"`
+}
+
+// getCardDetails returns cardID with the relations in include. Tasks, labels, members and
+// attachments come from the card's included payload and its board, which the client
+// usually holds already; comments and the stopwatch take a request each.
+func getCardDetails(ctx context.Context, client PlankaClient, cardID string, include []string) (*cardDetails, error) {
+	details, err := client.GetCardDetails(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	result := &cardDetails{Card: details.Card}
+	included := details.Included
+
+	// boardID is the card's board, looked up through its list for Planka versions not
+	// sending it with the card
+	boardID := details.BoardID
+	if boardID == "" && (slices.Contains(include, includeLabels) || slices.Contains(include, includeMembers)) {
+		list, err := client.GetList(ctx, details.Card.ListID)
+		if err != nil {
+			return nil, err
+		}
+		boardID = list.BoardID
+	}
+
+	if slices.Contains(include, includeTasks) {
+		result.Tasks = included.Tasks
+		if result.Tasks == nil {
+			if result.Tasks, err = client.GetTasks(ctx, cardID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if slices.Contains(include, includeComments) {
+		comments, err := client.GetComments(ctx, cardID)
+		if err != nil && !errors.Is(err, planka.ErrTruncated) {
+			return nil, err
+		}
+		result.Comments = comments
+		result.CommentsTruncated = err != nil
+	}
+	if slices.Contains(include, includeLabels) {
+		cardLabels := included.CardLabels
+		if cardLabels == nil {
+			if cardLabels, err = client.GetCardLabels(ctx, boardID); err != nil {
+				return nil, err
+			}
+		}
+		labels, err := client.GetLabels(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		for _, cardLabel := range cardLabels {
+			if cardLabel.CardID != cardID {
+				continue
+			}
+			if i := slices.IndexFunc(labels, func(l planka.Label) bool { return l.ID == cardLabel.LabelID }); i >= 0 {
+				result.Labels = append(result.Labels, labels[i])
+			}
+		}
+	}
+	if slices.Contains(include, includeMembers) {
+		memberships := included.CardMemberships
+		if memberships == nil {
+			if memberships, err = client.GetCardMemberships(ctx, boardID); err != nil {
+				return nil, err
+			}
+		}
+		users, err := client.GetBoardUsers(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		for _, membership := range memberships {
+			if membership.CardID != cardID {
+				continue
+			}
+			if i := slices.IndexFunc(users, func(u planka.User) bool { return u.ID == membership.UserID }); i >= 0 {
+				result.Members = append(result.Members, users[i])
+			} else {
+				// A member who left the board is still shown by ID
+				result.Members = append(result.Members, planka.User{ID: membership.UserID})
+			}
+		}
+	}
+	if slices.Contains(include, includeAttachments) {
+		result.Attachments = included.Attachments
+	}
+	if slices.Contains(include, includeStopwatch) {
+		if result.Stopwatch, err = client.GetStopwatch(ctx, cardID); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// archiveListName is the name of the list done cards are archived to on boards
+// without a Planka archive list
+const archiveListName = "Archive"
+
+// CleanupOptions configures the background cleanup of stale done cards
+type CleanupOptions struct {
+	// OlderThan is how long a card must have been unchanged to be cleaned up
+	OlderThan time.Duration
+	// Delete deletes stale cards instead of archiving them
+	Delete bool
+	// Interval is how often the done lists are checked
+	Interval time.Duration
+}
+
+// cleanedCard is a card cleaned up, or that would be in a dry run
+type cleanedCard struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ListID    string    `json:"listId"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// ArchiveListID is the list the card is archived to
+	ArchiveListID string `json:"archiveListId,omitempty"`
+}
+
+// cleanupResult reports the outcome of cleanup_done_cards
+type cleanupResult struct {
+	DryRun bool          `json:"dryRun"`
+	Action string        `json:"action"`
+	Cards  []cleanedCard `json:"cards"`
+	// Errors describes cards or lists that could not be cleaned up
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SetDoneLists sets the lists whose stale cards cleanup_done_cards and the background
+// cleanup archive or delete when no lists are given
+func (s *Server) SetDoneLists(listIDs []string) {
+	s.doneLists = listIDs
+}
+
+// StartCleanup starts a background job that archives or deletes the cards of the done
+// lists that have not changed for opts.OlderThan, every opts.Interval. It stops when ctx
+// is cancelled.
+func (s *Server) StartCleanup(ctx context.Context, opts CleanupOptions) {
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result := s.cleanupDoneCards(ctx, s.client, s.doneLists, time.Now().Add(-opts.OlderThan), opts.Delete, false)
+				for _, msg := range result.Errors {
+					log.Printf("Done card cleanup: %s", msg)
+				}
+				if len(result.Cards) > 0 {
+					log.Printf("Done card cleanup: %s %d cards", pastTense(result.Action), len(result.Cards))
+				}
+			}
+		}
+	}()
+}
+
+func (s *Server) handleCleanupDoneCards(ctx context.Context, args cleanupDoneCardsArgs) (interface{}, error) {
+	listIDs := args.ListIDs
+	if len(listIDs) == 0 {
+		listIDs = s.doneLists
+	}
+	if len(listIDs) == 0 {
+		return nil, invalidParams("listIds is required when the server has no done lists configured")
+	}
+	if args.Action != "" && args.Action != "archive" && args.Action != "delete" {
+		return nil, invalidParams("action must be archive or delete")
+	}
+	if args.OlderThanDays < 0 {
+		return nil, invalidParams("olderThanDays must not be negative")
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -args.OlderThanDays)
+	return s.cleanupDoneCards(ctx, s.clientFor(ctx), listIDs, cutoff, args.Action == "delete", args.DryRun), nil
+}
+
+// cleanupDoneCards archives, or deletes, the cards in listIDs last updated before cutoff.
+// A dry run only reports the cards. Failures are collected in the result so one bad card
+// or list does not stop the others from being cleaned up.
+func (s *Server) cleanupDoneCards(ctx context.Context, client PlankaClient, listIDs []string, cutoff time.Time, remove, dryRun bool) cleanupResult {
+	result := cleanupResult{DryRun: dryRun, Action: "archive", Cards: []cleanedCard{}}
+	if remove {
+		result.Action = "delete"
+	}
+
+	for _, listID := range listIDs {
+		cards, err := client.GetCards(ctx, listID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("list %s: %v", listID, err))
+			continue
+		}
+		var stale []planka.Card
+		for _, card := range cards {
+			if card.UpdatedAt.Before(cutoff) {
+				stale = append(stale, card)
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+
+		var archiveListID string
+		var next float64
+		if !remove {
+			if archiveListID, err = findArchiveList(ctx, client, listID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("list %s: %v", listID, err))
+				continue
+			}
+			if !dryRun {
+				pos, err := resolvePosition(ctx, client, &position{keyword: "bottom"}, archiveListID, "")
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("list %s: %v", listID, err))
+					continue
+				}
+				next = *pos
+			}
+		}
+
+		for _, card := range stale {
+			if !dryRun {
+				if remove {
+					err = client.DeleteCard(ctx, card.ID)
+				} else {
+					_, err = client.MoveCard(ctx, card.ID, archiveListID, next)
+					next += planka.PositionGap
+				}
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID, err))
+					continue
+				}
+			}
+			result.Cards = append(result.Cards, cleanedCard{
+				ID:            card.ID,
+				Name:          card.Name,
+				ListID:        listID,
+				UpdatedAt:     card.UpdatedAt,
+				ArchiveListID: archiveListID,
+			})
+		}
+	}
+	return result
+}
+
+// findArchiveList returns the list cards of listID's board are archived to: Planka's
+// archive list, or else a list named "Archive"
+func findArchiveList(ctx context.Context, client PlankaClient, listID string) (string, error) {
+	list, err := client.GetList(ctx, listID)
+	if err != nil {
+		return "", err
+	}
+	lists, err := client.GetLists(ctx, list.BoardID)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range lists {
+		if candidate.Type == "archive" {
+			return candidate.ID, nil
+		}
+	}
+	for _, candidate := range lists {
+		if strings.EqualFold(candidate.Name, archiveListName) && candidate.ID != listID {
+			return candidate.ID, nil
+		}
+	}
+	return "", fmt.Errorf("board %s has no archive list; create a list named %q or use action delete", list.BoardID, archiveListName)
+}
+
+// pastTense returns the log wording of a cleanup action
+func pastTense(action string) string {
+	if action == "delete" {
+		return "deleted"
+	}
+	return "archived"
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// PlankaClient is the Planka API the tools act through. *planka.Client implements it
+// against a live server; tests can substitute a fake such as plankamock.Client.
+type PlankaClient interface {
+	GetMe(ctx context.Context) (*planka.User, error)
+	GetProjects(ctx context.Context) ([]planka.Project, error)
+	GetProject(ctx context.Context, projectID string) (*planka.Project, error)
+	CreateProject(ctx context.Context, req planka.CreateProjectRequest) (*planka.Project, error)
+	DeleteProject(ctx context.Context, projectID string) error
+	GetBoards(ctx context.Context, projectID string) ([]planka.Board, error)
+	GetBoard(ctx context.Context, boardID string) (*planka.Board, error)
+	CreateBoard(ctx context.Context, req planka.CreateBoardRequest) (*planka.Board, error)
+	DeleteBoard(ctx context.Context, boardID string) error
+	GetLists(ctx context.Context, boardID string) ([]planka.List, error)
+	GetLabels(ctx context.Context, boardID string) ([]planka.Label, error)
+	GetBoardCards(ctx context.Context, boardID string) ([]planka.Card, error)
+	GetCardMemberships(ctx context.Context, boardID string) ([]planka.CardMembership, error)
+	GetCardLabels(ctx context.Context, boardID string) ([]planka.CardLabel, error)
+	GetCustomFields(ctx context.Context, boardID string) ([]planka.CustomField, []planka.CustomFieldValue, error)
+	GetBoardUsers(ctx context.Context, boardID string) ([]planka.User, error)
+	CreateLabel(ctx context.Context, req planka.CreateLabelRequest) (*planka.Label, error)
+	AddCardLabel(ctx context.Context, cardID, labelID string) error
+	AddCardMember(ctx context.Context, cardID, userID string) error
+	GetList(ctx context.Context, listID string) (*planka.List, error)
+	CreateList(ctx context.Context, req planka.CreateListRequest) (*planka.List, error)
+	UpdateList(ctx context.Context, listID string, req planka.UpdateListRequest) (*planka.List, error)
+	DeleteList(ctx context.Context, listID string) error
+	GetCards(ctx context.Context, listID string) ([]planka.Card, error)
+	GetCard(ctx context.Context, cardID string) (*planka.Card, error)
+	GetCardDetails(ctx context.Context, cardID string) (*planka.CardDetails, error)
+	CreateCard(ctx context.Context, req planka.CreateCardRequest) (*planka.Card, error)
+	UpdateCard(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error)
+	DeleteCard(ctx context.Context, cardID string) error
+	MoveCard(ctx context.Context, cardID, listID string, position float64) (*planka.Card, error)
+	GetTasks(ctx context.Context, cardID string) ([]planka.Task, error)
+	CreateTask(ctx context.Context, req planka.CreateTaskRequest) (*planka.Task, error)
+	UpdateTask(ctx context.Context, taskID string, req planka.UpdateTaskRequest) (*planka.Task, error)
+	DeleteTask(ctx context.Context, taskID string) error
+	GetComments(ctx context.Context, cardID string) ([]planka.Comment, error)
+	CreateComment(ctx context.Context, req planka.CreateCommentRequest) (*planka.Comment, error)
+	DeleteComment(ctx context.Context, commentID string) error
+	GetStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	StartStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	StopStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	ResetStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	SetStopwatchDuration(ctx context.Context, cardID string, duration int64) (*planka.Stopwatch, error)
+}
+
+var _ PlankaClient = (*planka.Client)(nil)
@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCompletionValues is the maximum number of suggestions a completion response may carry
+const maxCompletionValues = 100
+
+// completionCandidate is a suggestion for an argument value along with the text it is matched on
+type completionCandidate struct {
+	value string
+	label string
+}
+
+// handleComplete handles completion/complete requests.
+// Suggestions are looked up live in Planka based on the argument being completed:
+// projectId, boardId and listId complete to IDs matched by name, label names to label names
+// and instance to the configured Planka instances.
+func (s *Server) handleComplete(ctx context.Context, req *mcpsdk.CompleteRequest) (*mcpsdk.CompleteResult, error) {
+	// Previously resolved arguments narrow the search, e.g. boardId scopes list and label suggestions
+	var resolved map[string]string
+	if req.Params.Context != nil {
+		resolved = req.Params.Context.Arguments
+	}
+	if name := resolved[instanceArgument]; name != "" && s.instances[name] != nil {
+		ctx = context.WithValue(ctx, instanceKey{}, name)
+	}
+
+	candidates, err := s.completionCandidates(ctx, req.Params.Argument.Name, resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch completions: %w", err)
+	}
+
+	values := matchCompletions(candidates, req.Params.Argument.Value)
+	total := len(values)
+	hasMore := false
+	if total > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		hasMore = true
+	}
+
+	return &mcpsdk.CompleteResult{
+		Completion: mcpsdk.CompletionResultDetails{
+			Values:  values,
+			Total:   total,
+			HasMore: hasMore,
+		},
+	}, nil
+}
+
+// completionCandidates fetches the possible values of an argument from Planka
+func (s *Server) completionCandidates(ctx context.Context, argument string, resolved map[string]string) ([]completionCandidate, error) {
+	client := s.clientFor(ctx)
+	projectID := resolved["projectId"]
+	boardID := resolved["boardId"]
+
+	switch argument {
+	case instanceArgument:
+		candidates := make([]completionCandidate, 0, len(s.instances))
+		for _, name := range s.instanceNames() {
+			candidates = append(candidates, completionCandidate{value: name, label: name})
+		}
+		return candidates, nil
+
+	case "projectId":
+		projects, err := client.GetProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]completionCandidate, 0, len(projects))
+		for _, project := range projects {
+			candidates = append(candidates, completionCandidate{value: project.ID, label: project.Name})
+		}
+		return candidates, nil
+
+	case "boardId":
+		projectIDs := []string{projectID}
+		if projectID == "" {
+			projects, err := client.GetProjects(ctx)
+			if err != nil {
+				return nil, err
+			}
+			projectIDs = projectIDs[:0]
+			for _, project := range projects {
+				projectIDs = append(projectIDs, project.ID)
+			}
+		}
+		var candidates []completionCandidate
+		for _, id := range projectIDs {
+			boards, err := client.GetBoards(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			for _, board := range boards {
+				candidates = append(candidates, completionCandidate{value: board.ID, label: board.Name})
+			}
+		}
+		return candidates, nil
+
+	case "listId":
+		if boardID == "" {
+			return nil, nil
+		}
+		lists, err := client.GetLists(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]completionCandidate, 0, len(lists))
+		for _, list := range lists {
+			candidates = append(candidates, completionCandidate{value: list.ID, label: list.Name})
+		}
+		return candidates, nil
+
+	case "label", "labelName":
+		if boardID == "" {
+			return nil, nil
+		}
+		labels, err := client.GetLabels(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]completionCandidate, 0, len(labels))
+		for _, label := range labels {
+			candidates = append(candidates, completionCandidate{value: label.Name, label: label.Name})
+		}
+		return candidates, nil
+	}
+
+	return nil, nil
+}
+
+// matchCompletions returns the values of the candidates whose label or value matches the typed prefix.
+// Prefix matches on the label rank first, followed by substring matches.
+func matchCompletions(candidates []completionCandidate, typed string) []string {
+	typed = strings.ToLower(typed)
+
+	type match struct {
+		value string
+		label string
+		rank  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		label := strings.ToLower(candidate.label)
+		switch {
+		case strings.HasPrefix(label, typed), strings.HasPrefix(candidate.value, typed):
+			matches = append(matches, match{candidate.value, label, 0})
+		case strings.Contains(label, typed):
+			matches = append(matches, match{candidate.value, label, 1})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].label < matches[j].label
+	})
+
+	values := make([]string, 0, len(matches))
+	for _, m := range matches {
+		values = append(values, m.value)
+	}
+	return values
+}
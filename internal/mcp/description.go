@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// sectionHeadingPattern matches a Markdown ATX heading such as "## Acceptance Criteria"
+var sectionHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+
+// descriptionSection locates a section of a card description: the lines from its heading
+// up to the next heading of the same or a higher level
+type descriptionSection struct {
+	// heading, body and end are line indexes: the heading line, the first line after it
+	// and the first line after the section
+	heading, body, end int
+}
+
+// findSection returns the section of lines whose heading is name, ignoring case and a
+// trailing colon. Headings inside fenced code blocks do not count.
+func findSection(lines []string, name string) (descriptionSection, bool) {
+	name = normalizeHeading(name)
+	found := descriptionSection{heading: -1}
+	level := 0
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		m := sectionHeadingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if found.heading >= 0 && len(m[1]) <= level {
+			found.end = i
+			return found, true
+		}
+		if found.heading < 0 && normalizeHeading(m[2]) == name {
+			found = descriptionSection{heading: i, body: i + 1}
+			level = len(m[1])
+		}
+	}
+	if found.heading < 0 {
+		return found, false
+	}
+	found.end = len(lines)
+	return found, true
+}
+
+// normalizeHeading reduces a heading to what section names are compared by
+func normalizeHeading(heading string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(heading), ":"))
+}
+
+// setSection replaces the content of the section name of description with content, or
+// adds the section at the end if there is none. An empty content removes the section.
+func setSection(description, name, content string) string {
+	lines := splitLines(description)
+	content = strings.TrimSpace(content)
+	section, ok := findSection(lines, name)
+	if !ok {
+		if content == "" {
+			return description
+		}
+		return joinBlocks(description, "## "+strings.TrimSpace(name)+"\n\n"+content)
+	}
+
+	var replaced []string
+	replaced = append(replaced, lines[:section.heading]...)
+	if content != "" {
+		replaced = append(replaced, lines[section.heading], "")
+		replaced = append(replaced, strings.Split(content, "\n")...)
+		if section.end < len(lines) {
+			replaced = append(replaced, "")
+		}
+	}
+	replaced = append(replaced, lines[section.end:]...)
+	return joinLines(replaced)
+}
+
+// appendToSection adds text at the end of the section name of description, creating the
+// section at the end if there is none. Without a name text is added at the end.
+func appendToSection(description, name, text string) string {
+	text = strings.TrimSpace(text)
+	if name == "" {
+		return joinBlocks(description, text)
+	}
+	lines := splitLines(description)
+	section, ok := findSection(lines, name)
+	if !ok {
+		return joinBlocks(description, "## "+strings.TrimSpace(name)+"\n\n"+text)
+	}
+
+	body := strings.Trim(strings.Join(lines[section.body:section.end], "\n"), "\n")
+	var appended []string
+	appended = append(appended, lines[:section.body]...)
+	appended = append(appended, "")
+	appended = append(appended, strings.Split(joinBlocks(body, text), "\n")...)
+	if section.end < len(lines) {
+		appended = append(appended, "")
+	}
+	appended = append(appended, lines[section.end:]...)
+	return joinLines(appended)
+}
+
+// splitLines splits a description into lines, accepting Windows line endings
+func splitLines(description string) []string {
+	return strings.Split(strings.ReplaceAll(description, "\r\n", "\n"), "\n")
+}
+
+// joinBlocks appends block to text, separated by a blank line
+func joinBlocks(text, block string) string {
+	text = strings.TrimRight(text, " \t\r\n")
+	if text == "" {
+		return block
+	}
+	return text + "\n\n" + block
+}
+
+// joinLines joins the lines of an edited description, dropping blank lines at its ends
+func joinLines(lines []string) string {
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func (s *Server) handleAppendToDescription(ctx context.Context, args appendToDescriptionArgs) (interface{}, error) {
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, invalidParams("text must not be empty")
+	}
+	return s.editDescription(ctx, "append_to_description", args.CardID, func(description string) string {
+		return appendToSection(description, args.Section, args.Text)
+	})
+}
+
+func (s *Server) handleSetDescriptionSection(ctx context.Context, args setDescriptionSectionArgs) (interface{}, error) {
+	if normalizeHeading(args.Section) == "" {
+		return nil, invalidParams("section must not be empty")
+	}
+	if args.Content == nil {
+		return nil, invalidArgument("content", "missing required argument content")
+	}
+	return s.editDescription(ctx, "set_description_section", args.CardID, func(description string) string {
+		return setSection(description, args.Section, *args.Content)
+	})
+}
+
+// editDescription reads the description of a card, applies edit and saves the result,
+// leaving the card alone if nothing changed
+func (s *Server) editDescription(ctx context.Context, tool, cardID string, edit func(string) string) (interface{}, error) {
+	client := s.clientFor(ctx)
+	card, err := client.GetCard(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	description := edit(card.Description)
+	if description == card.Description {
+		return card, nil
+	}
+	updated, err := client.UpdateCard(ctx, cardID, planka.UpdateCardRequest{Description: &description})
+	if err != nil {
+		return nil, err
+	}
+	updated = s.applyRules(ctx, client, updated)
+	s.recordCardUndo(ctx, client, tool, card, updated)
+	return updated, nil
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// dryRunArgument is the argument of destructive tools that makes a call only report the
+// requests it would send to Planka
+const dryRunArgument = "dryRun"
+
+// dryRunResult is the result of a tool call made in a dry run
+type dryRunResult struct {
+	DryRun bool   `json:"dryRun"`
+	Tool   string `json:"tool"`
+	// Calls are the requests that would change Planka, in order. Entities the call would
+	// create get placeholder IDs such as "dry-run-1", which later calls refer to.
+	Calls []planka.Call `json:"calls"`
+}
+
+// SetDryRun makes the tools that change Planka report the POST, PATCH and DELETE requests
+// they would send instead of sending them, e.g. to try out new agent prompts safely.
+// Reads still reach Planka, and nothing is recorded for undo_last or sent to webhooks.
+func (s *Server) SetDryRun() {
+	s.dryRun = true
+	s.registerTools()
+}
+
+// withDryRunArgument adds the dryRun argument to the input schema of a destructive tool
+func withDryRunArgument(tool *mcpsdk.Tool) *mcpsdk.Tool {
+	schema := maps.Clone(tool.InputSchema.(map[string]interface{}))
+	properties := maps.Clone(schema["properties"].(map[string]interface{}))
+	properties[dryRunArgument] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Only report the Planka API requests the call would make, without making them",
+	}
+	schema["properties"] = properties
+
+	clone := *tool
+	clone.InputSchema = schema
+	return &clone
+}
+
+// hasArgument reports whether the input schema of tool has the argument name
+func hasArgument(tool *mcpsdk.Tool, name string) bool {
+	properties, _ := tool.InputSchema.(map[string]interface{})["properties"].(map[string]interface{})
+	_, ok := properties[name]
+	return ok
+}
+
+// withDryRun wraps the handler of a tool that changes Planka so its calls are dry runs when
+// the server is in dry-run mode or, if perCall is set, when the dryRun argument is true.
+// Tools with a dryRun argument of their own preview without changing anything, so that
+// preview is returned as is.
+func (s *Server) withDryRun(name string, perCall bool, next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		var args map[string]interface{}
+		if len(req.Params.Arguments) > 0 {
+			// Malformed arguments are reported by the tool handler itself
+			json.Unmarshal(req.Params.Arguments, &args)
+		}
+		requested := false
+		if value, ok := args[dryRunArgument]; ok && value != nil {
+			if requested, ok = value.(bool); !ok {
+				return nil, invalidParams("%s must be a boolean", dryRunArgument)
+			}
+		}
+		if requested && !perCall {
+			return next(ctx, req)
+		}
+		if !requested && !s.dryRun {
+			return next(ctx, req)
+		}
+
+		ctx, dryRun := planka.WithDryRun(ctx)
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+		return buildToolResult(dryRunResult{DryRun: true, Tool: name, Calls: dryRun.Calls()})
+	}
+}
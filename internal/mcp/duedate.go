@@ -0,0 +1,285 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDueHour is the time of day a due date without one is set to
+const defaultDueHour = 17
+
+// dueDate is a due date as given by the model: an RFC 3339 timestamp, a date, epoch
+// milliseconds or a phrase such as "tomorrow 5pm", "next friday" or "in 3 days"
+type dueDate string
+
+// UnmarshalJSON accepts a string or a number of epoch milliseconds
+func (d *dueDate) UnmarshalJSON(data []byte) error {
+	var millis json.Number
+	if err := json.Unmarshal(data, &millis); err == nil {
+		*d = dueDate(millis)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errors.New("dueDate must be a string or a number of epoch milliseconds")
+	}
+	*d = dueDate(text)
+	return nil
+}
+
+var (
+	// dueTimePattern matches a time of day at the end of a phrase, e.g. "at 5:30pm" or "17:00"
+	dueTimePattern = regexp.MustCompile(`(?:^|\s)(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	// dueOffsetPattern matches relative phrases such as "in 3 days" or "in an hour"
+	dueOffsetPattern = regexp.MustCompile(`^in\s+(\d+|a|an|one)\s+(minute|hour|day|week|month|year)s?$`)
+	// dueFromNowPattern matches phrases such as "3 days from now"
+	dueFromNowPattern = regexp.MustCompile(`^(\d+|a|an|one)\s+(minute|hour|day|week|month|year)s?\s+from\s+now$`)
+)
+
+// zonedLayouts are the timestamps with a UTC offset accepted besides RFC 3339
+var zonedLayouts = []string{
+	"2006-01-02T15:04:05Z0700", "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05Z0700",
+	time.RFC1123Z, time.RFC1123, time.RFC850, time.ANSIC,
+}
+
+// isoLayouts are the timestamps and dates without a UTC offset accepted; they are
+// interpreted in the server's timezone
+var isoLayouts = []string{
+	"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04",
+	"2006/01/02 15:04", "2006-01-02", "2006/01/02", "20060102",
+}
+
+// minEpochMillis is the smallest number taken as epoch milliseconds rather than seconds,
+// 1973-03-03 in milliseconds or the year 5138 in seconds
+const minEpochMillis = 1e11
+
+// monthDayLayouts are calendar dates without a time, with or without a year.
+// Month names are matched case-insensitively.
+var monthDayLayouts = []string{
+	"January 2 2006", "Jan 2 2006", "2 January 2006", "2 Jan 2006",
+	"January 2", "Jan 2", "2 January", "2 Jan",
+}
+
+// SetTimezone sets the timezone due date phrases such as "tomorrow 5pm" and recurrence
+// schedules are interpreted in.
+// The server's local timezone is used until it is set.
+func (s *Server) SetTimezone(loc *time.Location) {
+	s.timezone = loc
+}
+
+// location returns the timezone due date phrases and schedules are interpreted in
+func (s *Server) location() *time.Location {
+	if s.timezone == nil {
+		return time.Local
+	}
+	return s.timezone
+}
+
+// resolveDueDate converts a due date argument to a timestamp, reporting unparseable
+// phrases as invalid params
+func (s *Server) resolveDueDate(name string, value *dueDate) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	due, err := parseDueDate(string(*value), time.Now().In(s.location()))
+	if err != nil {
+		return nil, invalidParams("%s: %v", name, err)
+	}
+	return &due, nil
+}
+
+// parseDueDate interprets text relative to now, in now's timezone, and returns the due
+// date in UTC. Dates without a time of day are due at defaultDueHour.
+func parseDueDate(text string, now time.Time) (time.Time, error) {
+	due, err := parseLocalDueDate(text, now)
+	return due.UTC(), err
+}
+
+// parseLocalDueDate interprets text like parseDueDate but returns the due date in the
+// timezone it was given in
+func parseLocalDueDate(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, nil
+	}
+	if epoch, err := strconv.ParseInt(text, 10, 64); err == nil && len(text) > len("20060102") {
+		if epoch >= minEpochMillis {
+			return time.UnixMilli(epoch), nil
+		}
+		return time.Unix(epoch, 0), nil
+	}
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			if !strings.Contains(layout, ":") {
+				t = t.Add(defaultDueHour * time.Hour)
+			}
+			return t, nil
+		}
+	}
+
+	phrase := strings.Join(strings.Fields(strings.ToLower(strings.NewReplacer(",", " ", ".", " ").Replace(text))), " ")
+	if t, ok := parseDueOffset(phrase, now); ok {
+		return t, nil
+	}
+
+	// Split off a trailing time of day, e.g. "friday at 5pm"
+	hour, minute, hasTime := defaultDueHour, 0, false
+	if h, m, rest, ok := splitDueTime(phrase); ok {
+		hour, minute, hasTime, phrase = h, m, true, rest
+	}
+
+	day, ok := parseDueDay(phrase, now)
+	if !ok {
+		return time.Time{}, fmt.Errorf(`cannot understand due date %q; use RFC 3339 (e.g. 2024-01-31T17:00:00Z), a date (e.g. 2024-01-31), epoch milliseconds or a phrase such as "tomorrow 5pm", "next friday" or "in 3 days"`, text)
+	}
+	if phrase == "tonight" && !hasTime {
+		hour = 20
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location()), nil
+}
+
+// parseDueOffset parses "now", "in 3 days" and "3 days from now"
+func parseDueOffset(phrase string, now time.Time) (time.Time, bool) {
+	if phrase == "now" {
+		return now.Truncate(time.Minute), true
+	}
+	match := dueOffsetPattern.FindStringSubmatch(phrase)
+	if match == nil {
+		match = dueFromNowPattern.FindStringSubmatch(phrase)
+	}
+	if match == nil {
+		return time.Time{}, false
+	}
+	n := 1
+	if count, err := strconv.Atoi(match[1]); err == nil {
+		n = count
+	}
+	switch match[2] {
+	case "minute":
+		return now.Add(time.Duration(n) * time.Minute).Truncate(time.Minute), true
+	case "hour":
+		return now.Add(time.Duration(n) * time.Hour).Truncate(time.Minute), true
+	case "day":
+		return now.AddDate(0, 0, n).Truncate(time.Minute), true
+	case "week":
+		return now.AddDate(0, 0, 7*n).Truncate(time.Minute), true
+	case "month":
+		return now.AddDate(0, n, 0).Truncate(time.Minute), true
+	default:
+		return now.AddDate(n, 0, 0).Truncate(time.Minute), true
+	}
+}
+
+// splitDueTime removes a trailing time of day from phrase
+func splitDueTime(phrase string) (hour, minute int, rest string, ok bool) {
+	for word, h := range map[string]int{"noon": 12, "midnight": 0} {
+		if rest, found := strings.CutSuffix(phrase, word); found {
+			return h, 0, strings.TrimSuffix(strings.TrimSpace(rest), " at"), true
+		}
+	}
+
+	groups := dueTimePattern.FindStringSubmatch(phrase)
+	if groups == nil {
+		return 0, 0, "", false
+	}
+	// A bare number is a day of the month ("march 5"), not a time
+	if groups[2] == "" && groups[3] == "" {
+		return 0, 0, "", false
+	}
+	hour, _ = strconv.Atoi(groups[1])
+	if groups[2] != "" {
+		minute, _ = strconv.Atoi(groups[2])
+	}
+	if hour > 23 || minute > 59 || (groups[3] != "" && (hour == 0 || hour > 12)) {
+		return 0, 0, "", false
+	}
+	switch groups[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	return hour, minute, strings.TrimSpace(strings.TrimSuffix(phrase, groups[0])), true
+}
+
+// parseDueDay resolves a day phrase such as "tomorrow", "next friday" or "march 5".
+// An empty phrase is today, so a lone time like "5pm" is due today.
+func parseDueDay(phrase string, now time.Time) (time.Time, bool) {
+	switch phrase {
+	case "", "today", "tonight":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	case "day after tomorrow":
+		return now.AddDate(0, 0, 2), true
+	case "next week":
+		// Monday of next week
+		return now.AddDate(0, 0, 7-(int(now.Weekday())+6)%7), true
+	case "end of week", "end of the week":
+		return nextWeekday(now, time.Friday, true), true
+	case "next month":
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()), true
+	case "end of month", "end of the month":
+		return time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()), true
+	}
+
+	qualifier, name, found := strings.Cut(phrase, " ")
+	if !found {
+		qualifier, name = "", phrase
+	}
+	if qualifier == "" || qualifier == "this" || qualifier == "next" || qualifier == "on" {
+		if weekday, ok := parseWeekday(name); ok {
+			return nextWeekday(now, weekday, qualifier != "next"), true
+		}
+	}
+
+	for _, layout := range monthDayLayouts {
+		t, err := time.ParseInLocation(layout, phrase, now.Location())
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			// Without a year, the next occurrence of the date is meant
+			t = t.AddDate(now.Year()-t.Year(), 0, 0)
+			if t.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// parseWeekday parses a weekday name or its three-letter abbreviation
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// nextWeekday returns the next day that is weekday; today counts if includeToday is set
+func nextWeekday(now time.Time, weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDueDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no timezone database: %v", err)
+	}
+	// Friday 2025-02-28 10:30 in Berlin, UTC+1
+	now := time.Date(2025, 2, 28, 10, 30, 0, 0, berlin)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"2025-03-01T12:00:00+02:00", "2025-03-01T10:00:00Z"},
+		{"2025-03-01T12:00:00.250Z", "2025-03-01T12:00:00.25Z"},
+		{"2025-03-01T12:00:00+0200", "2025-03-01T10:00:00Z"},
+		{"Sat, 01 Mar 2025 12:00:00 +0000", "2025-03-01T12:00:00Z"},
+		{"2025-03-01T12:00:00", "2025-03-01T11:00:00Z"},
+		{"2025-03-01 12:00", "2025-03-01T11:00:00Z"},
+		{"2025-03-01", "2025-03-01T16:00:00Z"},
+		{"2025/03/01", "2025-03-01T16:00:00Z"},
+		{"20250301", "2025-03-01T16:00:00Z"},
+		{"1740830400000", "2025-03-01T12:00:00Z"},
+		{"1740830400", "2025-03-01T12:00:00Z"},
+		{"tomorrow 9am", "2025-03-01T08:00:00Z"},
+		{"march 5", "2025-03-05T16:00:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := parseDueDate(tt.text, now)
+		if err != nil {
+			t.Errorf("parseDueDate(%q): %v", tt.text, err)
+			continue
+		}
+		if got.Location() != time.UTC {
+			t.Errorf("parseDueDate(%q) = %s, want it in UTC", tt.text, got)
+		}
+		if got.Format(time.RFC3339Nano) != tt.want {
+			t.Errorf("parseDueDate(%q) = %s, want %s", tt.text, got.Format(time.RFC3339Nano), tt.want)
+		}
+	}
+
+	for _, text := range []string{"soonish", "2025-13-01", "31/02/2025"} {
+		if _, err := parseDueDate(text, now); err == nil {
+			t.Errorf("parseDueDate(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestDueDateAcceptsEpochMillis(t *testing.T) {
+	var args createCardArgs
+	if err := json.Unmarshal([]byte(`{"name": "Ship", "listId": "1", "dueDate": 1740830400000}`), &args); err != nil {
+		t.Fatalf("decoding a numeric dueDate: %v", err)
+	}
+	if args.DueDate == nil || *args.DueDate != "1740830400000" {
+		t.Errorf("dueDate = %v, want 1740830400000", args.DueDate)
+	}
+}
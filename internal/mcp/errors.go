@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// invalidParams returns an error reported to the client as -32602 Invalid params
+func invalidParams(format string, args ...interface{}) error {
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// invalidArgument returns an invalid params error about one argument, naming it in the
+// error's data as {"field": name} so clients can point at it
+func invalidArgument(name, format string, args ...interface{}) error {
+	data, _ := json.Marshal(map[string]string{"field": name})
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf(format, args...), Data: data}
+}
+
+// toolError is a tool failure described in terms the model can act on.
+// The underlying error remains available through errors.As.
+type toolError struct {
+	message string
+	err     error
+}
+
+func (e *toolError) Error() string { return e.message }
+func (e *toolError) Unwrap() error { return e.err }
+
+// describeError maps errors from the Planka API to tool errors that say what went wrong,
+// e.g. a 404 on a card to "card not found". Other errors are returned unchanged.
+func describeError(err error) error {
+	var apiErr *planka.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	entity := apiErr.Entity()
+	if entity == "" {
+		entity = "resource"
+	}
+
+	var message string
+	switch status := apiErr.StatusCode; {
+	case status == http.StatusNotFound:
+		message = fmt.Sprintf("%s not found; check that the ID is correct", entity)
+	case status == http.StatusForbidden:
+		message = fmt.Sprintf("insufficient permissions for this %s", entity)
+	case status == http.StatusUnauthorized:
+		message = "Planka rejected the credentials; the access token may be invalid or expired"
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		message = fmt.Sprintf("Planka rejected the %s request as invalid", entity)
+	case status == http.StatusConflict:
+		message = fmt.Sprintf("the %s conflicts with an existing one", entity)
+	case status == http.StatusTooManyRequests:
+		message = "Planka is rate limiting requests; retry later"
+	case status >= 500:
+		message = fmt.Sprintf("Planka failed to handle the request (status %d); retry later", status)
+	default:
+		return err
+	}
+
+	// Planka's own description adds detail, e.g. which parameter was invalid
+	if apiErr.Message != "" && apiErr.StatusCode != http.StatusNotFound {
+		message += ": " + apiErr.Message
+	}
+	return &toolError{message: message, err: err}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// eventLogger is the logger name attached to board change notifications
+	eventLogger = "planka.events"
+	// eventBacklog is how many board changes may wait to be sent before new ones are dropped
+	eventBacklog = 100
+)
+
+// StartBoardEvents pushes a notifications/message to every connected client whenever a
+// board watched by realtime changes in Planka, so agents learn about changes made by
+// people without polling. Clients only receive them once they have enabled logging at
+// info level or lower. Notifications stop when ctx is cancelled.
+func (s *Server) StartBoardEvents(ctx context.Context, realtime *planka.Realtime) {
+	unsubscribe := realtime.Subscribe(func(event planka.Event) {
+		// Only changes to watched boards are announced
+		if realtime.Watching(event.BoardID) {
+			s.publishEvent(event)
+		}
+	})
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	s.startEventNotifications(ctx)
+}
+
+// publishEvent passes a change in Planka on to the server's event bus
+func (s *Server) publishEvent(event planka.Event) {
+	if dropped := s.events.Publish(event); dropped > 0 {
+		log.Printf("Board events: dropped %s for board %s, clients are not keeping up", event.Name, event.BoardID)
+	}
+}
+
+// startEventNotifications forwards the changes published on the event bus to connected
+// clients until ctx is cancelled. Only the first call starts forwarding.
+func (s *Server) startEventNotifications(ctx context.Context) {
+	s.notifyEvents.Do(func() {
+		events := s.events.Subscribe(eventBacklog)
+		go func() {
+			defer events.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-events.C:
+					s.notifyBoardEvent(ctx, event)
+				}
+			}
+		}()
+	})
+}
+
+// notifyBoardEvent sends a board change to every connected client
+func (s *Server) notifyBoardEvent(ctx context.Context, event planka.Event) {
+	var item interface{}
+	json.Unmarshal(event.Item, &item)
+
+	params := &mcpsdk.LoggingMessageParams{
+		Level:  "info",
+		Logger: eventLogger,
+		Data: map[string]interface{}{
+			"type":    "boardChange",
+			"message": fmt.Sprintf("A %s on board %s was %sd", event.Entity, event.BoardID, event.Action),
+			"event":   event.Name,
+			"entity":  event.Entity,
+			"action":  event.Action,
+			"boardId": event.BoardID,
+			"item":    item,
+		},
+	}
+
+	for session := range s.server.Sessions() {
+		if err := session.Log(ctx, params); err != nil {
+			log.Printf("Board events: failed to notify session %s: %v", session.ID(), err)
+		}
+	}
+}
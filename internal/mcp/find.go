@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// minMatchScore is the similarity below which a name is not considered a match
+const minMatchScore = 0.5
+
+// boardMatch is a board found by find_board, with the project it belongs to
+type boardMatch struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	ProjectID   string  `json:"projectId"`
+	ProjectName string  `json:"projectName"`
+	Score       float64 `json:"score"`
+}
+
+func (s *Server) handleFindBoard(ctx context.Context, args findBoardArgs) (interface{}, error) {
+	client := s.clientFor(ctx)
+
+	var projects []planka.Project
+	if args.ProjectID != "" {
+		project, err := client.GetProject(ctx, args.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		projects = []planka.Project{*project}
+	} else {
+		var err error
+		if projects, err = client.GetProjects(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := []boardMatch{}
+	for _, project := range projects {
+		boards, err := client.GetBoards(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, board := range boards {
+			if score := matchScore(args.Name, board.Name); score >= minMatchScore {
+				matches = append(matches, boardMatch{
+					ID:          board.ID,
+					Name:        board.Name,
+					ProjectID:   project.ID,
+					ProjectName: project.Name,
+					Score:       score,
+				})
+			}
+		}
+	}
+
+	// Best matches first
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches, nil
+}
+
+// matchScore rates how well name matches query, from 0 (unrelated) to 1 (equal ignoring
+// case, punctuation and spacing). Names containing the query score high, and typos are
+// tolerated through the edit distance of the normalized names.
+func matchScore(query, name string) float64 {
+	q, n := normalizeName(query), normalizeName(name)
+	switch {
+	case q == "" || n == "":
+		return 0
+	case q == n:
+		return 1
+	case strings.HasPrefix(n, q):
+		return 0.9
+	case strings.Contains(n, q):
+		return 0.8
+	}
+
+	score := nameSimilarity(q, n)
+
+	// Every word of the query appearing in the name, in any order, is a good match too
+	words := strings.Fields(q)
+	found := 0
+	for _, word := range words {
+		if strings.Contains(n, word) {
+			found++
+		}
+	}
+	if found == len(words) && 0.7 > score {
+		score = 0.7
+	}
+	return score
+}
+
+// nameSimilarity rates how alike two names are, from 0 to 1, by the edit distance of
+// their normalized forms
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeName(a), normalizeName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// normalizeName lowercases name and reduces punctuation and runs of spaces to single spaces
+func normalizeName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// listMatch is the list found by get_list_by_name
+type listMatch struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	BoardID   string  `json:"boardId"`
+	CardCount int     `json:"cardCount"`
+	Score     float64 `json:"score"`
+}
+
+func (s *Server) handleGetListByName(ctx context.Context, args getListByNameArgs) (interface{}, error) {
+	client := s.clientFor(ctx)
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *planka.List
+	var bestScore float64
+	ties := 0
+	for i := range lists {
+		score := matchScore(args.Name, lists[i].Name)
+		switch {
+		case score > bestScore:
+			best, bestScore, ties = &lists[i], score, 1
+		case score == bestScore && best != nil:
+			ties++
+		}
+	}
+	if best == nil || bestScore < minMatchScore {
+		names := make([]string, len(lists))
+		for i, list := range lists {
+			names[i] = list.Name
+		}
+		return nil, invalidParams("no list named %q on board %s; its lists are: %s", args.Name, args.BoardID, strings.Join(names, ", "))
+	}
+	if ties > 1 {
+		return nil, invalidParams("%d lists on board %s match %q equally well; use get_lists to pick one by ID", ties, args.BoardID, args.Name)
+	}
+
+	cards, err := client.GetBoardCards(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	match := listMatch{ID: best.ID, Name: best.Name, BoardID: args.BoardID, Score: bestScore}
+	for _, card := range cards {
+		if card.ListID == best.ID {
+			match.CardCount++
+		}
+	}
+	return match, nil
+}
@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Formats report tools can render their result in
+const (
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+	formatSlack    = "slack"
+)
+
+// formatArgs is the format argument accepted by every report tool
+type formatArgs struct {
+	Format string `json:"format,omitempty" enum:"json,markdown,slack" jsonschema:"How to render the report: json (default), markdown, or slack for Slack mrkdwn ready to post as a message"`
+}
+
+// validate rejects unknown formats
+func (f formatArgs) validate() error {
+	switch f.Format {
+	case "", formatJSON, formatMarkdown, formatSlack:
+		return nil
+	default:
+		return invalidParams("format must be json, markdown or slack")
+	}
+}
+
+// report is a document rendered from a report tool's result: a title, an optional
+// summary line and sections of bullet items
+type report struct {
+	Title    string
+	Summary  string
+	Sections []reportSection
+	// Empty is shown when no section has items
+	Empty string
+}
+
+// reportSection is a heading with bullet items; sections without items are left out
+type reportSection struct {
+	Heading string
+	Items   []reportItem
+}
+
+// reportItem is a bullet: its text, linked to URL if set, followed by detail
+type reportItem struct {
+	Text   string
+	URL    string
+	Detail string
+}
+
+// formattedResult is a tool result whose text content is a rendered report, for posting
+// as is, while the structured content keeps the data
+type formattedResult struct {
+	Text string
+	Data interface{}
+}
+
+// formatReport returns data as is for the json format, and otherwise a formattedResult
+// carrying data and the document rendered in the format
+func formatReport(format string, data interface{}, doc report) interface{} {
+	switch format {
+	case formatMarkdown:
+		return formattedResult{Text: doc.markdown(), Data: data}
+	case formatSlack:
+		return formattedResult{Text: doc.slack(), Data: data}
+	default:
+		return data
+	}
+}
+
+// markdown renders the report as CommonMark
+func (r report) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", escapeMarkdown(r.Title))
+	if r.Summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", escapeMarkdown(r.Summary))
+	}
+	empty := true
+	for _, section := range r.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", escapeMarkdown(section.Heading), len(section.Items))
+		for _, item := range section.Items {
+			text := escapeMarkdown(item.Text)
+			if item.URL != "" {
+				text = fmt.Sprintf("[%s](%s)", text, item.URL)
+			}
+			if item.Detail != "" {
+				text += " — " + escapeMarkdown(item.Detail)
+			}
+			fmt.Fprintf(&b, "- %s\n", text)
+		}
+	}
+	if empty && r.Empty != "" {
+		fmt.Fprintf(&b, "\n%s\n", escapeMarkdown(r.Empty))
+	}
+	return b.String()
+}
+
+// slack renders the report as Slack mrkdwn, which has no headings or lists, so headings
+// are bold and items are bullet characters
+func (r report) slack() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n", escapeSlack(r.Title))
+	if r.Summary != "" {
+		fmt.Fprintf(&b, "%s\n", escapeSlack(r.Summary))
+	}
+	empty := true
+	for _, section := range r.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "\n*%s* (%d)\n", escapeSlack(section.Heading), len(section.Items))
+		for _, item := range section.Items {
+			text := escapeSlack(item.Text)
+			if item.URL != "" {
+				// The link text ends at "|" and ">", which escapeSlack does not touch
+				text = fmt.Sprintf("<%s|%s>", item.URL, strings.ReplaceAll(text, "|", "¦"))
+			}
+			if item.Detail != "" {
+				text += " — " + escapeSlack(item.Detail)
+			}
+			fmt.Fprintf(&b, "• %s\n", text)
+		}
+	}
+	if empty && r.Empty != "" {
+		fmt.Fprintf(&b, "\n%s\n", escapeSlack(r.Empty))
+	}
+	return b.String()
+}
+
+// escapeMarkdown escapes the characters of text that Markdown would read as formatting
+var escapeMarkdown = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+).Replace
+
+// escapeSlack escapes the control characters of Slack mrkdwn, as Slack documents
+var escapeSlack = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// cardURL returns the link to a card in the Planka web app, or "" if the client does not
+// know its Planka URL
+func cardURL(client PlankaClient, cardID string) string {
+	if c, ok := client.(interface{ BaseURL() string }); ok && c.BaseURL() != "" {
+		return c.BaseURL() + "/cards/" + cardID
+	}
+	return ""
+}
@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/gitlab"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// labelColors are the colors Planka accepts for labels; labels created by an import get
+// one picked from the label name, so a label keeps its color across boards
+var labelColors = []string{
+	"berry-red", "pumpkin-orange", "lagoon-blue", "pink-tulip", "light-mud",
+	"orange-peel", "bright-moss", "antique-blue", "dark-granite", "lagune-blue",
+	"sunny-grass", "morning-sky", "light-orange", "midnight-blue", "tank-green",
+	"gun-metal", "wet-moss", "red-burgundy", "light-concrete", "apricot-red",
+	"desert-sand", "navy-blue", "egg-yellow", "coral-green", "light-cocoa",
+}
+
+// importedCard is a card created from a GitLab issue, or that would be in a dry run
+type importedCard struct {
+	// ID is the created card; empty in a dry run
+	ID        string   `json:"id,omitempty"`
+	Name      string   `json:"name"`
+	IssueIID  int      `json:"issueIid"`
+	IssueURL  string   `json:"issueUrl"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// skippedIssue is an issue that already has a card on the board
+type skippedIssue struct {
+	IssueIID int    `json:"issueIid"`
+	IssueURL string `json:"issueUrl"`
+	CardID   string `json:"cardId"`
+}
+
+// gitlabImport reports the outcome of import_gitlab_issues
+type gitlabImport struct {
+	DryRun   bool           `json:"dryRun"`
+	Imported []importedCard `json:"imported"`
+	Skipped  []skippedIssue `json:"skipped"`
+	// UnmappedAssignees are GitLab usernames no board member could be found for
+	UnmappedAssignees []string `json:"unmappedAssignees,omitempty"`
+	// Errors describes issues, labels or assignees that could not be imported
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SetGitLab sets the GitLab instance and token import_gitlab_issues uses when the call
+// does not name them. An empty baseURL means gitlab.com.
+func (s *Server) SetGitLab(baseURL, token string) {
+	s.gitlabURL = baseURL
+	s.gitlabToken = token
+}
+
+func (s *Server) handleImportGitLabIssues(ctx context.Context, args importGitLabIssuesArgs) (interface{}, error) {
+	if args.State != "" && args.State != "opened" && args.State != "closed" && args.State != "all" {
+		return nil, invalidParams("state must be opened, closed or all")
+	}
+	if args.Limit < 0 {
+		return nil, invalidParams("limit must not be negative")
+	}
+	token := args.Token
+	if token == "" {
+		token = s.gitlabToken
+	}
+	if token == "" {
+		return nil, invalidParams("token is required when the server has no GitLab token configured (GITLAB_TOKEN)")
+	}
+	baseURL := args.GitLabURL
+	if baseURL == "" {
+		baseURL = s.gitlabURL
+	}
+	if baseURL == "" {
+		baseURL = gitlab.DefaultURL
+	}
+	state := args.State
+	if state == "" {
+		state = "opened"
+	}
+	limit := args.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	client := s.clientFor(ctx)
+	list, err := client.GetList(ctx, args.ListID)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := gitlab.NewClient(baseURL, token).ListIssues(ctx, args.Project, gitlab.IssueQuery{
+		State:  state,
+		Labels: args.Labels,
+		Limit:  limit,
+	})
+	if err != nil {
+		var apiErr *gitlab.Error
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == 401 || apiErr.StatusCode == 403 || apiErr.StatusCode == 404) {
+			return nil, invalidParams("cannot read issues of GitLab project %q: %v", args.Project, err)
+		}
+		return nil, err
+	}
+	result, err := s.importIssues(ctx, client, list, issues, args.Assignees, args.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importIssues creates a card in list for every issue that has none on the board yet. Cards
+// get the issue's labels, creating missing board labels, and its assignees, mapped to board
+// members through assignees or by username or name. Failures are collected in the result
+// so one bad issue does not stop the others from being imported.
+func (s *Server) importIssues(ctx context.Context, client PlankaClient, list *planka.List, issues []gitlab.Issue, assignees map[string]string, dryRun bool) (*gitlabImport, error) {
+	cards, err := client.GetBoardCards(ctx, list.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := client.GetLabels(ctx, list.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := client.GetBoardUsers(ctx, list.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &gitlabImport{DryRun: dryRun, Imported: []importedCard{}, Skipped: []skippedIssue{}}
+	labelIDs := map[string]string{}
+	for _, label := range labels {
+		labelIDs[strings.ToLower(label.Name)] = label.ID
+	}
+	unmapped := map[string]bool{}
+
+	var next float64
+	if !dryRun {
+		pos, err := resolvePosition(ctx, client, &position{keyword: "bottom"}, list.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		next = *pos
+	}
+
+	for _, issue := range issues {
+		// Imported cards link back to their issue, which is how earlier imports are recognized
+		if card := cardLinking(cards, issue.WebURL); card != nil {
+			result.Skipped = append(result.Skipped, skippedIssue{IssueIID: issue.IID, IssueURL: issue.WebURL, CardID: card.ID})
+			continue
+		}
+
+		entry := importedCard{Name: issue.Title, IssueIID: issue.IID, IssueURL: issue.WebURL, Labels: issue.Labels}
+		var userIDs []string
+		for _, assignee := range issue.Assignees {
+			user := mapAssignee(assignee, assignees, users)
+			if user == nil {
+				if !unmapped[assignee.Username] {
+					unmapped[assignee.Username] = true
+					result.UnmappedAssignees = append(result.UnmappedAssignees, assignee.Username)
+				}
+				continue
+			}
+			userIDs = append(userIDs, user.ID)
+			entry.Assignees = append(entry.Assignees, user.Username)
+		}
+
+		var due *time.Time
+		if issue.DueDate != "" {
+			if t, err := parseDueDate(issue.DueDate, time.Now().In(s.location())); err == nil {
+				due = &t
+			}
+		}
+		if dryRun {
+			result.Imported = append(result.Imported, entry)
+			continue
+		}
+
+		card, err := client.CreateCard(ctx, planka.CreateCardRequest{
+			Name:        issue.Title,
+			Description: issueDescription(issue),
+			ListID:      list.ID,
+			Position:    &next,
+			DueDate:     due,
+		})
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("issue #%d: %v", issue.IID, err))
+			continue
+		}
+		next += planka.PositionGap
+		entry.ID = card.ID
+
+		for _, name := range issue.Labels {
+			labelID, ok := labelIDs[strings.ToLower(name)]
+			if !ok {
+				label, err := client.CreateLabel(ctx, planka.CreateLabelRequest{BoardID: list.BoardID, Name: name, Color: labelColor(name)})
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("label %q: %v", name, err))
+					continue
+				}
+				labelID = label.ID
+				labelIDs[strings.ToLower(name)] = labelID
+			}
+			if err := client.AddCardLabel(ctx, card.ID, labelID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("issue #%d: label %q: %v", issue.IID, name, err))
+			}
+		}
+		for _, userID := range userIDs {
+			if err := client.AddCardMember(ctx, card.ID, userID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("issue #%d: assignee %s: %v", issue.IID, userID, err))
+			}
+		}
+		result.Imported = append(result.Imported, entry)
+	}
+	return result, nil
+}
+
+// cardLinking returns the card whose description links to url, if any. The link must not
+// continue with a digit, so issue 1 is not mistaken for issue 12.
+func cardLinking(cards []planka.Card, url string) *planka.Card {
+	if url == "" {
+		return nil
+	}
+	for i := range cards {
+		rest := cards[i].Description
+		for {
+			at := strings.Index(rest, url)
+			if at < 0 {
+				break
+			}
+			rest = rest[at+len(url):]
+			if rest == "" || rest[0] < '0' || rest[0] > '9' {
+				return &cards[i]
+			}
+		}
+	}
+	return nil
+}
+
+// mapAssignee finds the board member for a GitLab user: the one named for its username
+// in mapping (by ID or username), else the member with the same username, else the
+// member with the same name
+func mapAssignee(assignee gitlab.User, mapping map[string]string, users []planka.User) *planka.User {
+	if target, ok := mapping[assignee.Username]; ok {
+		for i, user := range users {
+			if user.ID == target || strings.EqualFold(user.Username, target) {
+				return &users[i]
+			}
+		}
+		return nil
+	}
+	for i, user := range users {
+		if strings.EqualFold(user.Username, assignee.Username) {
+			return &users[i]
+		}
+	}
+	for i, user := range users {
+		if assignee.Name != "" && strings.EqualFold(user.Name, assignee.Name) {
+			return &users[i]
+		}
+	}
+	return nil
+}
+
+// issueDescription is the description of the card imported from issue, linking back to it
+func issueDescription(issue gitlab.Issue) string {
+	link := fmt.Sprintf("Imported from GitLab issue #%d: %s", issue.IID, issue.WebURL)
+	if strings.TrimSpace(issue.Description) == "" {
+		return link
+	}
+	return issue.Description + "\n\n---\n" + link
+}
+
+// labelColor picks the color of a new label from its name
+func labelColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(name)))
+	return labelColors[h.Sum32()%uint32(len(labelColors))]
+}
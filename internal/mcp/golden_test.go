@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka/plankamock"
+)
+
+// update rewrites the golden files from the current output instead of comparing them:
+//
+//	go test ./internal/mcp -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenTools holds a golden file per tool with the tool as tools/list returns it
+const goldenTools = "testdata/tools"
+
+// TestToolSchemasGolden fails when a tool's name, parameters, types or annotations change
+// from its golden file, since agents depend on them; run with -update after a deliberate
+// change and review the diff
+func TestToolSchemasGolden(t *testing.T) {
+	session := connect(t, NewServer(&plankamock.Client{}))
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("tools/list: %v", err)
+	}
+
+	if *update {
+		files, _ := filepath.Glob(filepath.Join(goldenTools, "*.json"))
+		for _, file := range files {
+			os.Remove(file)
+		}
+		if err := os.MkdirAll(goldenTools, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listed := map[string]bool{}
+	for _, tool := range result.Tools {
+		listed[tool.Name] = true
+		got, err := json.MarshalIndent(tool, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: %v", tool.Name, err)
+		}
+		got = append(got, '\n')
+		path := filepath.Join(goldenTools, tool.Name+".json")
+		if *update {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s is a new tool with no golden file; run go test -run Golden -update to add %s", tool.Name, path)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from %s; run go test -run Golden -update if the change is intended\ngot:\n%s", tool.Name, path, got)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(goldenTools, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if name := strings.TrimSuffix(filepath.Base(file), ".json"); !listed[name] {
+			t.Errorf("tool %s is no longer listed but %s expects it", name, file)
+		}
+	}
+}
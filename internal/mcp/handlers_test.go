@@ -0,0 +1,602 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	"github.com/ayushgarg/mcp-planka/pkg/planka/plankamock"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callToolResult calls a tool and returns its text result, whether it is a tool error,
+// and any JSON-RPC error
+func callToolResult(t *testing.T, session *mcpsdk.ClientSession, name string, args map[string]interface{}) (string, bool, error) {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcpsdk.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		return "", false, err
+	}
+	return result.Content[0].(*mcpsdk.TextContent).Text, result.IsError, nil
+}
+
+// containsJSON reports whether got has every field of want with the same value; lists must
+// have the same length and match item by item
+func containsJSON(got, want interface{}) bool {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if !containsJSON(got[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(got) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !containsJSON(got[i], want[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func TestToolsRejectMissingArguments(t *testing.T) {
+	s := NewServer(&plankamock.Client{})
+	session := connect(t, s)
+
+	for _, def := range s.getTools() {
+		required, _ := def.tool.InputSchema.(map[string]interface{})["required"].([]string)
+		if len(required) == 0 {
+			continue
+		}
+		t.Run(def.tool.Name, func(t *testing.T) {
+			_, _, err := callToolResult(t, session, def.tool.Name, map[string]interface{}{})
+			var rpcErr *jsonrpc.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams {
+				t.Fatalf("%s without arguments: error %v, want invalid params", def.tool.Name, err)
+			}
+			if !strings.Contains(rpcErr.Message, "missing required argument") {
+				t.Errorf("%s without arguments: %q, want a missing argument named", def.tool.Name, rpcErr.Message)
+			}
+		})
+	}
+}
+
+func TestToolArgumentValidation(t *testing.T) {
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+		want string
+	}{
+		{"create_project", map[string]interface{}{"name": 42}, "name must be a string"},
+		{"create_list", map[string]interface{}{"name": "Todo", "boardId": "1", "position": "first"}, "position must be a number"},
+		{"update_task", map[string]interface{}{"taskId": "1", "isCompleted": "yes"}, "isCompleted must be a boolean"},
+		{"create_card", map[string]interface{}{"name": "Docs", "listId": "1", "position": true}, "position must be a number or a string"},
+		{"move_card", map[string]interface{}{"cardId": "1", "listId": "2", "position": []string{"top"}}, "position must be a number or a string"},
+		{"update_card", map[string]interface{}{"cardId": "1", "dueDate": 1.5}, "dueDate must be an integer"},
+		{"create_card", map[string]interface{}{"name": "Docs", "listId": "1", "dueDate": false}, "dueDate must be a string or an integer"},
+		{"get_cards", map[string]interface{}{"listId": "1", "page": 1.5}, "page must be an integer"},
+		{"create_comments_bulk", map[string]interface{}{"cardIds": "401", "text": "Done"}, "cardIds must be an array"},
+		{"delete_card", map[string]interface{}{"cardId": "1", "dryRun": "yes"}, "dryRun must be a boolean"},
+		{"get_projects", map[string]interface{}{"cursor": "not-a-cursor"}, "cursor"},
+		{"update_card", map[string]interface{}{"cardId": "1", "clearFields": []string{"name"}}, `clearFields items must be one of description, dueDate`},
+		{"update_card", map[string]interface{}{"cardId": "1", "dueDate": "tomorrow", "clearFields": []string{"dueDate"}}, "dueDate cannot be cleared and set"},
+		{"get_card", map[string]interface{}{"cardId": "Write docs"}, `cardId must be a Planka ID such as 1357158568008091264, got "Write docs"; pass cardName`},
+		{"create_comments_bulk", map[string]interface{}{"cardIds": []string{"1", "../2"}, "text": "Done"}, `cardIds must be a Planka ID such as 1357158568008091264, got "../2"`},
+		{"create_project", map[string]interface{}{"name": "  "}, "name must not be empty"},
+		{"auto_sort_list", map[string]interface{}{"listId": "1", "strategy": "priority"}, `strategy must be one of dueDate, name, createdAt, got "priority"`},
+		{"get_projects", map[string]interface{}{"sortBy": "boards"}, `cannot sort by "boards"; sortBy must be one of id, name, description, createdAt, updatedAt`},
+		{"get_projects", map[string]interface{}{"sortOrder": "newest"}, `sortOrder must be one of asc, desc`},
+		{"get_projects", map[string]interface{}{"offset": 1, "cursor": encodeCursor(1)}, "offset cannot be combined with cursor"},
+		{"get_projects", map[string]interface{}{"page": 2, "limit": 10}, "limit cannot be combined with page or pageSize"},
+		{"get_projects", map[string]interface{}{"pageSize": 10, "cursor": encodeCursor(1)}, "cursor cannot be combined with page or pageSize"},
+	}
+	session := connect(t, NewServer(&plankamock.Client{
+		GetProjectsFunc: func(ctx context.Context) ([]planka.Project, error) { return nil, nil },
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			_, _, err := callToolResult(t, session, tt.tool, tt.args)
+			var rpcErr *jsonrpc.Error
+			if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams {
+				t.Fatalf("%s(%v): error %v, want invalid params", tt.tool, tt.args, err)
+			}
+			if !strings.Contains(rpcErr.Message, tt.want) {
+				t.Errorf("%s(%v): %q, want it to mention %q", tt.tool, tt.args, rpcErr.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidArgumentNamesField(t *testing.T) {
+	session := connect(t, NewServer(&plankamock.Client{}))
+	_, _, err := callToolResult(t, session, "move_all_cards", map[string]interface{}{"sourceListId": "1", "targetListId": "2", "position": "middle"})
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams {
+		t.Fatalf("move_all_cards: error %v, want invalid params", err)
+	}
+	var data struct {
+		Field string `json:"field"`
+	}
+	if err := json.Unmarshal(rpcErr.Data, &data); err != nil || data.Field != "position" {
+		t.Errorf("move_all_cards error data = %s, want the field position", rpcErr.Data)
+	}
+}
+
+func TestToolErrorMapping(t *testing.T) {
+	apiError := func(status int, endpoint, message string) error {
+		return &planka.APIError{StatusCode: status, Method: "GET", Endpoint: endpoint, Message: message}
+	}
+	tests := []struct {
+		name string
+		tool string
+		args map[string]interface{}
+		err  error
+		want string
+	}{
+		{"not found", "get_card", map[string]interface{}{"cardId": "1"}, apiError(http.StatusNotFound, "/api/cards/1", "Card not found"),
+			"card not found; check that the ID is correct"},
+		{"forbidden", "get_board", map[string]interface{}{"boardId": "1"}, apiError(http.StatusForbidden, "/api/boards/1", ""),
+			"insufficient permissions for this board"},
+		{"unauthorized", "get_projects", nil, apiError(http.StatusUnauthorized, "/api/projects", ""),
+			"Planka rejected the credentials; the access token may be invalid or expired"},
+		{"bad request", "create_task", map[string]interface{}{"cardId": "1", "name": "Outline"}, apiError(http.StatusBadRequest, "/api/cards/1/tasks", "Invalid name"),
+			"Planka rejected the card request as invalid: Invalid name"},
+		{"conflict", "create_list", map[string]interface{}{"boardId": "1", "name": "Todo"}, apiError(http.StatusConflict, "/api/boards/1/lists", ""),
+			"the board conflicts with an existing one"},
+		{"rate limited", "get_tasks", map[string]interface{}{"cardId": "1"}, apiError(http.StatusTooManyRequests, "/api/cards/1", ""),
+			"Planka is rate limiting requests; retry later"},
+		{"server error", "delete_task", map[string]interface{}{"taskId": "1"}, apiError(http.StatusServiceUnavailable, "/api/tasks/1", ""),
+			"Planka failed to handle the request (status 503); retry later"},
+		{"network error", "get_comments", map[string]interface{}{"cardId": "1"}, errors.New("request failed: connection refused"),
+			"request failed: connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &plankamock.Client{
+				GetCardFunc:     func(ctx context.Context, cardID string) (*planka.Card, error) { return nil, tt.err },
+				GetBoardFunc:    func(ctx context.Context, boardID string) (*planka.Board, error) { return nil, tt.err },
+				GetProjectsFunc: func(ctx context.Context) ([]planka.Project, error) { return nil, tt.err },
+				CreateTaskFunc: func(ctx context.Context, req planka.CreateTaskRequest) (*planka.Task, error) {
+					return nil, tt.err
+				},
+				CreateListFunc: func(ctx context.Context, req planka.CreateListRequest) (*planka.List, error) {
+					return nil, tt.err
+				},
+				GetTasksFunc:    func(ctx context.Context, cardID string) ([]planka.Task, error) { return nil, tt.err },
+				DeleteTaskFunc:  func(ctx context.Context, taskID string) error { return tt.err },
+				GetCommentsFunc: func(ctx context.Context, cardID string) ([]planka.Comment, error) { return nil, tt.err },
+			}
+			session := connect(t, NewServer(client))
+
+			text, isError, err := callToolResult(t, session, tt.tool, tt.args)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.tool, err)
+			}
+			if !isError || !strings.HasPrefix(text, tt.want) {
+				t.Errorf("%s = %q (isError %v), want a tool error starting with %q", tt.tool, text, isError, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolOutput(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 6, 30, 17, 0, 0, 0, time.UTC)
+	started := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+	card := planka.Card{ID: "401", Name: "Write docs", ListID: "301", Position: 65535, CreatedAt: created}
+	client := &plankamock.Client{
+		GetProjectsFunc: func(ctx context.Context) ([]planka.Project, error) {
+			return []planka.Project{{ID: "101", Name: "Launch"}, {ID: "102", Name: "Ops"}}, nil
+		},
+		GetProjectFunc: func(ctx context.Context, projectID string) (*planka.Project, error) {
+			return &planka.Project{ID: projectID, Name: "Launch", CreatedAt: created}, nil
+		},
+		CreateProjectFunc: func(ctx context.Context, req planka.CreateProjectRequest) (*planka.Project, error) {
+			return &planka.Project{ID: "103", Name: req.Name, Description: req.Description}, nil
+		},
+		DeleteProjectFunc: func(ctx context.Context, projectID string) error { return nil },
+		GetBoardsFunc: func(ctx context.Context, projectID string) ([]planka.Board, error) {
+			return []planka.Board{{ID: "201", Name: "Roadmap", ProjectID: projectID}}, nil
+		},
+		GetBoardFunc: func(ctx context.Context, boardID string) (*planka.Board, error) {
+			return &planka.Board{ID: boardID, Name: "Roadmap", ProjectID: "101"}, nil
+		},
+		CreateBoardFunc: func(ctx context.Context, req planka.CreateBoardRequest) (*planka.Board, error) {
+			return &planka.Board{ID: "202", Name: req.Name, ProjectID: req.ProjectID}, nil
+		},
+		DeleteBoardFunc: func(ctx context.Context, boardID string) error { return nil },
+		GetListsFunc: func(ctx context.Context, boardID string) ([]planka.List, error) {
+			return []planka.List{{ID: "301", Name: "Todo", BoardID: boardID, Position: 65535}, {ID: "302", Name: "Done", BoardID: boardID, Position: 131070}}, nil
+		},
+		GetListFunc: func(ctx context.Context, listID string) (*planka.List, error) {
+			return &planka.List{ID: listID, Name: "Todo", BoardID: "201", Position: 65535}, nil
+		},
+		CreateListFunc: func(ctx context.Context, req planka.CreateListRequest) (*planka.List, error) {
+			list := &planka.List{ID: "303", Name: req.Name, BoardID: req.BoardID}
+			if req.Position != nil {
+				list.Position = *req.Position
+			}
+			return list, nil
+		},
+		DeleteListFunc: func(ctx context.Context, listID string) error { return nil },
+		GetCardsFunc: func(ctx context.Context, listID string) ([]planka.Card, error) {
+			return []planka.Card{card}, nil
+		},
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			c := card
+			return &c, nil
+		},
+		CreateCardFunc: func(ctx context.Context, req planka.CreateCardRequest) (*planka.Card, error) {
+			c := &planka.Card{ID: "402", Name: req.Name, ListID: req.ListID, DueDate: req.DueDate}
+			if req.Position != nil {
+				c.Position = *req.Position
+			}
+			return c, nil
+		},
+		UpdateCardFunc: func(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error) {
+			c := card
+			if req.Name != nil {
+				c.Name = *req.Name
+			}
+			return &c, nil
+		},
+		DeleteCardFunc: func(ctx context.Context, cardID string) error { return nil },
+		MoveCardFunc: func(ctx context.Context, cardID, listID string, position float64) (*planka.Card, error) {
+			c := card
+			c.ListID, c.Position = listID, position
+			return &c, nil
+		},
+		GetTasksFunc: func(ctx context.Context, cardID string) ([]planka.Task, error) {
+			return []planka.Task{{ID: "501", Name: "Outline", CardID: cardID, IsCompleted: true}}, nil
+		},
+		CreateTaskFunc: func(ctx context.Context, req planka.CreateTaskRequest) (*planka.Task, error) {
+			return &planka.Task{ID: "502", Name: req.Name, CardID: req.CardID}, nil
+		},
+		UpdateTaskFunc: func(ctx context.Context, taskID string, req planka.UpdateTaskRequest) (*planka.Task, error) {
+			return &planka.Task{ID: taskID, Name: "Outline", IsCompleted: req.IsCompleted != nil && *req.IsCompleted}, nil
+		},
+		DeleteTaskFunc: func(ctx context.Context, taskID string) error { return nil },
+		GetCommentsFunc: func(ctx context.Context, cardID string) ([]planka.Comment, error) {
+			return []planka.Comment{{ID: "601", Text: "Looks good", CardID: cardID, UserID: "701"}}, nil
+		},
+		CreateCommentFunc: func(ctx context.Context, req planka.CreateCommentRequest) (*planka.Comment, error) {
+			return &planka.Comment{ID: "602", Text: req.Text, CardID: req.CardID}, nil
+		},
+		DeleteCommentFunc: func(ctx context.Context, commentID string) error { return nil },
+		GetStopwatchFunc: func(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+			return &planka.Stopwatch{CardID: cardID, Duration: 600}, nil
+		},
+		StartStopwatchFunc: func(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+			return &planka.Stopwatch{CardID: cardID, StartedAt: &started, Duration: 600}, nil
+		},
+		StopStopwatchFunc: func(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+			return &planka.Stopwatch{CardID: cardID, Duration: 900}, nil
+		},
+		ResetStopwatchFunc: func(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+			return &planka.Stopwatch{CardID: cardID}, nil
+		},
+	}
+	session := connect(t, NewServer(client))
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+		// want is the expected JSON output, or the fields it must have for objects
+		want string
+	}{
+		{"get_projects", nil, `{"items": [{"id": "101", "name": "Launch"}, {"id": "102", "name": "Ops"}], "totalCount": 2, "returnedCount": 2, "truncated": false}`},
+		{"get_projects", map[string]interface{}{"limit": 1},
+			`{"items": [{"id": "101"}], "totalCount": 2, "returnedCount": 1, "truncated": true, "nextCursor": "` + encodeCursor(1) + `", "hasMore": true}`},
+		{"get_projects", map[string]interface{}{"sortBy": "name", "sortOrder": "desc"}, `{"items": [{"id": "102", "name": "Ops"}, {"id": "101", "name": "Launch"}]}`},
+		{"get_projects", map[string]interface{}{"limit": 1, "offset": 1}, `{"items": [{"id": "102"}], "totalCount": 2, "returnedCount": 1, "truncated": true}`},
+		{"get_project", map[string]interface{}{"projectId": "101"}, `{"id": "101", "name": "Launch", "createdAt": "2024-05-01T09:00:00Z"}`},
+		{"create_project", map[string]interface{}{"name": "Hiring", "description": "Q3"}, `{"id": "103", "name": "Hiring", "description": "Q3"}`},
+		{"delete_project", map[string]interface{}{"projectId": "101"}, `"Project 101 deleted successfully"`},
+		{"get_boards", map[string]interface{}{"projectId": "101", "page": 1, "pageSize": 10},
+			`{"items": [{"id": "201", "projectId": "101"}], "totalCount": 1, "returnedCount": 1, "truncated": false, "page": 1, "pageSize": 10}`},
+		{"get_board", map[string]interface{}{"boardId": "201"}, `{"id": "201", "name": "Roadmap", "projectId": "101"}`},
+		{"create_board", map[string]interface{}{"projectId": "101", "name": "Backlog"}, `{"id": "202", "name": "Backlog", "projectId": "101"}`},
+		{"delete_board", map[string]interface{}{"boardId": "201"}, `"Board 201 deleted successfully"`},
+		{"get_lists", map[string]interface{}{"boardId": "201"}, `{"items": [{"id": "301", "position": 65535}, {"id": "302", "position": 131070}], "totalCount": 2}`},
+		{"get_list", map[string]interface{}{"listId": "301"}, `{"id": "301", "name": "Todo", "boardId": "201"}`},
+		{"create_list", map[string]interface{}{"boardId": "201", "name": "Doing", "position": 131070}, `{"id": "303", "name": "Doing", "position": 131070}`},
+		{"delete_list", map[string]interface{}{"listId": "301"}, `"List 301 deleted successfully"`},
+		{"get_cards", map[string]interface{}{"listId": "301"}, `{"items": [{"id": "401", "name": "Write docs", "listId": "301"}], "returnedCount": 1}`},
+		{"get_card", map[string]interface{}{"cardId": "401"}, `{"id": "401", "name": "Write docs", "position": 65535}`},
+		{"create_card", map[string]interface{}{"listId": "301", "name": "Ship", "dueDate": "2024-06-30T17:00:00Z"},
+			`{"id": "402", "name": "Ship", "listId": "301", "dueDate": "` + due.Format(time.RFC3339) + `"}`},
+		{"create_card", map[string]interface{}{"listId": "301", "name": "Ship", "position": "top"}, `{"id": "402", "position": 32767.5}`},
+		{"update_card", map[string]interface{}{"cardId": "401", "name": "Write the docs"}, `{"id": "401", "name": "Write the docs"}`},
+		{"delete_card", map[string]interface{}{"cardId": "401"}, `{"success": true}`},
+		{"delete_card", map[string]interface{}{"cardId": "401", "dryRun": true}, `{"dryRun": true, "tool": "delete_card", "calls": []}`},
+		{"move_card", map[string]interface{}{"cardId": "401", "listId": "302", "position": 100}, `{"id": "401", "listId": "302", "position": 100}`},
+		{"get_tasks", map[string]interface{}{"cardId": "401"}, `{"items": [{"id": "501", "name": "Outline", "cardId": "401", "isCompleted": true}]}`},
+		{"create_task", map[string]interface{}{"cardId": "401", "name": "Review"}, `{"id": "502", "name": "Review", "cardId": "401"}`},
+		{"update_task", map[string]interface{}{"taskId": "501", "isCompleted": true}, `{"id": "501", "isCompleted": true}`},
+		{"delete_task", map[string]interface{}{"taskId": "501"}, `{"success": true}`},
+		{"get_comments", map[string]interface{}{"cardId": "401"}, `{"items": [{"id": "601", "text": "Looks good", "userId": "701"}]}`},
+		{"create_comment", map[string]interface{}{"cardId": "401", "text": "Done"}, `{"id": "602", "text": "Done", "cardId": "401"}`},
+		{"get_stopwatch", map[string]interface{}{"cardId": "401"}, `{"cardId": "401", "duration": 600}`},
+		{"start_stopwatch", map[string]interface{}{"cardId": "401"}, `{"startedAt": "2024-05-02T08:00:00Z", "duration": 600}`},
+		{"stop_stopwatch", map[string]interface{}{"cardId": "401"}, `{"duration": 900}`},
+		{"reset_stopwatch", map[string]interface{}{"cardId": "401"}, `{"duration": 0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			text, isError, err := callToolResult(t, session, tt.tool, tt.args)
+			if err != nil || isError {
+				t.Fatalf("%s(%v) failed: %v %s", tt.tool, tt.args, err, text)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal([]byte(text), &got); err != nil {
+				// Plain text results are compared as JSON strings
+				got = text
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid want %s: %v", tt.want, err)
+			}
+			if !containsJSON(got, want) {
+				t.Errorf("%s(%v) = %s, want %s", tt.tool, tt.args, text, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolCallsClient(t *testing.T) {
+	client := &plankamock.Client{
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Write docs", ListID: "301"}, nil
+		},
+		UpdateCardFunc: func(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: *req.Name, ListID: "301"}, nil
+		},
+	}
+	session := connect(t, NewServer(client))
+	callTool(t, session, "update_card", map[string]interface{}{"cardId": "401", "name": "Write the docs"}, nil)
+
+	var methods []string
+	for _, call := range client.Calls() {
+		methods = append(methods, call.Method)
+	}
+	if got, want := fmt.Sprint(methods), "[GetCard UpdateCard]"; got != want {
+		t.Errorf("update_card called %s, want %s", got, want)
+	}
+	req := client.Calls()[1].Args[1].(planka.UpdateCardRequest)
+	if req.Name == nil || *req.Name != "Write the docs" || req.ListID != nil || req.DueDate != nil {
+		t.Errorf("update_card sent %+v, want only the name changed", req)
+	}
+}
+
+func TestUpdateCardClearsFields(t *testing.T) {
+	due := time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC)
+	client := &plankamock.Client{
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Write docs", Description: "Draft", ListID: "301", DueDate: &due}, nil
+		},
+		UpdateCardFunc: func(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Write docs", Description: "Draft", ListID: "301"}, nil
+		},
+	}
+	session := connect(t, NewServer(client))
+	callTool(t, session, "update_card", map[string]interface{}{"cardId": "401", "clearFields": []string{"dueDate"}}, nil)
+
+	req := client.Calls()[1].Args[1].(planka.UpdateCardRequest)
+	if !req.ClearDueDate || req.ClearDescription {
+		t.Errorf("update_card sent %+v, want only the due date cleared", req)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(body), `{"dueDate":null}`; got != want {
+		t.Errorf("update_card request body = %s, want %s", got, want)
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	due := time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC)
+	session := connect(t, NewServer(&plankamock.Client{
+		GetCardsFunc: func(ctx context.Context, listID string) ([]planka.Card, error) {
+			return []planka.Card{
+				{ID: "401", Name: "Write docs", Description: "Cover | pipes\nand lines", ListID: listID, Position: 65535, DueDate: &due},
+				{ID: "402", Name: "Ship", ListID: listID, Position: 131070},
+			}, nil
+		},
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Ship", ListID: "301"}, nil
+		},
+	}))
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+		want string
+	}{
+		{"get_cards", map[string]interface{}{"listId": "301", "outputFormat": "table", "limit": 1},
+			"| id | name | description | listId | position | dueDate | createdAt | updatedAt |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| 401 | Write docs | Cover \\| pipes and lines | 301 | 65535 | 2024-05-31T17:00:00Z | 0001-01-01T00:00:00Z | 0001-01-01T00:00:00Z |\n" +
+				"\ntotalCount: 2, returnedCount: 1, truncated: true, nextCursor: " + encodeCursor(1) + ", hasMore: true\n"},
+		{"get_cards", map[string]interface{}{"listId": "301", "outputFormat": "markdown"},
+			"- **Write docs** — id: 401, description: Cover | pipes and lines, listId: 301, position: 65535, dueDate: 2024-05-31T17:00:00Z, createdAt: 0001-01-01T00:00:00Z, updatedAt: 0001-01-01T00:00:00Z\n" +
+				"- **Ship** — id: 402, listId: 301, position: 131070, createdAt: 0001-01-01T00:00:00Z, updatedAt: 0001-01-01T00:00:00Z\n" +
+				"\ntotalCount: 2, returnedCount: 2\n"},
+		{"get_card", map[string]interface{}{"cardId": "402", "outputFormat": "markdown"},
+			"- **id**: 402\n- **name**: Ship\n- **listId**: 301\n- **position**: 0\n- **createdAt**: 0001-01-01T00:00:00Z\n- **updatedAt**: 0001-01-01T00:00:00Z\n"},
+	}
+	for _, tt := range tests {
+		text, isError, err := callToolResult(t, session, tt.tool, tt.args)
+		if err != nil || isError {
+			t.Fatalf("%s(%v) failed: %v %s", tt.tool, tt.args, err, text)
+		}
+		if text != tt.want {
+			t.Errorf("%s(%v) =\n%s\nwant\n%s", tt.tool, tt.args, text, tt.want)
+		}
+	}
+
+	_, _, err := callToolResult(t, session, "get_cards", map[string]interface{}{"listId": "301", "outputFormat": "csv"})
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("get_cards with outputFormat csv: error %v, want invalid params", err)
+	}
+}
+
+func TestSetDescriptionSectionRemovesEmpty(t *testing.T) {
+	client := &plankamock.Client{
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Write docs", Description: "Intro\n\n## Notes\nOld notes\n\n## Links\nWiki", ListID: "301"}, nil
+		},
+		UpdateCardFunc: func(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Write docs", Description: *req.Description, ListID: "301"}, nil
+		},
+	}
+	session := connect(t, NewServer(client))
+	callTool(t, session, "set_description_section", map[string]interface{}{"cardId": "401", "section": "Notes", "content": ""}, nil)
+
+	req := client.Calls()[1].Args[1].(planka.UpdateCardRequest)
+	if req.Description == nil || strings.Contains(*req.Description, "Notes") || !strings.Contains(*req.Description, "## Links") {
+		t.Errorf("set_description_section with empty content sent %+v, want the Notes section removed", req)
+	}
+
+	_, _, err := callToolResult(t, session, "set_description_section", map[string]interface{}{"cardId": "401", "section": "Notes"})
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "missing required argument content") {
+		t.Errorf("set_description_section without content: error %v, want content reported missing", err)
+	}
+}
+
+func TestTruncatedListCounts(t *testing.T) {
+	var cards []planka.Card
+	for i := 0; i < 50; i++ {
+		cards = append(cards, planka.Card{ID: fmt.Sprint(401 + i), Name: "Card", ListID: "301"})
+	}
+	s := NewServer(&plankamock.Client{
+		GetCardsFunc: func(ctx context.Context, listID string) ([]planka.Card, error) { return cards, nil },
+	})
+	s.LimitResultSize(2000)
+	session := connect(t, s)
+
+	text, _, err := callToolResult(t, session, "get_cards", map[string]interface{}{"listId": "301"})
+	if err != nil {
+		t.Fatalf("get_cards: %v", err)
+	}
+	var result struct {
+		Items         []planka.Card `json:"items"`
+		TotalCount    int           `json:"totalCount"`
+		ReturnedCount int           `json:"returnedCount"`
+		Truncated     bool          `json:"truncated"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("decoding %q: %v", text, err)
+	}
+	if result.TotalCount != 50 || result.ReturnedCount != len(result.Items) || result.ReturnedCount >= 50 || !result.Truncated {
+		t.Errorf("get_cards = %d of %d items (returnedCount %d, truncated %v), want part of the 50 cards flagged as truncated",
+			len(result.Items), result.TotalCount, result.ReturnedCount, result.Truncated)
+	}
+}
+
+func TestTruncatedEmptyList(t *testing.T) {
+	s := NewServer(&plankamock.Client{
+		GetCardsFunc: func(ctx context.Context, listID string) ([]planka.Card, error) { return nil, nil },
+	})
+	// Even the envelope of no cards is larger than this
+	s.LimitResultSize(20)
+	session := connect(t, s)
+
+	text, isError, err := callToolResult(t, session, "get_cards", map[string]interface{}{"listId": "301"})
+	if err != nil || isError {
+		t.Fatalf("get_cards: %v %s", err, text)
+	}
+	if len(text) <= 20 || !strings.HasPrefix(text[20:], "\n[truncated:") {
+		t.Errorf("get_cards of an empty list = %q, want it cut at 20 bytes", text)
+	}
+}
+
+func TestWatchTimeoutWithinWriteTimeout(t *testing.T) {
+	s := NewServer(&plankamock.Client{})
+	s.writeTimeout = 2 * time.Minute
+	session := connect(t, s)
+
+	_, _, err := callToolResult(t, session, "watch_board", map[string]interface{}{"boardId": "201", "timeoutSeconds": 150})
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "between 0 and 110") {
+		t.Errorf("watch_board waiting past the write timeout: error %v, want timeoutSeconds capped at 110", err)
+	}
+}
+
+func TestGetCommentsFlagsItemLimit(t *testing.T) {
+	session := connect(t, NewServer(&plankamock.Client{
+		GetCommentsFunc: func(ctx context.Context, cardID string) ([]planka.Comment, error) {
+			return []planka.Comment{{ID: "601", CardID: cardID}}, fmt.Errorf("%w: stopped fetching at 1 items", planka.ErrTruncated)
+		},
+	}))
+
+	text, isError, err := callToolResult(t, session, "get_comments", map[string]interface{}{"cardId": "401"})
+	if err != nil || isError {
+		t.Fatalf("get_comments failed: %v %s", err, text)
+	}
+	var got, want interface{}
+	json.Unmarshal([]byte(text), &got)
+	json.Unmarshal([]byte(`{"items": [{"id": "601"}], "returnedCount": 1, "truncated": true}`), &want)
+	if !containsJSON(got, want) || !strings.Contains(text, "PLANKA_MAX_ITEMS") {
+		t.Errorf("get_comments = %s, want the comment flagged as truncated with a note", text)
+	}
+}
+
+func TestOutputFormatKeepsResultSizeLimit(t *testing.T) {
+	var cards []planka.Card
+	for i := 0; i < 50; i++ {
+		cards = append(cards, planka.Card{ID: fmt.Sprint(401 + i), Name: "Card", ListID: "301"})
+	}
+	s := NewServer(&plankamock.Client{
+		GetCardsFunc: func(ctx context.Context, listID string) ([]planka.Card, error) { return cards, nil },
+		GetCardFunc: func(ctx context.Context, cardID string) (*planka.Card, error) {
+			return &planka.Card{ID: cardID, Name: "Long", Description: strings.Repeat("word ", 20), ListID: "301"}, nil
+		},
+	})
+	s.LimitResultSize(1000)
+	session := connect(t, s)
+
+	result, err := session.CallTool(context.Background(), &mcpsdk.CallToolParams{
+		Name:      "get_cards",
+		Arguments: map[string]interface{}{"listId": "301", "outputFormat": "markdown"},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("get_cards: %v %v", err, result)
+	}
+	text := result.Content[0].(*mcpsdk.TextContent).Text
+	if bullets := strings.Count(text, "- **Card**"); bullets == 0 || bullets >= 50 {
+		t.Errorf("get_cards rendered %d cards, want only the ones that fit", bullets)
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(*mcpsdk.TextContent).Text, "[truncated:") {
+		t.Errorf("get_cards content = %v, want the truncation note after the rendered cards", result.Content)
+	}
+
+	s.LimitResultSize(100)
+	text, _, err = callToolResult(t, session, "get_card", map[string]interface{}{"cardId": "401", "outputFormat": "table"})
+	if err != nil || len(text) <= 100 || !strings.HasPrefix(text[100:], "\n[truncated:") {
+		t.Errorf("get_card as a table = %q, %v, want it cut at 100 bytes", text, err)
+	}
+}
@@ -1,198 +1,473 @@
 package mcp
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
-	"sync"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// sessionState tracks initialization state per session
-type sessionState struct {
-	initialized bool
-	mu          sync.RWMutex
+// HTTPOptions configures the HTTP server
+type HTTPOptions struct {
+	// APIKeys are the keys accepted on the MCP endpoint
+	APIKeys []string
+	// OAuth enables OAuth 2.1 access tokens on the MCP endpoint.
+	// Without API keys or OAuth the endpoint is unauthenticated.
+	OAuth *OAuthOptions
+	// RateLimit limits how fast each client may send requests; nil disables rate limiting
+	RateLimit *RateLimitOptions
+	// SessionTimeout closes sessions idle for longer than this; zero keeps them forever
+	SessionTimeout time.Duration
+	// MaxSessions caps the number of concurrent sessions; zero means unlimited
+	MaxSessions int
+	// MaxBodyBytes caps the size of request bodies; zero uses defaultMaxBodyBytes
+	MaxBodyBytes int64
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound how long a client may
+	// take to send a request, and how long responses and idle keep-alive connections may last,
+	// so slow clients cannot tie up connections; zero means no timeout. The write timeout does
+	// not apply to the GET /mcp event stream, which stays open for server notifications.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// BasePath serves all endpoints under this prefix, e.g. /planka serves the MCP endpoint
+	// at /planka/mcp, for reverse proxies that route several services by path
+	BasePath string
+	// UnixSocket listens on this Unix domain socket instead of a TCP port.
+	// The socket is only accessible to the user running the server.
+	UnixSocket string
+	// Listener is served instead of listening on a port or UnixSocket, e.g. a socket
+	// passed by systemd socket activation
+	Listener net.Listener
+	// Ready is called once the server listens, e.g. to notify a service manager
+	Ready func()
+	// CalendarTokens enable the /calendar/{boardId}.ics feeds of card due dates and are the
+	// tokens accepted on them; nil disables the feeds
+	CalendarTokens []string
+	// WebhookToken enables the /webhooks/planka endpoint receiving Planka's webhooks and is
+	// the access token they must carry; empty disables the endpoint
+	WebhookToken string
+	// CORSOrigins are the origins browsers may call the endpoints from; empty allows any
+	CORSOrigins []string
 }
 
-// HTTP server with session management
+// defaultMaxBodyBytes is the request body limit used when none is configured
+const defaultMaxBodyBytes = 1 << 20
+
+// httpServer serves the MCP Streamable HTTP transport alongside auxiliary endpoints
 type httpServer struct {
-	server  *Server
-	sessions map[string]*sessionState
-	mu      sync.RWMutex
+	server      *Server
+	apiKeys     []string
+	oauth       *OAuthOptions
+	tokens      *tokenIntrospector
+	maxSessions int
+	maxBody     int64
+	// calendarTokens are the tokens accepted on the calendar feeds
+	calendarTokens []string
+	// webhookToken is the access token Planka's webhooks authenticate with
+	webhookToken string
+	// corsOrigins are the origins allowed by CORS; empty allows any
+	corsOrigins []string
 }
 
 // StartHTTP starts the MCP server in HTTP mode
-func (s *Server) StartHTTP(addr string, port int) error {
+// Sessions are tracked by the SDK through the standard Mcp-Session-Id header.
+func (s *Server) StartHTTP(addr string, port int, opts HTTPOptions) error {
 	httpSrv := &httpServer{
-		server:   s,
-		sessions: make(map[string]*sessionState),
+		server:         s,
+		apiKeys:        opts.APIKeys,
+		oauth:          opts.OAuth,
+		maxSessions:    opts.MaxSessions,
+		maxBody:        opts.MaxBodyBytes,
+		calendarTokens: opts.CalendarTokens,
+		webhookToken:   opts.WebhookToken,
+		corsOrigins:    opts.CORSOrigins,
+	}
+	if httpSrv.maxBody <= 0 {
+		httpSrv.maxBody = defaultMaxBodyBytes
+	}
+	s.writeTimeout = opts.WriteTimeout
+	if opts.OAuth != nil {
+		tokens, err := newTokenIntrospector(context.Background(), *opts.OAuth)
+		if err != nil {
+			return err
+		}
+		httpSrv.tokens = tokens
+	}
+
+	if opts.RateLimit != nil {
+		s.server.AddReceivingMiddleware(newRateLimiter(*opts.RateLimit).middleware)
 	}
 
+	// Responses are plain JSON rather than SSE streams so simple HTTP clients keep working.
+	// The SDK issues a random Mcp-Session-Id on initialize and closes sessions once idle for SessionTimeout.
+	mcpHandler := httpSrv.bodyLimitMiddleware(streamDeadlineMiddleware(httpSrv.sessionMiddleware(mcpsdk.NewStreamableHTTPHandler(func(*http.Request) *mcpsdk.Server {
+		return s.server
+	}, &mcpsdk.StreamableHTTPOptions{
+		JSONResponse:   true,
+		SessionTimeout: opts.SessionTimeout,
+	}))))
+
 	mux := http.NewServeMux()
-	
+
 	// Main MCP JSON-RPC endpoint
-	mux.HandleFunc("/mcp", httpSrv.handleMCPRequest)
-	mux.HandleFunc("/", httpSrv.handleMCPRequest) // Also support root path
-	
-	// Health check endpoint
-	mux.HandleFunc("/health", httpSrv.handleHealth)
-	
-	serverAddr := fmt.Sprintf("%s:%d", addr, port)
-	log.Printf("HTTP server listening on %s", serverAddr)
-	log.Printf("MCP endpoint: http://%s/mcp", serverAddr)
-	
-	return http.ListenAndServe(serverAddr, httpSrv.corsMiddleware(mux))
+	mcpEndpoint, err := httpSrv.authMiddleware(mcpHandler)
+	if err != nil {
+		return err
+	}
+	basePath := normalizeBasePath(opts.BasePath)
+	mux.Handle(basePath+"/mcp", mcpEndpoint)
+	mux.Handle(basePath+"/", mcpEndpoint) // Also support root path
+
+	// Protected resource metadata (RFC 9728) lets OAuth clients discover the authorization server
+	if httpSrv.oauth != nil {
+		metadataPath, err := httpSrv.oauth.resourceMetadataPath()
+		if err != nil {
+			return err
+		}
+		metadataHandler := auth.ProtectedResourceMetadataHandler(httpSrv.oauth.resourceMetadata())
+		mux.Handle(metadataPath, metadataHandler)
+		// Some clients only probe the root well-known location
+		if metadataPath != resourceMetadataRoot {
+			mux.Handle(resourceMetadataRoot, metadataHandler)
+		}
+	}
+
+	// Health check endpoint (always unauthenticated); probes may bypass the proxy, so it
+	// stays available at the root too
+	mux.HandleFunc(basePath+"/health", httpSrv.handleHealth)
+	if basePath != "" {
+		mux.HandleFunc("/health", httpSrv.handleHealth)
+	}
+
+	// Prometheus metrics of the Planka client, protected like the MCP endpoint
+	if s.metrics != nil {
+		metricsEndpoint, err := httpSrv.authMiddleware(http.HandlerFunc(httpSrv.handleMetrics))
+		if err != nil {
+			return err
+		}
+		mux.Handle(basePath+"/metrics", metricsEndpoint)
+	}
+
+	// iCalendar feeds of due dates, read with the server's own Planka credentials
+	if len(httpSrv.calendarTokens) > 0 {
+		if s.client == nil {
+			return errors.New("calendar feeds require PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+		}
+		mux.HandleFunc(basePath+"/calendar/{file}", httpSrv.handleCalendar)
+	}
+
+	// Planka webhooks, relayed to clients as notifications
+	if httpSrv.webhookToken != "" {
+		mux.HandleFunc(basePath+"/webhooks/planka", httpSrv.handlePlankaWebhook)
+		s.startEventNotifications(context.Background())
+	}
+
+	// A Unix socket is protected by its file permissions instead
+	unix := opts.UnixSocket != "" || (opts.Listener != nil && opts.Listener.Addr().Network() == "unix")
+	if len(httpSrv.apiKeys) == 0 && httpSrv.oauth == nil && !unix {
+		log.Printf("Warning: no API keys or OAuth configured, the MCP endpoint is unauthenticated")
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", addr, port),
+		Handler:           httpSrv.corsMiddleware(mux),
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+	}
+
+	listener := opts.Listener
+	switch {
+	case listener != nil:
+		log.Printf("HTTP server listening on the passed socket %s", listener.Addr())
+		if unix {
+			log.Printf("MCP endpoint: http://localhost%s/mcp (via %s)", basePath, listener.Addr())
+		} else {
+			log.Printf("MCP endpoint: http://%s%s/mcp", listener.Addr(), basePath)
+		}
+	case opts.UnixSocket != "":
+		var err error
+		if listener, err = listenUnix(opts.UnixSocket); err != nil {
+			return err
+		}
+		log.Printf("HTTP server listening on unix socket %s", opts.UnixSocket)
+		log.Printf("MCP endpoint: http://localhost%s/mcp (via %s)", basePath, opts.UnixSocket)
+	default:
+		var err error
+		if listener, err = net.Listen("tcp", server.Addr); err != nil {
+			return err
+		}
+		log.Printf("HTTP server listening on %s", server.Addr)
+		log.Printf("MCP endpoint: http://%s%s/mcp", server.Addr, basePath)
+	}
+
+	if opts.Ready != nil {
+		opts.Ready()
+	}
+	return server.Serve(listener)
+}
+
+// normalizeBasePath turns a configured base path into the form "/prefix", or "" for none
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// streamDeadlineMiddleware lifts the write timeout for GET requests, which open the
+// long-lived event stream the server pushes notifications on
+func streamDeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
-// corsMiddleware adds CORS headers to responses
+// listenUnix listens on a Unix domain socket at path that only the current user can connect to.
+// A socket left behind by a previous run is replaced; any other file at path is an error.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set permissions on unix socket %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// corsMiddleware adds CORS headers to responses. Browsers on origins that are not allowed
+// get no Access-Control-Allow-Origin header, so they cannot read the responses.
 func (h *httpServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("Content-Type", "application/json")
-		
+		if len(h.corsOrigins) == 0 {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); origin != "" && slices.Contains(h.corsOrigins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Authorization, X-API-Key, X-Planka-Token, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id, WWW-Authenticate")
+
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
-		
+
 		next.ServeHTTP(w, r)
 	})
 }
 
-// handleHealth handles health check requests
-func (h *httpServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	
-	response := map[string]interface{}{
-		"status": "ok",
-		"service": "planka-mcp",
+// authMiddleware rejects requests that present neither one of the configured API keys,
+// as "Authorization: Bearer <key>" or in the X-API-Key header, nor a valid OAuth access token.
+// Rejections of OAuth-protected endpoints carry a WWW-Authenticate header pointing at the
+// protected resource metadata so clients can start the authorization flow.
+func (h *httpServer) authMiddleware(next http.Handler) (http.Handler, error) {
+	var oauthHandler http.Handler
+	if h.oauth != nil {
+		metadataURL, err := h.oauth.resourceMetadataURL()
+		if err != nil {
+			return nil, err
+		}
+		oauthHandler = auth.RequireBearerToken(h.tokens.verify, &auth.RequireBearerTokenOptions{
+			ResourceMetadataURL: metadataURL,
+			Scopes:              h.oauth.Scopes,
+		})(next)
 	}
-	
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case len(h.apiKeys) == 0 && oauthHandler == nil:
+			next.ServeHTTP(w, r)
+		case h.validAPIKey(requestAPIKey(r)):
+			next.ServeHTTP(w, r)
+		case oauthHandler != nil:
+			oauthHandler.ServeHTTP(w, r)
+		default:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="planka-mcp"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		}
+	}), nil
 }
 
-// handleMCPRequest handles MCP JSON-RPC requests over HTTP
-func (h *httpServer) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed. Use POST for JSON-RPC requests.", http.StatusMethodNotAllowed)
-		return
-	}
+// bodyLimitMiddleware rejects request bodies larger than maxBody with a JSON-RPC error,
+// so oversized payloads cannot exhaust memory
+func (h *httpServer) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-	// Get or create session based on a simple identifier
-	// For stateless HTTP, we could use a session token, but for simplicity,
-	// we'll track by a combination of factors or make initialization per-request
-	sessionID := h.getSessionID(r)
-	session := h.getOrCreateSession(sessionID)
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeJSONRPCError(w, http.StatusRequestEntityTooLarge, jsonrpc.CodeInvalidRequest,
+					fmt.Sprintf("request body exceeds %d bytes", h.maxBody))
+				return
+			}
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
 
-	// Decode JSON-RPC request
-	var request map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.sendHTTPError(w, nil, fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
-		return
-	}
+		next.ServeHTTP(w, r)
+	})
+}
 
-	method, _ := request["method"].(string)
-	id, _ := request["id"]
+// writeJSONRPCError writes a JSON-RPC error response not tied to any request id
+func writeJSONRPCError(w http.ResponseWriter, status int, code int64, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
 
-	// Handle initialization
-	if method == "initialize" {
-		session.mu.Lock()
-		session.initialized = true
-		session.mu.Unlock()
-		
-		response := h.server.buildInitializeResponse(id)
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response)
-		return
-	}
+// sessionMiddleware enforces Mcp-Session-Id handling on top of the SDK transport:
+// only initialize may be sent without a session ID, and new sessions are refused once
+// maxSessions are active so a misbehaving client cannot exhaust memory
+func (h *httpServer) sessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.Header.Get("Mcp-Session-Id") == "" {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
 
-	// Handle initialized notification
-	if method == "notifications/initialized" {
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"result":  nil,
-			"id":      id,
-		})
-		return
-	}
+			if !isInitializeRequest(body) {
+				http.Error(w, "Bad Request: missing Mcp-Session-Id header; send initialize first", http.StatusBadRequest)
+				return
+			}
 
-	// Check if initialized (for HTTP, we're more lenient - allow requests without explicit init)
-	session.mu.RLock()
-	initialized := session.initialized
-	session.mu.RUnlock()
+			if h.maxSessions > 0 {
+				active := 0
+				for session := range h.server.server.Sessions() {
+					// A stdio session served alongside HTTP has no ID and does not count
+					if session.ID() != "" {
+						active++
+					}
+				}
+				if active >= h.maxSessions {
+					w.Header().Set("Retry-After", "60")
+					http.Error(w, "Too many active sessions", http.StatusServiceUnavailable)
+					return
+				}
+			}
+		}
 
-	// For HTTP mode, we can auto-initialize if not done
-	if !initialized && method != "initialize" {
-		// Auto-initialize for HTTP mode
-		session.mu.Lock()
-		session.initialized = true
-		session.mu.Unlock()
-	}
+		next.ServeHTTP(w, r)
+	})
+}
 
-	// Handle the request
-	response, err := h.server.handleMCPRequest(request)
-	if err != nil {
-		h.sendHTTPError(w, request, err, http.StatusOK) // JSON-RPC errors still return 200
-		return
+// isInitializeRequest reports whether a JSON-RPC message (or batch) contains an initialize request.
+// Malformed bodies are passed through so the transport can report the parse error.
+func isInitializeRequest(body []byte) bool {
+	var messages []struct {
+		Method string `json:"method"`
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &messages); err != nil {
+			return true
+		}
+	} else {
+		var message struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(trimmed, &message); err != nil {
+			return true
+		}
+		messages = append(messages, message)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Failed to encode response: %v", err)
+	for _, message := range messages {
+		if message.Method == "initialize" {
+			return true
+		}
 	}
+	return false
 }
 
-// getSessionID generates a session ID from the request
-// For simplicity, we can use IP + User-Agent, or a session token if provided
-func (h *httpServer) getSessionID(r *http.Request) string {
-	// Check for session token in header
-	if token := r.Header.Get("X-Session-Token"); token != "" {
-		return token
+// requestAPIKey extracts the API key presented by a request, if any
+func requestAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if scheme, token, ok := strings.Cut(auth, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
 	}
-	
-	// Fallback to IP + User-Agent combination
-	return r.RemoteAddr + r.Header.Get("User-Agent")
+	return r.Header.Get("X-API-Key")
 }
 
-// getOrCreateSession gets or creates a session state
-func (h *httpServer) getOrCreateSession(sessionID string) *sessionState {
-	h.mu.RLock()
-	session, exists := h.sessions[sessionID]
-	h.mu.RUnlock()
-	
-	if exists {
-		return session
-	}
-	
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	
-	// Double-check after acquiring write lock
-	if session, exists := h.sessions[sessionID]; exists {
-		return session
-	}
-	
-	session = &sessionState{initialized: false}
-	h.sessions[sessionID] = session
-	return session
+// validAPIKey reports whether key is one of the configured API keys
+// Keys are compared in constant time so response timing does not leak them.
+func (h *httpServer) validAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	valid := false
+	for _, apiKey := range h.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			valid = true
+		}
+	}
+	return valid
 }
 
-// sendHTTPError sends a JSON-RPC error response
-func (h *httpServer) sendHTTPError(w http.ResponseWriter, request map[string]interface{}, err error, statusCode int) {
-	id := interface{}(nil)
-	if request != nil {
-		id, _ = request["id"]
+// handleHealth handles health check requests
+func (h *httpServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"service": "planka-mcp",
 	}
-	
-	response := h.server.buildErrorResponse(id, err)
-	w.WriteHeader(statusCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
-
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// instanceArgument is the tool argument selecting a Planka instance
+const instanceArgument = "instance"
+
+// instanceKey is the context key of the Planka instance a request targets
+type instanceKey struct{}
+
+// SetInstances configures several named Planka servers. Every tool gains an optional
+// instance argument choosing which one it acts on; defaultName is used when it is omitted.
+func (s *Server) SetInstances(instances map[string]PlankaClient, defaultName string) {
+	s.instances = instances
+	s.defaultInstance = defaultName
+	s.registerTools()
+}
+
+// instanceNames returns the names of the configured instances in order
+func (s *Server) instanceNames() []string {
+	names := make([]string, 0, len(s.instances))
+	for name := range s.instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withInstanceArgument returns a copy of tool whose inputSchema accepts an instance argument
+func withInstanceArgument(tool *mcpsdk.Tool, names []string, defaultName string) *mcpsdk.Tool {
+	schema := maps.Clone(tool.InputSchema.(map[string]interface{}))
+	properties := maps.Clone(schema["properties"].(map[string]interface{}))
+	properties[instanceArgument] = map[string]interface{}{
+		"type":        "string",
+		"enum":        names,
+		"description": "The Planka instance to use (default: " + defaultName + ")",
+	}
+	schema["properties"] = properties
+
+	clone := *tool
+	clone.InputSchema = schema
+	return &clone
+}
+
+// routeInstance wraps a tool handler so its instance argument selects the Planka client
+func (s *Server) routeInstance(next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		var args map[string]interface{}
+		if len(req.Params.Arguments) > 0 {
+			// Malformed arguments are reported by the tool handler itself
+			json.Unmarshal(req.Params.Arguments, &args)
+		}
+
+		if value, ok := args[instanceArgument]; ok && value != nil {
+			name, ok := value.(string)
+			if !ok {
+				return nil, invalidParams("%s must be a string", instanceArgument)
+			}
+			if _, ok := s.instances[name]; !ok {
+				return nil, invalidParams("unknown instance %q, expected one of: %s", name, strings.Join(s.instanceNames(), ", "))
+			}
+			ctx = context.WithValue(ctx, instanceKey{}, name)
+		}
+		return next(ctx, req)
+	}
+}
+
+// instanceClient returns the client of the instance selected for a request, if any
+func (s *Server) instanceClient(ctx context.Context) (PlankaClient, bool) {
+	name, ok := ctx.Value(instanceKey{}).(string)
+	if !ok {
+		return nil, false
+	}
+	client, ok := s.instances[name]
+	return client, ok
+}
+
+// backgroundClient returns the client background jobs use for the instance name, the
+// server's own client for an empty name
+func (s *Server) backgroundClient(name string) (PlankaClient, error) {
+	client := s.client
+	if name != "" {
+		instance, ok := s.instances[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown instance %q", name)
+		}
+		client = instance
+	}
+	if client == nil {
+		return nil, errors.New("no Planka credentials configured")
+	}
+	return client, nil
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// orderedList is a list in the order reorder_lists applied
+type orderedList struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Position float64 `json:"position"`
+}
+
+// listOrder is the result of reorder_lists
+type listOrder struct {
+	BoardID string        `json:"boardId"`
+	Lists   []orderedList `json:"lists"`
+	// Updated counts the lists whose position changed
+	Updated int `json:"updated"`
+}
+
+func (s *Server) handleSwapListPositions(ctx context.Context, args swapListPositionsArgs) (interface{}, error) {
+	if args.ListID == args.OtherListID {
+		return nil, invalidParams("listId and otherListId must be different lists")
+	}
+	client := s.clientFor(ctx)
+	list, err := client.GetList(ctx, args.ListID)
+	if err != nil {
+		return nil, err
+	}
+	other, err := client.GetList(ctx, args.OtherListID)
+	if err != nil {
+		return nil, err
+	}
+	if list.BoardID != other.BoardID {
+		return nil, invalidParams("lists %s and %s are on different boards", list.ID, other.ID)
+	}
+
+	first, err := client.UpdateList(ctx, list.ID, planka.UpdateListRequest{Position: &other.Position})
+	if err != nil {
+		return nil, err
+	}
+	second, err := client.UpdateList(ctx, other.ID, planka.UpdateListRequest{Position: &list.Position})
+	if err != nil {
+		return nil, err
+	}
+	s.recordListPositionsUndo(ctx, client, "swap_list_positions", []planka.List{*list, *other})
+	return []planka.List{*first, *second}, nil
+}
+
+func (s *Server) handleReorderLists(ctx context.Context, args reorderListsArgs) (interface{}, error) {
+	if len(args.Lists) == 0 {
+		return nil, invalidParams("lists must name at least one list")
+	}
+	client := s.clientFor(ctx)
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	// Planka 2's archive and trash lists are not columns of the board
+	var columns []planka.List
+	for _, list := range lists {
+		if list.Type != "archive" && list.Type != "trash" {
+			columns = append(columns, list)
+		}
+	}
+	sort.SliceStable(columns, func(i, j int) bool { return columns[i].Position < columns[j].Position })
+
+	ordered, err := orderLists(columns, args.Lists)
+	if err != nil {
+		return nil, err
+	}
+
+	result := listOrder{BoardID: args.BoardID, Lists: []orderedList{}}
+	var moved []planka.List
+	for i, list := range ordered {
+		position := float64(i+1) * planka.PositionGap
+		if list.Position != position {
+			if _, err := client.UpdateList(ctx, list.ID, planka.UpdateListRequest{Position: &position}); err != nil {
+				s.recordListPositionsUndo(ctx, client, "reorder_lists", moved)
+				return nil, fmt.Errorf("failed to move list %s after reordering %d lists: %w", list.ID, result.Updated, err)
+			}
+			moved = append(moved, list)
+			result.Updated++
+		}
+		result.Lists = append(result.Lists, orderedList{ID: list.ID, Name: list.Name, Position: position})
+	}
+	s.recordListPositionsUndo(ctx, client, "reorder_lists", moved)
+	return result, nil
+}
+
+// orderLists puts the lists named in order, by name ignoring case or by ID, first and in
+// that order, followed by the other lists in their current order
+func orderLists(lists []planka.List, order []string) ([]planka.List, error) {
+	placed := map[string]bool{}
+	var ordered []planka.List
+	for _, name := range order {
+		var matches []planka.List
+		for _, list := range lists {
+			if list.ID == name || strings.EqualFold(list.Name, strings.TrimSpace(name)) {
+				matches = append(matches, list)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			return nil, invalidParams("the board has no list %q", name)
+		case len(matches) > 1:
+			return nil, invalidParams("the board has %d lists named %q; use their IDs instead", len(matches), name)
+		case placed[matches[0].ID]:
+			return nil, invalidParams("list %q is named more than once", name)
+		}
+		placed[matches[0].ID] = true
+		ordered = append(ordered, matches[0])
+	}
+	for _, list := range lists {
+		if !placed[list.ID] {
+			ordered = append(ordered, list)
+		}
+	}
+	return ordered, nil
+}
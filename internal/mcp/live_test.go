@@ -0,0 +1,267 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// The live tests run against a real Planka server, and only when PLANKA_LIVE_TEST is set:
+//
+//	PLANKA_LIVE_TEST=1 PLANKA_URL=https://planka.example.com PLANKA_TOKEN=... go test -run Live ./internal/mcp
+//
+// They create a project of their own, so the user needs permission to create projects.
+// Everything they create is named with liveTag and deleted when the test ends, failed or
+// not; projects left behind by runs that were killed are deleted by the next run.
+
+const (
+	// liveTag starts the name of every resource the live tests create
+	liveTag = "planka-mcp-live-test"
+	// liveStaleAfter is how old a tagged project must be before a run deletes it as left
+	// behind, so runs in parallel keep their own projects
+	liveStaleAfter = time.Hour
+	// liveTimeout bounds each request, including the cleanup ones
+	liveTimeout = 30 * time.Second
+)
+
+// liveClient returns a client for the Planka server given by the PLANKA_* environment
+// variables, skipping the test unless PLANKA_LIVE_TEST is set
+func liveClient(t *testing.T) *planka.Client {
+	t.Helper()
+	if os.Getenv("PLANKA_LIVE_TEST") == "" {
+		t.Skip("set PLANKA_LIVE_TEST=1 and PLANKA_URL to run against a live Planka")
+	}
+	url := os.Getenv("PLANKA_URL")
+	if url == "" {
+		t.Fatal("PLANKA_LIVE_TEST is set but PLANKA_URL is not")
+	}
+	opts := []planka.Option{planka.WithTimeout(liveTimeout)}
+	if token := os.Getenv("PLANKA_TOKEN"); token != "" {
+		return planka.NewClient(url, token, opts...)
+	}
+	username, password := os.Getenv("PLANKA_USERNAME"), os.Getenv("PLANKA_PASSWORD")
+	if username == "" || password == "" {
+		t.Fatal("set PLANKA_TOKEN, or PLANKA_USERNAME and PLANKA_PASSWORD")
+	}
+	client, err := planka.NewClientWithPassword(url, username, password, opts...)
+	if err != nil {
+		t.Fatalf("logging in to %s: %v", url, err)
+	}
+	return client
+}
+
+// liveName returns the name of a resource a live test creates: the tag, the time the run
+// started and a random suffix, then name
+func liveName(name string) string {
+	return fmt.Sprintf("%s %s-%04x %s", liveTag, time.Now().UTC().Format("20060102T150405"), rand.IntN(0x10000), name)
+}
+
+// liveCreated parses when a tagged resource was created from its name
+func liveCreated(name string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(name, liveTag+" ")
+	if !ok || len(rest) < len("20060102T150405") {
+		return time.Time{}, false
+	}
+	created, err := time.Parse("20060102T150405", rest[:len("20060102T150405")])
+	return created, err == nil
+}
+
+// cleanup deletes a resource when the test ends, reporting failures other than the
+// resource being gone already, e.g. because the test deleted it
+func cleanup(t *testing.T, what string, remove func(ctx context.Context) error) {
+	t.Helper()
+	t.Cleanup(func() {
+		// The test context is canceled by the time cleanups run
+		ctx, cancel := context.WithTimeout(context.Background(), liveTimeout)
+		defer cancel()
+		var apiErr *planka.APIError
+		if err := remove(ctx); err != nil && !(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+			t.Errorf("cleaning up %s: %v", what, err)
+		}
+	})
+}
+
+// sweepLive deletes tagged projects left behind by earlier runs
+func sweepLive(t *testing.T, client *planka.Client) {
+	t.Helper()
+	ctx := t.Context()
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		t.Fatalf("GetProjects: %v", err)
+	}
+	for _, project := range projects {
+		created, ok := liveCreated(project.Name)
+		if !ok || time.Since(created) < liveStaleAfter {
+			continue
+		}
+		t.Logf("deleting %q left behind by an earlier run", project.Name)
+		boards, err := client.GetBoards(ctx, project.ID)
+		if err != nil {
+			t.Logf("GetBoards(%s): %v", project.ID, err)
+			continue
+		}
+		for _, board := range boards {
+			if err := client.DeleteBoard(ctx, board.ID); err != nil {
+				t.Logf("DeleteBoard(%s): %v", board.ID, err)
+			}
+		}
+		if err := client.DeleteProject(ctx, project.ID); err != nil {
+			t.Logf("DeleteProject(%s): %v", project.ID, err)
+		}
+	}
+}
+
+// liveBoard creates a tagged project with a board to run a live test in, deleted when the
+// test ends
+func liveBoard(t *testing.T, client *planka.Client) (*planka.Project, *planka.Board) {
+	t.Helper()
+	sweepLive(t, client)
+	ctx := t.Context()
+
+	project, err := client.CreateProject(ctx, planka.CreateProjectRequest{
+		Name:        liveName("project"),
+		Description: "Created by the planka-mcp live tests; safe to delete.",
+	})
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	cleanup(t, "project "+project.ID, func(ctx context.Context) error { return client.DeleteProject(ctx, project.ID) })
+
+	board, err := client.CreateBoard(ctx, planka.CreateBoardRequest{Name: liveName("board"), ProjectID: project.ID, Position: 65535})
+	if err != nil {
+		t.Fatalf("CreateBoard: %v", err)
+	}
+	// Cleanups run last in first out, so the board goes before its project
+	cleanup(t, "board "+board.ID, func(ctx context.Context) error { return client.DeleteBoard(ctx, board.ID) })
+	return project, board
+}
+
+func TestLiveClient(t *testing.T) {
+	client := liveClient(t)
+	ctx := t.Context()
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+	if me.ID == "" || me.Username == "" {
+		t.Errorf("GetMe = %+v, want the user", me)
+	}
+
+	project, board := liveBoard(t, client)
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		t.Fatalf("GetProjects: %v", err)
+	}
+	found := false
+	for _, p := range projects {
+		found = found || p.ID == project.ID
+	}
+	if !found {
+		t.Errorf("GetProjects does not list the created project %s", project.ID)
+	}
+	if got, err := client.GetBoard(ctx, board.ID); err != nil || got.ProjectID != project.ID {
+		t.Errorf("GetBoard(%s) = %+v, %v, want the board of project %s", board.ID, got, err, project.ID)
+	}
+
+	list, err := client.CreateList(ctx, planka.CreateListRequest{Name: liveName("list"), BoardID: board.ID})
+	if err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	cleanup(t, "list "+list.ID, func(ctx context.Context) error { return client.DeleteList(ctx, list.ID) })
+	card, err := client.CreateCard(ctx, planka.CreateCardRequest{Name: liveName("card"), ListID: list.ID})
+	if err != nil {
+		t.Fatalf("CreateCard: %v", err)
+	}
+	cleanup(t, "card "+card.ID, func(ctx context.Context) error { return client.DeleteCard(ctx, card.ID) })
+
+	cards, err := client.GetCards(ctx, list.ID)
+	if err != nil || len(cards) != 1 || cards[0].ID != card.ID {
+		t.Errorf("GetCards(%s) = %+v, %v, want the created card", list.ID, cards, err)
+	}
+	if _, err := client.GetStopwatch(ctx, card.ID); err != nil {
+		t.Errorf("GetStopwatch: %v", err)
+	}
+	if err := client.DeleteList(ctx, list.ID); err != nil {
+		t.Errorf("DeleteList: %v", err)
+	}
+}
+
+func TestLiveTools(t *testing.T) {
+	client := liveClient(t)
+	_, board := liveBoard(t, client)
+	session := connect(t, NewServer(client))
+
+	var list, done, card, task, comment struct {
+		ID string `json:"id"`
+	}
+	callTool(t, session, "create_list", map[string]interface{}{"name": liveName("todo"), "boardId": board.ID}, &list)
+	cleanup(t, "list "+list.ID, func(ctx context.Context) error { return client.DeleteList(ctx, list.ID) })
+	callTool(t, session, "create_list", map[string]interface{}{"name": liveName("done"), "boardId": board.ID, "position": 131070}, &done)
+	cleanup(t, "list "+done.ID, func(ctx context.Context) error { return client.DeleteList(ctx, done.ID) })
+	callTool(t, session, "create_card", map[string]interface{}{"name": liveName("card"), "listId": list.ID, "dueDate": "2030-01-31T12:00:00Z"}, &card)
+	cleanup(t, "card "+card.ID, func(ctx context.Context) error { return client.DeleteCard(ctx, card.ID) })
+
+	var updated struct {
+		Name    string     `json:"name"`
+		DueDate *time.Time `json:"dueDate"`
+	}
+	name := liveName("renamed card")
+	callTool(t, session, "update_card", map[string]interface{}{"cardId": card.ID, "name": name}, &updated)
+	if updated.Name != name || updated.DueDate == nil || !updated.DueDate.Equal(time.Date(2030, 1, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("update_card = %+v, want the new name and the due date kept", updated)
+	}
+
+	callTool(t, session, "create_task", map[string]interface{}{"name": "Outline", "cardId": card.ID}, &task)
+	var completed struct {
+		IsCompleted bool `json:"isCompleted"`
+	}
+	callTool(t, session, "update_task", map[string]interface{}{"taskId": task.ID, "isCompleted": true}, &completed)
+	if !completed.IsCompleted {
+		t.Errorf("update_task did not complete the task")
+	}
+	callTool(t, session, "create_comment", map[string]interface{}{"text": "Looks good", "cardId": card.ID}, &comment)
+	var comments []struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	}
+	callTool(t, session, "get_comments", map[string]interface{}{"cardId": card.ID}, &comments)
+	if len(comments) != 1 || comments[0].ID != comment.ID || comments[0].Text != "Looks good" {
+		t.Errorf("get_comments = %+v, want the created comment", comments)
+	}
+
+	var started, stopped struct {
+		StartedAt *time.Time `json:"startedAt"`
+	}
+	callTool(t, session, "start_stopwatch", map[string]interface{}{"cardId": card.ID}, &started)
+	if started.StartedAt == nil {
+		t.Errorf("start_stopwatch did not start the stopwatch")
+	}
+	callTool(t, session, "stop_stopwatch", map[string]interface{}{"cardId": card.ID}, &stopped)
+	if stopped.StartedAt != nil {
+		t.Errorf("stop_stopwatch did not stop the stopwatch")
+	}
+
+	var moved struct {
+		ListID string `json:"listId"`
+	}
+	callTool(t, session, "move_card", map[string]interface{}{"cardId": card.ID, "listId": done.ID}, &moved)
+	if moved.ListID != done.ID {
+		t.Errorf("move_card moved the card to %s, want %s", moved.ListID, done.ID)
+	}
+
+	callTool(t, session, "delete_comment", map[string]interface{}{"commentId": comment.ID}, nil)
+	callTool(t, session, "delete_task", map[string]interface{}{"taskId": task.ID}, nil)
+	callTool(t, session, "delete_card", map[string]interface{}{"cardId": card.ID}, nil)
+	if _, err := client.GetCard(t.Context(), card.ID); err == nil {
+		t.Errorf("card %s still exists after delete_card", card.ID)
+	}
+}
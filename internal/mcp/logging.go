@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redactedKeys are the (lowercased) JSON keys whose values are never written to logs
+var redactedKeys = map[string]bool{
+	"token":         true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"password":      true,
+	"secret":        true,
+	"clientsecret":  true,
+	"apikey":        true,
+	"authorization": true,
+}
+
+// LoggingOptions configures request logging
+type LoggingOptions struct {
+	// Logger receives one record per request
+	Logger *slog.Logger
+	// Bodies adds redacted request params and results to each record when Logger is at debug level
+	Bodies bool
+}
+
+// EnableRequestLogging logs every MCP request handled by the server: its method,
+// tool name, session, duration and outcome, so operators can audit what agents did.
+// Each request gets an ID that is logged and appended to errors returned to the client
+// as "(ref: ID)", so a failure reported by a user can be found in the logs.
+func (s *Server) EnableRequestLogging(opts LoggingOptions) {
+	s.server.AddReceivingMiddleware(requestLogger(opts))
+}
+
+// requestLogger returns middleware that logs each request once it completes
+func requestLogger(opts LoggingOptions) mcpsdk.Middleware {
+	logger := opts.Logger
+	return func(next mcpsdk.MethodHandler) mcpsdk.MethodHandler {
+		return func(ctx context.Context, method string, req mcpsdk.Request) (mcpsdk.Result, error) {
+			start := time.Now()
+			id := newRequestID()
+			result, err := next(ctx, method, req)
+
+			attrs := []slog.Attr{
+				slog.String("request_id", id),
+				slog.String("method", method),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if session := req.GetSession(); session != nil && session.ID() != "" {
+				attrs = append(attrs, slog.String("session", session.ID()))
+			}
+			if params, ok := req.GetParams().(*mcpsdk.CallToolParamsRaw); ok {
+				attrs = append(attrs, slog.String("tool", params.Name))
+			}
+
+			// Tool failures are reported inside a successful result
+			level := slog.LevelInfo
+			outcome := "ok"
+			if err != nil {
+				level = slog.LevelWarn
+				outcome = "error"
+				attrs = append(attrs, slog.String("error", err.Error()))
+			} else if toolResult, ok := result.(*mcpsdk.CallToolResult); ok && toolResult.IsError {
+				level = slog.LevelWarn
+				outcome = "tool_error"
+			}
+			attrs = append(attrs, slog.String("outcome", outcome))
+
+			// Notifications are chatty and uninteresting unless debugging
+			if strings.HasPrefix(method, "notifications/") && err == nil {
+				level = slog.LevelDebug
+			}
+
+			if opts.Bodies && logger.Enabled(ctx, slog.LevelDebug) {
+				attrs = append(attrs, slog.String("params", redactedJSON(req.GetParams())))
+				if err == nil {
+					attrs = append(attrs, slog.String("result", redactedJSON(result)))
+				}
+			}
+
+			logger.LogAttrs(ctx, level, "mcp request", attrs...)
+			return withRequestRef(result, err, id)
+		}
+	}
+}
+
+// newRequestID returns a short random ID identifying a request
+func newRequestID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// withRequestRef appends a reference to request id to the error a client receives,
+// whether it is a JSON-RPC error or a failed tool result
+func withRequestRef(result mcpsdk.Result, err error, id string) (mcpsdk.Result, error) {
+	ref := fmt.Sprintf(" (ref: %s)", id)
+
+	if err != nil {
+		var rpcErr *jsonrpc.Error
+		if errors.As(err, &rpcErr) {
+			tagged := *rpcErr
+			tagged.Message += ref
+			return result, &tagged
+		}
+		return result, fmt.Errorf("%w%s", err, ref)
+	}
+
+	if toolResult, ok := result.(*mcpsdk.CallToolResult); ok && toolResult.IsError && len(toolResult.Content) > 0 {
+		if text, ok := toolResult.Content[0].(*mcpsdk.TextContent); ok {
+			text.Text += ref
+		}
+	}
+	return result, nil
+}
+
+// redactedJSON returns the JSON encoding of v with the values of sensitive keys replaced
+func redactedJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return ""
+	}
+	data, err = json.Marshal(redact(decoded))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// redact walks a decoded JSON value, masking the values of sensitive keys
+func redact(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if redactedKeys[strings.ToLower(key)] {
+				value[key] = "[REDACTED]"
+				continue
+			}
+			value[key] = redact(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redact(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
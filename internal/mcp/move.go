@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// movedCard is a card moved by move_all_cards, or that would be in a dry run
+type movedCard struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Position float64 `json:"position"`
+}
+
+// moveAllResult is the result of move_all_cards
+type moveAllResult struct {
+	SourceListID string      `json:"sourceListId"`
+	TargetListID string      `json:"targetListId"`
+	DryRun       bool        `json:"dryRun"`
+	Moved        int         `json:"moved"`
+	Cards        []movedCard `json:"cards"`
+	// Skipped counts the cards of the source list left there by the filters
+	Skipped int `json:"skipped"`
+	// Errors describes cards that could not be moved
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (s *Server) handleMoveAllCards(ctx context.Context, args moveAllCardsArgs) (interface{}, error) {
+	if args.SourceListID == args.TargetListID {
+		return nil, invalidParams("sourceListId and targetListId must be different lists")
+	}
+	if args.Position != "" && args.Position != "top" && args.Position != "bottom" {
+		return nil, invalidParams("position must be top or bottom")
+	}
+	loc := s.location()
+	var dueFrom, dueTo time.Time
+	var err error
+	if args.DueFrom != "" {
+		if dueFrom, err = parseReportDate(args.DueFrom, loc, false); err != nil {
+			return nil, invalidParams("dueFrom: %v", err)
+		}
+	}
+	if args.DueTo != "" {
+		if dueTo, err = parseReportDate(args.DueTo, loc, true); err != nil {
+			return nil, invalidParams("dueTo: %v", err)
+		}
+	}
+
+	client := s.clientFor(ctx)
+	source, err := client.GetList(ctx, args.SourceListID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.GetList(ctx, args.TargetListID); err != nil {
+		return nil, err
+	}
+	cards, err := client.GetCards(ctx, args.SourceListID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cards with the label, if one is given
+	var labeled map[string]bool
+	if args.Label != "" {
+		if labeled, err = cardsWithLabel(ctx, client, source.BoardID, args.Label); err != nil {
+			return nil, err
+		}
+	}
+
+	var moving []planka.Card
+	for _, card := range cards {
+		if labeled != nil && !labeled[card.ID] {
+			continue
+		}
+		if !dueFrom.IsZero() || !dueTo.IsZero() {
+			if card.DueDate == nil || (!dueFrom.IsZero() && card.DueDate.Before(dueFrom)) || (!dueTo.IsZero() && card.DueDate.After(dueTo)) {
+				continue
+			}
+		}
+		moving = append(moving, card)
+	}
+	sort.SliceStable(moving, func(i, j int) bool { return moving[i].Position < moving[j].Position })
+
+	result := moveAllResult{
+		SourceListID: args.SourceListID,
+		TargetListID: args.TargetListID,
+		DryRun:       args.DryRun,
+		Cards:        []movedCard{},
+		Skipped:      len(cards) - len(moving),
+	}
+	if len(moving) == 0 {
+		return result, nil
+	}
+	positions, err := appendPositions(ctx, client, args.TargetListID, len(moving), args.Position == "top")
+	if err != nil {
+		return nil, err
+	}
+
+	var moved []planka.Card
+	for i, card := range moving {
+		if !args.DryRun {
+			if _, err := client.MoveCard(ctx, card.ID, args.TargetListID, positions[i]); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID, err))
+				continue
+			}
+			moved = append(moved, card)
+		}
+		result.Cards = append(result.Cards, movedCard{ID: card.ID, Name: card.Name, Position: positions[i]})
+		result.Moved++
+	}
+	if len(moved) > 0 {
+		s.recordUndo(ctx, "move_all_cards", fmt.Sprintf("move of %d cards to list %s", len(moved), args.TargetListID), func(ctx context.Context) error {
+			for _, card := range moved {
+				if _, err := client.MoveCard(ctx, card.ID, card.ListID, card.Position); err != nil {
+					return fmt.Errorf("card %s: %w", card.ID, err)
+				}
+			}
+			return nil
+		})
+	}
+	return result, nil
+}
+
+// appendPositions returns n ascending positions for cards added to the top or bottom of
+// listID, keeping the order of the cards already there
+func appendPositions(ctx context.Context, client PlankaClient, listID string, n int, top bool) ([]float64, error) {
+	cards, err := client.GetCards(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cards of list %s to compute the positions: %w", listID, err)
+	}
+	first, last := 0.0, 0.0
+	for i, card := range cards {
+		if i == 0 || card.Position < first {
+			first = card.Position
+		}
+		if i == 0 || card.Position > last {
+			last = card.Position
+		}
+	}
+
+	positions := make([]float64, n)
+	for i := range positions {
+		if top && len(cards) > 0 {
+			// Spread the cards evenly between 0 and the current first card
+			positions[i] = first * float64(i+1) / float64(n+1)
+		} else {
+			positions[i] = last + float64(i+1)*planka.PositionGap
+		}
+	}
+	return positions, nil
+}
+
+// cardsWithLabel returns the cards of a board that have the label named, or with the ID, label
+func cardsWithLabel(ctx context.Context, client PlankaClient, boardID, label string) (map[string]bool, error) {
+	labels, err := client.GetLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	labelIDs := map[string]bool{}
+	for _, l := range labels {
+		if l.ID == label || strings.EqualFold(l.Name, strings.TrimSpace(label)) {
+			labelIDs[l.ID] = true
+		}
+	}
+	if len(labelIDs) == 0 {
+		return nil, invalidParams("board %s has no label %q", boardID, label)
+	}
+	cardLabels, err := client.GetCardLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	cards := map[string]bool{}
+	for _, cardLabel := range cardLabels {
+		if labelIDs[cardLabel.LabelID] {
+			cards[cardLabel.CardID] = true
+		}
+	}
+	return cards, nil
+}
@@ -0,0 +1,416 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// nameIndexTTL is how long the entity names fetched to resolve a name are reused
+const nameIndexTTL = time.Minute
+
+// maxNameCandidates bounds how many candidates a resolution error lists
+const maxNameCandidates = 10
+
+// nameArgument pairs an ID argument with the name argument that may replace it
+type nameArgument struct {
+	id   string
+	name string
+	kind string
+}
+
+// nameArguments are resolved in this order, so a resolved project scopes the board
+// search, a resolved board the list search and so on
+var nameArguments = []nameArgument{
+	{id: "projectId", name: "projectName", kind: "project"},
+	{id: "boardId", name: "boardName", kind: "board"},
+	{id: "listId", name: "listName", kind: "list"},
+	{id: "cardId", name: "cardName", kind: "card"},
+}
+
+// namedEntity is an entity a name may resolve to
+type namedEntity struct {
+	id   string
+	name string
+	// parent describes where the entity lives, e.g. `board "Roadmap"`, to tell apart
+	// entities of the same name
+	parent string
+}
+
+// withNameArguments returns a copy of tool whose inputSchema accepts a name in place of each
+// project, board, list and card ID. IDs that were required are required unless their name is given.
+func withNameArguments(tool *mcpsdk.Tool) *mcpsdk.Tool {
+	schema := maps.Clone(tool.InputSchema.(map[string]interface{}))
+	properties := maps.Clone(schema["properties"].(map[string]interface{}))
+	required, _ := schema["required"].([]string)
+
+	changed := false
+	for _, arg := range nameArguments {
+		if _, ok := properties[arg.id]; !ok {
+			continue
+		}
+		properties[arg.name] = map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("The %s name, resolved to its ID when %s is not given", arg.kind, arg.id),
+		}
+		required = slices.DeleteFunc(slices.Clone(required), func(name string) bool { return name == arg.id })
+		changed = true
+	}
+	if !changed {
+		return tool
+	}
+
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	} else {
+		delete(schema, "required")
+	}
+	clone := *tool
+	clone.InputSchema = schema
+	return &clone
+}
+
+// resolveNames wraps a tool handler so name arguments are replaced by the IDs they resolve to
+// before the handler validates its arguments. required lists the ID arguments the tool needs.
+func (s *Server) resolveNames(required []string, next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		var args map[string]interface{}
+		if len(req.Params.Arguments) == 0 || json.Unmarshal(req.Params.Arguments, &args) != nil {
+			// Malformed arguments are reported by the tool handler itself
+			return next(ctx, req)
+		}
+
+		changed := false
+		for _, arg := range nameArguments {
+			name, hasName := args[arg.name].(string)
+			if id, ok := args[arg.id].(string); ok && id != "" {
+				delete(args, arg.name)
+				continue
+			}
+			if !hasName || strings.TrimSpace(name) == "" {
+				if slices.Contains(required, arg.id) {
+					return nil, invalidParams("missing required argument %s (or %s)", arg.id, arg.name)
+				}
+				continue
+			}
+
+			id, err := s.names.resolve(ctx, s.clientFor(ctx), arg, name, args)
+			if err != nil {
+				return nil, err
+			}
+			args[arg.id] = id
+			delete(args, arg.name)
+			changed = true
+		}
+		if !changed {
+			return next(ctx, req)
+		}
+
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		params := *req.Params
+		params.Arguments = data
+		resolved := *req
+		resolved.Params = &params
+		return next(ctx, &resolved)
+	}
+}
+
+// nameIndexes holds a name index per Planka client, since callers in multi-tenant mode or
+// on different instances see different entities
+type nameIndexes struct {
+	mu      sync.Mutex
+	indexes map[PlankaClient]*nameIndex
+}
+
+// nameIndex caches the entities fetched to resolve names with one Planka client
+type nameIndex struct {
+	mu        sync.Mutex
+	createdAt time.Time
+	projects  []namedEntity
+	// boards, lists and cards are keyed by the project, board and list they belong to
+	boards map[string][]namedEntity
+	lists  map[string][]namedEntity
+	cards  map[string][]namedEntity
+}
+
+// index returns the name index of client, replacing it once it is older than nameIndexTTL
+func (n *nameIndexes) index(client PlankaClient, fresh bool) *nameIndex {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.indexes == nil {
+		n.indexes = map[PlankaClient]*nameIndex{}
+	}
+	// Drop the indexes of clients that went away along with expired ones
+	for c, index := range n.indexes {
+		if time.Since(index.createdAt) > nameIndexTTL {
+			delete(n.indexes, c)
+		}
+	}
+
+	index, ok := n.indexes[client]
+	if !ok || fresh {
+		index = &nameIndex{
+			createdAt: time.Now(),
+			boards:    map[string][]namedEntity{},
+			lists:     map[string][]namedEntity{},
+			cards:     map[string][]namedEntity{},
+		}
+		n.indexes[client] = index
+	}
+	return index
+}
+
+// resolve returns the ID of the entity of arg's kind called name, scoped by the IDs already
+// in args. An unknown name is looked up again on fresh data, since the entity may be new.
+func (n *nameIndexes) resolve(ctx context.Context, client PlankaClient, arg nameArgument, name string, args map[string]interface{}) (string, error) {
+	scope := func(key string) string {
+		id, _ := args[key].(string)
+		return id
+	}
+
+	var matches, candidates []namedEntity
+	for _, fresh := range []bool{false, true} {
+		index := n.index(client, fresh)
+		var err error
+		candidates, err = index.candidates(ctx, client, arg.kind, scope("projectId"), scope("boardId"), scope("listId"))
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s %q: %w", arg.name, name, err)
+		}
+		matches = matchName(candidates, name)
+		if len(matches) > 0 {
+			break
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0].id, nil
+	case 0:
+		return "", invalidParams("%s %q matches no %s%s", arg.name, name, arg.kind, describeCandidates(" among: ", candidates))
+	default:
+		return "", invalidParams("%s %q matches %d %ss, pass %s instead: %s", arg.name, name, len(matches), arg.kind, arg.id, describeCandidates("", matches))
+	}
+}
+
+// matchName returns the entities called name, ignoring case and surrounding space
+func matchName(entities []namedEntity, name string) []namedEntity {
+	var matches []namedEntity
+	for _, entity := range entities {
+		if strings.EqualFold(strings.TrimSpace(entity.name), strings.TrimSpace(name)) {
+			matches = append(matches, entity)
+		}
+	}
+	return matches
+}
+
+// describeCandidates lists entities for an error message, e.g. `"Todo" (id 12, board "Roadmap")`
+func describeCandidates(prefix string, entities []namedEntity) string {
+	if len(entities) == 0 {
+		return ""
+	}
+	described := make([]string, 0, maxNameCandidates)
+	for i, entity := range entities {
+		if i == maxNameCandidates {
+			described = append(described, fmt.Sprintf("and %d more", len(entities)-i))
+			break
+		}
+		if entity.parent != "" {
+			described = append(described, fmt.Sprintf("%q (id %s, %s)", entity.name, entity.id, entity.parent))
+		} else {
+			described = append(described, fmt.Sprintf("%q (id %s)", entity.name, entity.id))
+		}
+	}
+	return prefix + strings.Join(described, ", ")
+}
+
+// candidates returns the entities of kind within the given scope; empty scope IDs widen
+// the search to everything the client can see
+func (x *nameIndex) candidates(ctx context.Context, client PlankaClient, kind, projectID, boardID, listID string) ([]namedEntity, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	switch kind {
+	case "project":
+		return x.projectEntities(ctx, client)
+	case "board":
+		return x.boardEntities(ctx, client, projectID)
+	case "list":
+		if boardID != "" {
+			return x.listEntities(ctx, client, boardID, x.boardLabel(boardID))
+		}
+		return x.across(ctx, client, projectID, x.listEntities)
+	default:
+		if listID != "" {
+			return x.cardEntities(ctx, client, "", listID)
+		}
+		if boardID != "" {
+			return x.cardEntities(ctx, client, boardID, "")
+		}
+		return x.across(ctx, client, projectID, func(ctx context.Context, client PlankaClient, boardID, _ string) ([]namedEntity, error) {
+			return x.cardEntities(ctx, client, boardID, "")
+		})
+	}
+}
+
+// across collects the entities fetch returns for every board of projectID, or of every project
+func (x *nameIndex) across(ctx context.Context, client PlankaClient, projectID string, fetch func(ctx context.Context, client PlankaClient, boardID, parent string) ([]namedEntity, error)) ([]namedEntity, error) {
+	boards, err := x.boardEntities(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var all []namedEntity
+	for _, board := range boards {
+		entities, err := fetch(ctx, client, board.id, fmt.Sprintf("board %q", board.name))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, entities...)
+	}
+	return all, nil
+}
+
+// projectEntities returns every project
+func (x *nameIndex) projectEntities(ctx context.Context, client PlankaClient) ([]namedEntity, error) {
+	if x.projects != nil {
+		return x.projects, nil
+	}
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	x.projects = []namedEntity{}
+	for _, project := range projects {
+		x.projects = append(x.projects, namedEntity{id: project.ID, name: project.Name})
+	}
+	return x.projects, nil
+}
+
+// boardEntities returns the boards of projectID, or of every project
+func (x *nameIndex) boardEntities(ctx context.Context, client PlankaClient, projectID string) ([]namedEntity, error) {
+	if projectID != "" {
+		parent := "project " + projectID
+		if projects, err := x.projectEntities(ctx, client); err == nil {
+			for _, project := range projects {
+				if project.id == projectID {
+					parent = fmt.Sprintf("project %q", project.name)
+				}
+			}
+		}
+		return x.projectBoards(ctx, client, projectID, parent)
+	}
+	projects, err := x.projectEntities(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var all []namedEntity
+	for _, project := range projects {
+		boards, err := x.projectBoards(ctx, client, project.id, fmt.Sprintf("project %q", project.name))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, boards...)
+	}
+	return all, nil
+}
+
+// projectBoards returns the boards of a single project
+func (x *nameIndex) projectBoards(ctx context.Context, client PlankaClient, projectID, parent string) ([]namedEntity, error) {
+	if boards, ok := x.boards[projectID]; ok {
+		return boards, nil
+	}
+	boards, err := client.GetBoards(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	entities := []namedEntity{}
+	for _, board := range boards {
+		entities = append(entities, namedEntity{id: board.ID, name: board.Name, parent: parent})
+	}
+	x.boards[projectID] = entities
+	return entities, nil
+}
+
+// listEntities returns the lists of a board
+func (x *nameIndex) listEntities(ctx context.Context, client PlankaClient, boardID, parent string) ([]namedEntity, error) {
+	if lists, ok := x.lists[boardID]; ok {
+		return lists, nil
+	}
+	lists, err := client.GetLists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	entities := []namedEntity{}
+	for _, list := range lists {
+		entities = append(entities, namedEntity{id: list.ID, name: list.Name, parent: parent})
+	}
+	x.lists[boardID] = entities
+	return entities, nil
+}
+
+// cardEntities returns the cards of a board, or of a single list when listID is set
+func (x *nameIndex) cardEntities(ctx context.Context, client PlankaClient, boardID, listID string) ([]namedEntity, error) {
+	key := "board:" + boardID
+	if listID != "" {
+		key = "list:" + listID
+	}
+	if cards, ok := x.cards[key]; ok {
+		return cards, nil
+	}
+
+	var entities []namedEntity
+	if listID != "" {
+		cards, err := client.GetCards(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+		entities = []namedEntity{}
+		for _, card := range cards {
+			entities = append(entities, namedEntity{id: card.ID, name: card.Name, parent: "list " + listID})
+		}
+	} else {
+		lists, err := x.listEntities(ctx, client, boardID, x.boardLabel(boardID))
+		if err != nil {
+			return nil, err
+		}
+		cards, err := client.GetBoardCards(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		entities = []namedEntity{}
+		for _, card := range cards {
+			parent := "list " + card.ListID
+			for _, list := range lists {
+				if list.id == card.ListID {
+					parent = fmt.Sprintf("list %q", list.name)
+				}
+			}
+			entities = append(entities, namedEntity{id: card.ID, name: card.Name, parent: parent})
+		}
+	}
+	x.cards[key] = entities
+	return entities, nil
+}
+
+// boardLabel describes boardID by its name if the index has seen it
+func (x *nameIndex) boardLabel(boardID string) string {
+	for _, boards := range x.boards {
+		for _, board := range boards {
+			if board.id == boardID {
+				return fmt.Sprintf("board %q", board.name)
+			}
+		}
+	}
+	return "board " + boardID
+}
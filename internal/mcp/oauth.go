@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/oauthex"
+)
+
+const (
+	// introspectionCacheTTL bounds how long an introspection result is reused
+	introspectionCacheTTL = time.Minute
+	// resourceMetadataRoot is the RFC 9728 well-known location of protected resource metadata
+	resourceMetadataRoot = "/.well-known/oauth-protected-resource"
+)
+
+// OAuthOptions configures the HTTP server as an OAuth 2.1 resource server
+// following the MCP authorization spec
+type OAuthOptions struct {
+	// Issuer is the issuer URL of the authorization server that issues access tokens
+	Issuer string
+	// ResourceURL is the canonical URL of this MCP server, e.g. https://mcp.example.com/mcp.
+	// Tokens must be issued for this audience.
+	ResourceURL string
+	// IntrospectionURL is the token introspection endpoint (RFC 7662);
+	// when empty it is discovered from the authorization server metadata
+	IntrospectionURL string
+	// ClientID and ClientSecret authenticate this server to the introspection endpoint
+	ClientID     string
+	ClientSecret string
+	// Scopes are the scopes every access token must carry
+	Scopes []string
+	// AllowMissingAudience accepts tokens without an audience, for authorization servers
+	// that do not set one. Tokens with an audience must still include ResourceURL.
+	AllowMissingAudience bool
+}
+
+// resourceMetadataPath returns the RFC 9728 well-known path of the protected resource metadata
+func (o *OAuthOptions) resourceMetadataPath() (string, error) {
+	resource, err := url.Parse(o.ResourceURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid OAuth resource URL: %w", err)
+	}
+	return resourceMetadataRoot + strings.TrimSuffix(resource.Path, "/"), nil
+}
+
+// resourceMetadataURL returns the absolute URL of the protected resource metadata
+func (o *OAuthOptions) resourceMetadataURL() (string, error) {
+	resource, err := url.Parse(o.ResourceURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid OAuth resource URL: %w", err)
+	}
+	path, err := o.resourceMetadataPath()
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: resource.Scheme, Host: resource.Host, Path: path}).String(), nil
+}
+
+// resourceMetadata returns the protected resource metadata served to clients for discovery
+func (o *OAuthOptions) resourceMetadata() *oauthex.ProtectedResourceMetadata {
+	return &oauthex.ProtectedResourceMetadata{
+		Resource:               o.ResourceURL,
+		AuthorizationServers:   []string{o.Issuer},
+		ScopesSupported:        o.Scopes,
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           "Planka MCP Server",
+	}
+}
+
+// introspectionResponse is the subset of an RFC 7662 introspection response used here
+type introspectionResponse struct {
+	Active   bool            `json:"active"`
+	Scope    string          `json:"scope"`
+	Exp      int64           `json:"exp"`
+	Sub      string          `json:"sub"`
+	Audience json.RawMessage `json:"aud"`
+}
+
+// audiences returns the token audiences, which may be encoded as a string or an array
+func (r *introspectionResponse) audiences() []string {
+	if len(r.Audience) == 0 {
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(r.Audience, &single); err == nil {
+		return []string{single}
+	}
+	var multiple []string
+	json.Unmarshal(r.Audience, &multiple)
+	return multiple
+}
+
+// cachedToken is a verified token kept until expiresAt
+type cachedToken struct {
+	info      *auth.TokenInfo
+	expiresAt time.Time
+}
+
+// tokenIntrospector validates access tokens against the authorization server's introspection endpoint
+type tokenIntrospector struct {
+	opts       OAuthOptions
+	endpoint   string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedToken
+}
+
+// newTokenIntrospector creates a token verifier for opts, discovering the introspection
+// endpoint from the authorization server metadata if it is not configured
+func newTokenIntrospector(ctx context.Context, opts OAuthOptions) (*tokenIntrospector, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	endpoint := opts.IntrospectionURL
+	if endpoint == "" {
+		meta, err := auth.GetAuthServerMetadata(ctx, opts.Issuer, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch authorization server metadata: %w", err)
+		}
+		if meta == nil || meta.IntrospectionEndpoint == "" {
+			return nil, fmt.Errorf("authorization server %s does not advertise an introspection endpoint", opts.Issuer)
+		}
+		endpoint = meta.IntrospectionEndpoint
+	}
+
+	return &tokenIntrospector{
+		opts:       opts,
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		cache:      map[string]cachedToken{},
+	}, nil
+}
+
+// verify is an auth.TokenVerifier that accepts active tokens issued for this resource
+func (t *tokenIntrospector) verify(ctx context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	t.mu.Lock()
+	cached, ok := t.cache[key]
+	t.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.info, nil
+	}
+
+	info, err := t.introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(introspectionCacheTTL)
+	if info.Expiration.Before(expiresAt) {
+		expiresAt = info.Expiration
+	}
+	t.mu.Lock()
+	for k, v := range t.cache {
+		if time.Now().After(v.expiresAt) {
+			delete(t.cache, k)
+		}
+	}
+	t.cache[key] = cachedToken{info: info, expiresAt: expiresAt}
+	t.mu.Unlock()
+
+	return info, nil
+}
+
+// introspect asks the authorization server whether token is active and for whom it was issued
+func (t *tokenIntrospector) introspect(ctx context.Context, token string) (*auth.TokenInfo, error) {
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if t.opts.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(t.opts.ClientID), url.QueryEscape(t.opts.ClientSecret))
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token introspection failed with status %d", resp.StatusCode)
+	}
+
+	var result introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	if !result.Active {
+		return nil, fmt.Errorf("%w: token is not active", auth.ErrInvalidToken)
+	}
+
+	// Tokens issued for other resources must not be accepted (no token passthrough), and
+	// neither must tokens that do not say what they were issued for
+	audiences := result.audiences()
+	if len(audiences) == 0 && !t.opts.AllowMissingAudience {
+		return nil, fmt.Errorf("%w: token has no audience", auth.ErrInvalidToken)
+	}
+	if len(audiences) > 0 && !containsString(audiences, t.opts.ResourceURL) {
+		return nil, fmt.Errorf("%w: token audience does not include %s", auth.ErrInvalidToken, t.opts.ResourceURL)
+	}
+
+	// Introspection responses may omit exp; such tokens are re-checked once the cache entry lapses
+	expiration := time.Now().Add(introspectionCacheTTL)
+	if result.Exp != 0 {
+		expiration = time.Unix(result.Exp, 0)
+	}
+
+	return &auth.TokenInfo{
+		Scopes:     strings.Fields(result.Scope),
+		Expiration: expiration,
+		UserID:     result.Sub,
+	}, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+func TestIntrospectChecksAudience(t *testing.T) {
+	const resource = "https://mcp.example.com/mcp"
+	responses := map[string]string{
+		"ours":        `{"active": true, "aud": "https://mcp.example.com/mcp"}`,
+		"listed":      `{"active": true, "aud": ["https://other.example.com", "https://mcp.example.com/mcp"]}`,
+		"other":       `{"active": true, "aud": "https://other.example.com"}`,
+		"no-audience": `{"active": true}`,
+	}
+	introspection := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[r.FormValue("token")]))
+	}))
+	defer introspection.Close()
+
+	tests := []struct {
+		token        string
+		allowMissing bool
+		wantValid    bool
+	}{
+		{"ours", false, true},
+		{"listed", false, true},
+		{"other", false, false},
+		{"no-audience", false, false},
+		{"no-audience", true, true},
+		{"other", true, false},
+	}
+	for _, tt := range tests {
+		introspector, err := newTokenIntrospector(context.Background(), OAuthOptions{
+			ResourceURL:          resource,
+			IntrospectionURL:     introspection.URL,
+			AllowMissingAudience: tt.allowMissing,
+		})
+		if err != nil {
+			t.Fatalf("newTokenIntrospector: %v", err)
+		}
+		_, err = introspector.introspect(context.Background(), tt.token)
+		if valid := err == nil; valid != tt.wantValid {
+			t.Errorf("introspect(%s, allowMissing %v) error = %v, want valid %v", tt.token, tt.allowMissing, err, tt.wantValid)
+		}
+		if err != nil && !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("introspect(%s) error = %v, want an invalid token error", tt.token, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// webhookBacklog is how many events may wait for delivery before new ones are dropped
+	webhookBacklog = 1000
+	// webhookAttempts is how often delivery of an event to a URL is tried
+	webhookAttempts = 3
+	// webhookSignatureHeader carries the HMAC-SHA256 of the body when a secret is configured
+	webhookSignatureHeader = "X-Planka-MCP-Signature"
+)
+
+// WebhookOptions configures the webhooks notified of changes agents make through the server
+type WebhookOptions struct {
+	// URLs receive a JSON POST for every successful mutating tool call
+	URLs []string
+	// Secret, if set, signs each body as "sha256=<hex HMAC>" in the X-Planka-MCP-Signature header
+	Secret string
+	// Timeout bounds each delivery attempt; zero uses 10 seconds
+	Timeout time.Duration
+}
+
+// toolCallEvent is the body POSTed to webhooks for a mutating tool call
+type toolCallEvent struct {
+	Type string `json:"type"`
+	// Text summarizes the call, so chat incoming webhooks such as Slack's can show it as is
+	Text      string      `json:"text"`
+	Tool      string      `json:"tool"`
+	Action    string      `json:"action"`
+	Entity    string      `json:"entity"`
+	Arguments interface{} `json:"arguments"`
+	Result    interface{} `json:"result,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Session   string      `json:"session,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// webhookSender delivers events to the configured URLs in the background, in order
+type webhookSender struct {
+	opts   WebhookOptions
+	client *http.Client
+	queue  chan []byte
+}
+
+// SetWebhooks POSTs a JSON event to every URL of opts whenever an agent creates, updates
+// or deletes something through a tool, so teams can mirror agent activity to chat or an
+// audit system. Deliveries happen in the background and are retried on failure.
+func (s *Server) SetWebhooks(opts WebhookOptions) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	sender := &webhookSender{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		queue:  make(chan []byte, webhookBacklog),
+	}
+	go sender.run()
+	s.webhooks = sender
+	s.registerTools()
+}
+
+// notifyWebhooks wraps the handler of a mutating tool so every successful call is sent to
+// the webhooks. Failed calls and dry runs changed nothing and are not sent.
+func (s *Server) notifyWebhooks(name string, next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || planka.DryRunFromContext(ctx) != nil {
+			return result, err
+		}
+
+		var args, structured interface{}
+		json.Unmarshal(req.Params.Arguments, &args)
+		// The result is decoded generically so it can be summarized like the arguments
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			json.Unmarshal(data, &structured)
+		}
+		action, entity, _ := strings.Cut(name, "_")
+		event := toolCallEvent{
+			Type:      "toolCall",
+			Tool:      name,
+			Action:    action,
+			Entity:    entity,
+			Arguments: redact(args),
+			Result:    structured,
+			Timestamp: time.Now().UTC(),
+		}
+		event.Instance, _ = ctx.Value(instanceKey{}).(string)
+		if req.Session != nil {
+			event.Session = req.Session.ID()
+		}
+		event.Text = describeToolCall(event)
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Webhooks: failed to encode %s event: %v", name, err)
+			return result, nil
+		}
+		select {
+		case s.webhooks.queue <- body:
+		default:
+			log.Printf("Webhooks: dropped %s event, deliveries are not keeping up", name)
+		}
+		return result, nil
+	}
+}
+
+// describeToolCall summarizes a tool call in a sentence, e.g.
+// `planka-mcp: create_card "Write docs" (id 42)`
+func describeToolCall(event toolCallEvent) string {
+	text := "planka-mcp: " + event.Tool
+	if item, ok := event.Result.(map[string]interface{}); ok {
+		if name, ok := item["name"].(string); ok && name != "" {
+			text += fmt.Sprintf(" %q", name)
+		}
+		if id, ok := item["id"].(string); ok && id != "" {
+			text += fmt.Sprintf(" (id %s)", id)
+		}
+	} else if args, ok := event.Arguments.(map[string]interface{}); ok {
+		keys := make([]string, 0, len(args))
+		for key := range args {
+			if strings.HasSuffix(key, "Id") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			text += fmt.Sprintf(" %s=%v", key, args[key])
+		}
+	}
+	if event.Instance != "" {
+		text += " on " + event.Instance
+	}
+	return text
+}
+
+// run delivers queued events until the process exits
+func (w *webhookSender) run() {
+	for body := range w.queue {
+		for _, url := range w.opts.URLs {
+			if err := w.deliver(url, body); err != nil {
+				log.Printf("Webhooks: failed to deliver to %s: %v", redactURL(url), err)
+			}
+		}
+	}
+}
+
+// deliver POSTs body to url, retrying network errors and 5xx responses with backoff
+func (w *webhookSender) deliver(url string, body []byte) error {
+	var err error
+	delay := time.Second
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		var retry bool
+		if retry, err = w.post(url, body); err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// post sends body to url once and reports whether a failure is worth retrying
+func (w *webhookSender) post(url string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.opts.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.opts.Secret))
+		mac.Write(body)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// redactURL drops the path and query of a webhook URL for logging, as chat webhook
+// URLs embed their secret there
+func redactURL(raw string) string {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return "webhook"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/..."
+}
@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// outputFormatArgument is the argument of tools with structured results that selects how
+// the text of the result is rendered
+const outputFormatArgument = "outputFormat"
+
+// Output formats of the text of tool results
+const (
+	outputJSON     = "json"
+	outputMarkdown = "markdown"
+	outputTable    = "table"
+)
+
+// outputFormats are the accepted values of outputFormat
+var outputFormats = []string{outputJSON, outputMarkdown, outputTable}
+
+// maxCellRunes bounds the text of a rendered value; longer values such as descriptions are cut
+const maxCellRunes = 80
+
+// withOutputFormatArgument adds the outputFormat argument to the input schema of a tool
+func withOutputFormatArgument(tool *mcpsdk.Tool) *mcpsdk.Tool {
+	schema := maps.Clone(tool.InputSchema.(map[string]interface{}))
+	properties := maps.Clone(schema["properties"].(map[string]interface{}))
+	properties[outputFormatArgument] = map[string]interface{}{
+		"type":        "string",
+		"enum":        outputFormats,
+		"description": "How to render the text of the result: json (default), markdown for a bullet list, or table for a compact Markdown table; structured content stays JSON",
+	}
+	schema["properties"] = properties
+
+	clone := *tool
+	clone.InputSchema = schema
+	return &clone
+}
+
+// withOutputFormat wraps a tool handler so the text of its result is rendered as Markdown
+// from the structured content when the outputFormat argument asks for it. It wraps the
+// result size limit: list results are rendered from the items that fit, keeping the note
+// on how to fetch the rest, and other rendered text is held to the limit itself.
+func (s *Server) withOutputFormat(next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		var args map[string]interface{}
+		if len(req.Params.Arguments) > 0 {
+			// Malformed arguments are reported by the tool handler itself
+			json.Unmarshal(req.Params.Arguments, &args)
+		}
+		format := outputJSON
+		if value, ok := args[outputFormatArgument]; ok && value != nil {
+			str, ok := value.(string)
+			if !ok || !slices.Contains(outputFormats, str) {
+				return nil, invalidArgument(outputFormatArgument, "%s must be one of %s", outputFormatArgument, strings.Join(outputFormats, ", "))
+			}
+			format = str
+		}
+
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || format == outputJSON || result.StructuredContent == nil {
+			return result, err
+		}
+		text := renderOutput(format, result.StructuredContent)
+		if s.maxResultBytes > 0 {
+			text = cutText(text, s.maxResultBytes)
+		}
+		content := append([]mcpsdk.Content{&mcpsdk.TextContent{Text: text}}, result.Content[1:]...)
+		return &mcpsdk.CallToolResult{Content: content, StructuredContent: result.StructuredContent}, nil
+	}
+}
+
+// renderOutput renders structured content as a Markdown bullet list or table. The items of
+// list results become one bullet or row each, followed by the paging details.
+func renderOutput(format string, structured interface{}) string {
+	fields := fieldOrder(reflect.TypeOf(structured))
+	var items interface{}
+	if paged, ok := structured.(pagedResult); ok {
+		items = paged.Items
+	}
+
+	var object map[string]interface{}
+	if data, err := json.Marshal(structured); err != nil || json.Unmarshal(data, &object) != nil {
+		return fmt.Sprint(structured)
+	}
+	if items == nil {
+		return renderObject(format, object, fields)
+	}
+
+	rows, _ := object["items"].([]interface{})
+	var b strings.Builder
+	b.WriteString(renderRows(format, rows, fieldOrder(reflect.TypeOf(items))))
+	// The counts and paging details follow the items, leaving out flags that are not set
+	var details []string
+	for _, name := range fields {
+		if name != "items" && !isEmptyValue(object[name]) && object[name] != false {
+			details = append(details, fmt.Sprintf("%s: %s", name, formatValue(object[name])))
+		}
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(details, ", "))
+	}
+	return b.String()
+}
+
+// renderRows renders list items, leaving out the fields empty in every item
+func renderRows(format string, rows []interface{}, fields []string) string {
+	if len(rows) == 0 {
+		return "No items.\n"
+	}
+	objects := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		objects[i], _ = row.(map[string]interface{})
+	}
+	var columns []string
+	for _, name := range withRemainingKeys(fields, objects...) {
+		for _, object := range objects {
+			if !isEmptyValue(object[name]) {
+				columns = append(columns, name)
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	if format == outputTable {
+		writeTableRow(&b, columns)
+		separators := make([]string, len(columns))
+		for i := range separators {
+			separators[i] = "---"
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(separators, " | "))
+		for _, object := range objects {
+			cells := make([]string, len(columns))
+			for i, name := range columns {
+				cells[i] = formatCell(object[name])
+			}
+			writeTableRow(&b, cells)
+		}
+		return b.String()
+	}
+
+	// Each bullet leads with the item's name, followed by its other fields
+	title := ""
+	for _, name := range []string{"name", "text", "title"} {
+		if slices.Contains(columns, name) {
+			title = name
+			break
+		}
+	}
+	for _, object := range objects {
+		var parts []string
+		for _, name := range columns {
+			if name != title && !isEmptyValue(object[name]) {
+				parts = append(parts, fmt.Sprintf("%s: %s", name, escapeMarkdown(formatValue(object[name]))))
+			}
+		}
+		line := strings.Join(parts, ", ")
+		if title != "" {
+			line = fmt.Sprintf("**%s** — %s", escapeMarkdown(formatValue(object[title])), line)
+		}
+		fmt.Fprintf(&b, "- %s\n", strings.TrimSuffix(line, " — "))
+	}
+	return b.String()
+}
+
+// renderObject renders a single result as a bullet per field, or a table of fields and values
+func renderObject(format string, object map[string]interface{}, fields []string) string {
+	var b strings.Builder
+	if format == outputTable {
+		b.WriteString("| Field | Value |\n| --- | --- |\n")
+	}
+	for _, name := range withRemainingKeys(fields, object) {
+		value := object[name]
+		if isEmptyValue(value) {
+			continue
+		}
+		if format == outputTable {
+			writeTableRow(&b, []string{name, formatCell(value)})
+		} else {
+			fmt.Fprintf(&b, "- **%s**: %s\n", name, escapeMarkdown(formatValue(value)))
+		}
+	}
+	return b.String()
+}
+
+// writeTableRow writes a row of a Markdown table
+func writeTableRow(b *strings.Builder, cells []string) {
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// formatCell formats a value for a table cell, where pipes end the cell
+func formatCell(value interface{}) string {
+	return strings.ReplaceAll(escapeMarkdown(formatValue(value)), "|", `\|`)
+}
+
+// formatValue formats a decoded JSON value on a single line: numbers without exponents,
+// lists as their length and long text cut to maxCellRunes
+func formatValue(value interface{}) string {
+	var text string
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		text = value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		return strconv.Itoa(len(value))
+	default:
+		data, _ := json.Marshal(value)
+		text = string(data)
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxCellRunes {
+		text = string(runes[:maxCellRunes-1]) + "…"
+	}
+	return text
+}
+
+// isEmptyValue reports whether a decoded JSON value carries nothing worth showing
+func isEmptyValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// fieldOrder returns the JSON field names of a struct type (or of the elements of a slice
+// type) in declaration order, so rendered fields follow the order of the JSON text
+func fieldOrder(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			names = append(names, fieldOrder(field.Type)...)
+			continue
+		}
+		if name, _ := jsonFieldName(field); field.IsExported() && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// withRemainingKeys returns fields followed by the other keys of objects in sorted order,
+// for values whose type does not tell their fields, such as maps
+func withRemainingKeys(fields []string, objects ...map[string]interface{}) []string {
+	var rest []string
+	for _, object := range objects {
+		for key := range object {
+			if !slices.Contains(fields, key) && !slices.Contains(rest, key) {
+				rest = append(rest, key)
+			}
+		}
+	}
+	sort.Strings(rest)
+	return append(slices.Clone(fields), rest...)
+}
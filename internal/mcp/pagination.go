@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+const (
+	// toolsPageSize is the number of tools returned per tools/list page
+	toolsPageSize = 50
+	// defaultPageSize is the page size used by list tools when a cursor is given without a limit
+	defaultPageSize = 100
+)
+
+// pagedResult is the result shape of list tools. The counts tell callers whether they
+// see the whole list or only part of it.
+type pagedResult struct {
+	Items interface{} `json:"items"`
+	// TotalCount is the number of items in the full list, ReturnedCount the number in Items
+	TotalCount    int `json:"totalCount"`
+	ReturnedCount int `json:"returnedCount"`
+	// Truncated marks results holding only part of the list, because a page of it was
+	// requested or because it was cut short to fit the maximum result size
+	Truncated  bool   `json:"truncated"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize,omitempty"`
+	HasMore    bool   `json:"hasMore,omitempty"`
+	// Note explains a truncation the counts cannot show
+	Note string `json:"note,omitempty"`
+
+	// offset is the position of Items in the full list; cursor is set when the
+	// page was requested by cursor, so a truncated page can hand out a new one
+	offset int
+	cursor bool
+}
+
+// truncatedAtSource marks a list result as truncated because Planka has more items than
+// were fetched, so even TotalCount is short of the full list
+func truncatedAtSource(result interface{}, note string) interface{} {
+	if paged, ok := result.(pagedResult); ok {
+		paged.Truncated = true
+		paged.Note = note
+		return paged
+	}
+	return result
+}
+
+// newPagedResult returns the result holding items out of a list of total items
+func newPagedResult[T any](items []T, total int) pagedResult {
+	if items == nil {
+		items = []T{}
+	}
+	return pagedResult{Items: items, TotalCount: total, ReturnedCount: len(items), Truncated: len(items) < total}
+}
+
+// pageArgs are the paging and sorting arguments accepted by every list tool
+type pageArgs struct {
+	Page      int    `json:"page,omitempty" jsonschema:"Page number to return, starting at 1; page/pageSize cannot be combined with limit, offset or cursor"`
+	PageSize  int    `json:"pageSize,omitempty" jsonschema:"Number of items per page (default 100)"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"Cursor returned as nextCursor by a previous call; pass the same sortBy and sortOrder again"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of items to return"`
+	Offset    int    `json:"offset,omitempty" jsonschema:"Number of items to skip before the first one returned"`
+	SortBy    string `json:"sortBy,omitempty" jsonschema:"Field of the items to sort by before paging, e.g. name, position, createdAt, updatedAt or dueDate (default: Planka's order)"`
+	SortOrder string `json:"sortOrder,omitempty" enum:"asc,desc" jsonschema:"The sort order: asc (default) or desc, e.g. desc with sortBy updatedAt for the most recently updated first"`
+}
+
+// requested reports whether the caller asked for a page at all
+func (p pageArgs) requested() bool {
+	return p.Page > 0 || p.PageSize > 0
+}
+
+// encodeCursor encodes an offset into an opaque pagination cursor
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeCursor decodes a pagination cursor back into an offset; an empty cursor is the first page
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalidParams("invalid cursor")
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, invalidParams("invalid cursor")
+	}
+	return offset, nil
+}
+
+// paginate returns the page of items starting at offset along with the cursor of the next page
+func paginate[T any](items []T, offset, limit int) ([]T, string) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+
+	end := offset + limit
+	if end >= len(items) {
+		return items[offset:], ""
+	}
+	return items[offset:end], encodeCursor(end)
+}
+
+// pageOf returns the requested page of items along with its position in the full list
+func pageOf[T any](items []T, args pageArgs) pagedResult {
+	page := args.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	start := (page - 1) * pageSize
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	result := newPagedResult(items[start:end], len(items))
+	result.Page = page
+	result.PageSize = pageSize
+	result.HasMore = end < len(items)
+	result.offset = start
+	return result
+}
+
+// listResult applies a list tool's sorting and paging arguments to its items: page/pageSize
+// select a numbered page, limit with offset or cursor selects a slice or continues from a
+// previous call. The two schemes cannot be mixed, and neither can offset and cursor.
+// Without paging arguments the full list is returned. Either way the items are wrapped
+// with their counts.
+func listResult[T any](items []T, args pageArgs) (interface{}, error) {
+	if args.Offset < 0 {
+		return nil, invalidArgument("offset", "offset must not be negative")
+	}
+	if args.Offset > 0 && args.Cursor != "" {
+		return nil, invalidArgument("offset", "offset cannot be combined with cursor")
+	}
+	if args.requested() {
+		mixed := ""
+		switch {
+		case args.Limit > 0:
+			mixed = "limit"
+		case args.Offset > 0:
+			mixed = "offset"
+		case args.Cursor != "":
+			mixed = "cursor"
+		}
+		if mixed != "" {
+			return nil, invalidArgument(mixed, "%s cannot be combined with page or pageSize; page with either page/pageSize or limit with offset or cursor", mixed)
+		}
+	}
+	items, err := sortItems(items, args.SortBy, args.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case args.requested():
+		return pageOf(items, args), nil
+	case args.Cursor != "" || args.Limit > 0 || args.Offset > 0:
+		offset := args.Offset
+		if args.Cursor != "" {
+			if offset, err = decodeCursor(args.Cursor); err != nil {
+				return nil, err
+			}
+		}
+		page, nextCursor := paginate(items, offset, args.Limit)
+		result := newPagedResult(page, len(items))
+		result.NextCursor = nextCursor
+		result.HasMore = nextCursor != ""
+		result.offset = offset
+		result.cursor = true
+		return result, nil
+	}
+	return newPagedResult(items, len(items)), nil
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// position is a card position argument: a number, or one of "top", "bottom",
+// "after:<cardId>" and "before:<cardId>", which are resolved against the list's cards
+type position struct {
+	value float64
+	// keyword is "top", "bottom", "after" or "before"; empty for a number
+	keyword string
+	// cardID is the neighbor of "after" and "before"
+	cardID string
+}
+
+// errInvalidPosition describes the accepted position values
+var errInvalidPosition = errors.New(`position must be a number, "top", "bottom", "after:<cardId>" or "before:<cardId>"`)
+
+func (p *position) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.value); err == nil {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return errInvalidPosition
+	}
+
+	text = strings.TrimSpace(text)
+	switch keyword, cardID, _ := strings.Cut(text, ":"); strings.ToLower(keyword) {
+	case "top", "bottom":
+		if cardID != "" {
+			return errInvalidPosition
+		}
+		p.keyword = strings.ToLower(keyword)
+	case "after", "before":
+		if strings.TrimSpace(cardID) == "" {
+			return errInvalidPosition
+		}
+		p.keyword, p.cardID = strings.ToLower(keyword), strings.TrimSpace(cardID)
+	default:
+		return errInvalidPosition
+	}
+	return nil
+}
+
+// resolvePosition computes the numeric position of pos in listID from the positions of the
+// cards already there, ignoring cardID (the card being moved). A nil pos resolves to nil.
+func resolvePosition(ctx context.Context, client PlankaClient, pos *position, listID, cardID string) (*float64, error) {
+	if pos == nil {
+		return nil, nil
+	}
+	if pos.keyword == "" {
+		return &pos.value, nil
+	}
+
+	cards, err := client.GetCards(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cards of list %s to compute the position: %w", listID, err)
+	}
+	neighbors := make([]planka.Card, 0, len(cards))
+	for _, card := range cards {
+		if card.ID != cardID {
+			neighbors = append(neighbors, card)
+		}
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].Position < neighbors[j].Position })
+
+	var value float64
+	switch pos.keyword {
+	case "top":
+		value = planka.PositionGap
+		if len(neighbors) > 0 {
+			value = neighbors[0].Position / 2
+		}
+	case "bottom":
+		value = planka.PositionGap
+		if len(neighbors) > 0 {
+			value = neighbors[len(neighbors)-1].Position + planka.PositionGap
+		}
+	default:
+		i := indexOfCard(neighbors, pos.cardID)
+		if i < 0 {
+			return nil, invalidParams("position: card %s is not in list %s", pos.cardID, listID)
+		}
+		if pos.keyword == "after" {
+			value = neighbors[i].Position + planka.PositionGap
+			if i+1 < len(neighbors) {
+				value = (neighbors[i].Position + neighbors[i+1].Position) / 2
+			}
+		} else {
+			value = neighbors[i].Position / 2
+			if i > 0 {
+				value = (neighbors[i-1].Position + neighbors[i].Position) / 2
+			}
+		}
+	}
+	return &value, nil
+}
+
+// indexOfCard returns the index of cardID in cards, or -1
+func indexOfCard(cards []planka.Card, cardID string) int {
+	for i, card := range cards {
+		if card.ID == cardID {
+			return i
+		}
+	}
+	return -1
+}
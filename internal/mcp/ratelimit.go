@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// codeRateLimited is the JSON-RPC error code returned when a client exceeds its rate limit
+	codeRateLimited = -32029
+	// limiterIdleTimeout is how long an unused client limiter is kept before it is dropped
+	limiterIdleTimeout = 10 * time.Minute
+)
+
+// RateLimitOptions configures per-client rate limiting of MCP requests
+type RateLimitOptions struct {
+	// Rate is the sustained number of requests per second allowed per client
+	Rate float64
+	// Burst is the number of requests a client may make at once
+	Burst int
+}
+
+// clientLimiter is the token bucket of a single client
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter hands out a token bucket per client
+type rateLimiter struct {
+	opts RateLimitOptions
+
+	mu      sync.Mutex
+	clients map[string]*clientLimiter
+}
+
+// newRateLimiter creates a rate limiter with opts
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	return &rateLimiter{
+		opts:    opts,
+		clients: map[string]*clientLimiter{},
+	}
+}
+
+// reserve takes a token from the client's bucket. If none is available it returns
+// how long the client has to wait before its next request would be allowed.
+func (l *rateLimiter) reserve(client string) (time.Duration, bool) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop limiters of clients that went away so the map does not grow forever
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > limiterIdleTimeout {
+			delete(l.clients, key)
+		}
+	}
+
+	c, ok := l.clients[client]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(l.opts.Rate), l.opts.Burst)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return time.Duration(math.MaxInt64), false
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Rejected requests do not consume tokens
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+// middleware rejects requests from clients that exceed their rate limit with a JSON-RPC error
+// whose data carries a retryAfter hint in seconds. Notifications and stdio requests are never limited.
+func (l *rateLimiter) middleware(next mcpsdk.MethodHandler) mcpsdk.MethodHandler {
+	return func(ctx context.Context, method string, req mcpsdk.Request) (mcpsdk.Result, error) {
+		// Only HTTP clients are limited; a local stdio client has no request headers
+		if strings.HasPrefix(method, "notifications/") || req.GetExtra() == nil {
+			return next(ctx, method, req)
+		}
+
+		if delay, ok := l.reserve(rateLimitKey(req)); !ok {
+			data, _ := json.Marshal(map[string]interface{}{
+				"retryAfter": math.Ceil(delay.Seconds()),
+			})
+			return nil, &jsonrpc.Error{
+				Code:    codeRateLimited,
+				Message: "rate limit exceeded, retry later",
+				Data:    data,
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// rateLimitKey identifies the client a request is accounted to: its API key or OAuth
+// subject when authenticated, otherwise its session
+func rateLimitKey(req mcpsdk.Request) string {
+	if extra := req.GetExtra(); extra != nil {
+		if extra.TokenInfo != nil && extra.TokenInfo.UserID != "" {
+			return "user:" + extra.TokenInfo.UserID
+		}
+		if extra.Header != nil {
+			if key := requestAPIKey(&http.Request{Header: extra.Header}); key != "" {
+				// Hash the key so it is not kept in memory in the clear
+				sum := sha256.Sum256([]byte(key))
+				return "key:" + hex.EncodeToString(sum[:])
+			}
+		}
+	}
+	return "session:" + req.GetSession().ID()
+}
@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// recurrence is a card that is created again on a cron schedule, e.g. "Weekly review"
+// every Monday morning
+type recurrence struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ListID      string `json:"listId"`
+	Schedule    string `json:"schedule"`
+	// Instance is the Planka instance the list belongs to; empty for the default one
+	Instance  string     `json:"instance,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	NextRun   time.Time  `json:"nextRun"`
+	LastRun   *time.Time `json:"lastRun,omitempty"`
+	// LastCardID is the card created by the last run, LastError why it failed
+	LastCardID string `json:"lastCardId,omitempty"`
+	LastError  string `json:"lastError,omitempty"`
+}
+
+// recurrenceFile is the layout of the recurrences state file
+type recurrenceFile struct {
+	Recurrences []recurrence `json:"recurrences"`
+}
+
+// recurrenceStore keeps the recurrences and persists every change to its file
+type recurrenceStore struct {
+	path string
+
+	mu    sync.Mutex
+	items []recurrence
+}
+
+// loadRecurrences reads the recurrences stored at path; a missing file holds none
+func loadRecurrences(path string) (*recurrenceStore, error) {
+	store := &recurrenceStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file recurrenceFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid recurrences file %s: %w", path, err)
+	}
+	for _, item := range file.Recurrences {
+		if _, err := parseCron(item.Schedule); err != nil {
+			return nil, fmt.Errorf("recurrence %s: %w", item.ID, err)
+		}
+	}
+	store.items = file.Recurrences
+	return store, nil
+}
+
+// save writes the recurrences to the file. Callers hold mu.
+func (r *recurrenceStore) save() error {
+	return writeStateFile(r.path, recurrenceFile{Recurrences: r.items})
+}
+
+// writeStateFile writes v as indented JSON to path, replacing the file atomically so a
+// crash never leaves it half written
+func writeStateFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// list returns a copy of the recurrences ordered by their next run
+func (r *recurrenceStore) list() []recurrence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := append([]recurrence(nil), r.items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].NextRun.Before(items[j].NextRun) })
+	return items
+}
+
+// add stores item, which must have an ID
+func (r *recurrenceStore) add(item recurrence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, item)
+	if err := r.save(); err != nil {
+		r.items = r.items[:len(r.items)-1]
+		return fmt.Errorf("failed to save recurrences: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the recurrence with id, reporting whether it existed
+func (r *recurrenceStore) remove(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, item := range r.items {
+		if item.ID == id {
+			items := append(append([]recurrence{}, r.items[:i]...), r.items[i+1:]...)
+			previous := r.items
+			r.items = items
+			if err := r.save(); err != nil {
+				r.items = previous
+				return false, fmt.Errorf("failed to save recurrences: %w", err)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnableRecurrences loads the recurring cards stored in the state file at path and
+// exposes the add_recurrence, list_recurrences and remove_recurrence tools.
+// StartRecurrences runs the scheduler that creates the cards.
+func (s *Server) EnableRecurrences(path string) error {
+	store, err := loadRecurrences(path)
+	if err != nil {
+		return err
+	}
+	s.recurrences = store
+	s.registerTools()
+	return nil
+}
+
+// StartRecurrences starts a background scheduler that checks every interval for
+// recurrences that are due and creates their cards. A recurrence whose run was missed
+// while the server was down runs once on the first check. The scheduler stops when
+// ctx is cancelled.
+func (s *Server) StartRecurrences(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.runRecurrences(ctx, time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.runRecurrences(ctx, now)
+			}
+		}
+	}()
+}
+
+// runRecurrences creates the cards of all recurrences due at now and schedules their next runs
+func (s *Server) runRecurrences(ctx context.Context, now time.Time) {
+	store := s.recurrences
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	changed := false
+	for i := range store.items {
+		item := &store.items[i]
+		schedule, err := parseCron(item.Schedule)
+		if err != nil {
+			continue
+		}
+		if item.NextRun.IsZero() {
+			// Recurrences added to the file by hand are scheduled on first sight
+			item.NextRun = schedule.next(now.In(s.location()))
+			changed = true
+			continue
+		}
+		if item.NextRun.After(now) {
+			continue
+		}
+
+		card, err := s.createRecurringCard(ctx, *item)
+		ran := now
+		item.LastRun = &ran
+		if err != nil {
+			log.Printf("Recurrence %s (%q): %v", item.ID, item.Name, err)
+			item.LastError = err.Error()
+		} else {
+			item.LastCardID, item.LastError = card.ID, ""
+		}
+		// Failed runs are not retried, so a deleted list does not produce errors every interval
+		item.NextRun = schedule.next(now.In(s.location()))
+		changed = true
+	}
+
+	if changed {
+		if err := store.save(); err != nil {
+			log.Printf("Recurrences: failed to save %s: %v", store.path, err)
+		}
+	}
+}
+
+// createRecurringCard creates the card of item at the bottom of its list
+func (s *Server) createRecurringCard(ctx context.Context, item recurrence) (*planka.Card, error) {
+	client, err := s.backgroundClient(item.Instance)
+	if err != nil {
+		return nil, err
+	}
+
+	pos, err := resolvePosition(ctx, client, &position{keyword: "bottom"}, item.ListID, "")
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateCard(ctx, planka.CreateCardRequest{
+		Name:        item.Name,
+		Description: item.Description,
+		ListID:      item.ListID,
+		Position:    pos,
+	})
+}
+
+// recurrenceTools returns the tools managing recurring cards
+func (s *Server) recurrenceTools() []toolDef {
+	return []toolDef{
+		newTool(&mcpsdk.Tool{
+			Name:         "add_recurrence",
+			Description:  "Create a card again and again on a schedule, e.g. a \"Weekly review\" card every Monday at 9:00. The schedule is a cron expression (minute hour day-of-month month day-of-week, e.g. \"0 9 * * mon\") or @hourly, @daily, @weekly, @monthly or @yearly, in the server's timezone. Cards are added at the bottom of the list.",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(recurrence{}),
+		}, s.handleAddRecurrence),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:         "list_recurrences",
+			Description:  "List the recurring cards, soonest next run first, with the outcome of their last run",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(recurrence{}),
+		}, s.handleListRecurrences)),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:        "remove_recurrence",
+			Description: "Stop creating a recurring card. Cards it already created are kept.",
+			Annotations: destructiveAnnotations(),
+		}, s.handleRemoveRecurrence)),
+	}
+}
+
+func (s *Server) handleAddRecurrence(ctx context.Context, args addRecurrenceArgs) (interface{}, error) {
+	schedule, err := parseCron(args.Schedule)
+	if err != nil {
+		return nil, invalidParams("schedule: %v", err)
+	}
+	next := schedule.next(time.Now().In(s.location()))
+	if next.IsZero() {
+		return nil, invalidParams("schedule: %q never runs", args.Schedule)
+	}
+	// Check the list exists now rather than at the first run
+	if _, err := s.clientFor(ctx).GetList(ctx, args.ListID); err != nil {
+		return nil, err
+	}
+
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	item := recurrence{
+		ID:          newStateID(),
+		Name:        args.Name,
+		Description: args.Description,
+		ListID:      args.ListID,
+		Schedule:    args.Schedule,
+		Instance:    instance,
+		CreatedAt:   time.Now().UTC(),
+		NextRun:     next,
+	}
+	if err := s.recurrences.add(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *Server) handleListRecurrences(ctx context.Context, args listRecurrencesArgs) (interface{}, error) {
+	return s.recurrences.list(), nil
+}
+
+func (s *Server) handleRemoveRecurrence(ctx context.Context, args recurrenceArgs) (interface{}, error) {
+	removed, err := s.recurrences.remove(args.RecurrenceID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, invalidParams("no recurrence with id %q", args.RecurrenceID)
+	}
+	return `{"success": true}`, nil
+}
+
+// newStateID returns a short random ID for an entry of a state file
+func newStateID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// reminderLogger is the logger name attached to due-date reminder notifications
+const reminderLogger = "planka.reminders"
+
+// dueCard is a card coming due along with where it lives
+type dueCard struct {
+	card    planka.Card
+	board   planka.Board
+	project planka.Project
+}
+
+// StartReminders starts a background watcher that polls Planka every interval for cards
+// due within window and pushes a notifications/message to every connected client.
+// Clients only receive reminders once they have enabled logging at info level or lower.
+// Each card is announced once per due date. The watcher stops when ctx is cancelled.
+func (s *Server) StartReminders(ctx context.Context, window, interval time.Duration) {
+	go func() {
+		// Due date each card was last announced for, so reminders are not repeated
+		notified := map[string]time.Time{}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkDueDates(ctx, window, notified)
+			}
+		}
+	}()
+}
+
+// checkDueDates announces cards that have come due within window since the last check
+func (s *Server) checkDueDates(ctx context.Context, window time.Duration, notified map[string]time.Time) {
+	now := time.Now()
+
+	cards, err := s.findDueCards(ctx, now, now.Add(window))
+	if err != nil {
+		log.Printf("Due-date reminders: %v", err)
+		return
+	}
+
+	for _, due := range cards {
+		dueDate := *due.card.DueDate
+		if last, ok := notified[due.card.ID]; ok && last.Equal(dueDate) {
+			continue
+		}
+		// Cards are only marked as announced once a client was connected to hear about them
+		if s.notifyDueCard(ctx, due) {
+			notified[due.card.ID] = dueDate
+		}
+	}
+
+	// Forget cards whose due date has passed; a new due date will be announced again
+	for cardID, dueDate := range notified {
+		if dueDate.Before(now) {
+			delete(notified, cardID)
+		}
+	}
+}
+
+// findDueCards returns all cards with a due date between from and to
+func (s *Server) findDueCards(ctx context.Context, from, to time.Time) ([]dueCard, error) {
+	client := s.client
+
+	projects, err := client.GetProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	var due []dueCard
+	for _, project := range projects {
+		boards, err := client.GetBoards(ctx, project.ID)
+		if err != nil {
+			log.Printf("Due-date reminders: failed to get boards for project %s: %v", project.ID, err)
+			continue
+		}
+		for _, board := range boards {
+			cards, err := client.GetBoardCards(ctx, board.ID)
+			if err != nil {
+				log.Printf("Due-date reminders: failed to get cards for board %s: %v", board.ID, err)
+				continue
+			}
+			for _, card := range cards {
+				if card.DueDate == nil || card.DueDate.Before(from) || card.DueDate.After(to) {
+					continue
+				}
+				due = append(due, dueCard{card: card, board: board, project: project})
+			}
+		}
+	}
+	return due, nil
+}
+
+// notifyDueCard sends a reminder for a card to every connected client.
+// It reports whether any client was connected.
+func (s *Server) notifyDueCard(ctx context.Context, due dueCard) bool {
+	params := &mcpsdk.LoggingMessageParams{
+		Level:  "info",
+		Logger: reminderLogger,
+		Data: map[string]interface{}{
+			"type":        "dueDateReminder",
+			"message":     fmt.Sprintf("Card %q on board %q is due %s", due.card.Name, due.board.Name, due.card.DueDate.Format(time.RFC3339)),
+			"cardId":      due.card.ID,
+			"cardName":    due.card.Name,
+			"listId":      due.card.ListID,
+			"boardId":     due.board.ID,
+			"boardName":   due.board.Name,
+			"projectId":   due.project.ID,
+			"projectName": due.project.Name,
+			"dueDate":     due.card.DueDate,
+		},
+	}
+
+	sent := false
+	for session := range s.server.Sessions() {
+		sent = true
+		if err := session.Log(ctx, params); err != nil {
+			log.Printf("Due-date reminders: failed to notify session %s: %v", session.ID(), err)
+		}
+	}
+	return sent
+}
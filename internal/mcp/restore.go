@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// restoreAction is a change restore_board makes to bring a board back to a snapshot
+type restoreAction struct {
+	// Action is "createList", "updateList", "createCard", "updateCard" or "deleteCard"
+	Action string `json:"action"`
+	// ID is the list or card in the snapshot, or on the board for deleteCard
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ListID is the list a created or updated card ends up in, as in the snapshot
+	ListID string `json:"listId,omitempty"`
+	// Changes names what an update reverts: list, position, name, dueDate or labels
+	Changes []string `json:"changes,omitempty"`
+	// NewID is the ID of a recreated list or card; Planka cannot bring back the old one
+	NewID string `json:"newId,omitempty"`
+
+	list snapshotList
+	card snapshotCard
+	// labels are the labels of the snapshot an existing card lacks
+	labels []string
+}
+
+// boardRestore is the result of restore_board
+type boardRestore struct {
+	BoardID string    `json:"boardId"`
+	TakenAt time.Time `json:"takenAt"`
+	DryRun  bool      `json:"dryRun"`
+	// Actions is the plan, in the order it is carried out
+	Actions []restoreAction `json:"actions"`
+	Applied int             `json:"applied"`
+	// Errors describes actions that failed; the others are still carried out
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (s *Server) handleRestoreBoard(ctx context.Context, args restoreBoardArgs) (interface{}, error) {
+	snapshot, err := s.argumentSnapshot("snapshotId", "snapshot", args.SnapshotID, args.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, invalidParams("snapshotId or snapshot is required")
+	}
+	client := s.clientFor(ctx)
+	current, err := s.takeSnapshot(ctx, client, snapshot.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := boardRestore{
+		BoardID: snapshot.BoardID,
+		TakenAt: snapshot.TakenAt,
+		DryRun:  args.DryRun,
+		Actions: planRestore(*snapshot, *current, args.DeleteAdded),
+	}
+	if args.DryRun {
+		return result, nil
+	}
+
+	// Cards of recreated lists go to the new lists
+	listIDs := map[string]string{}
+	for i := range result.Actions {
+		action := &result.Actions[i]
+		if action.ListID != "" {
+			if id, ok := listIDs[action.ListID]; ok {
+				action.ListID = id
+			}
+		}
+		if err := applyRestoreAction(ctx, client, snapshot.BoardID, action); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %v", action.Action, action.ID, err))
+			continue
+		}
+		if action.Action == "createList" {
+			listIDs[action.ID] = action.NewID
+		}
+		result.Applied++
+	}
+	return result, nil
+}
+
+// planRestore lists the changes that bring a board as it is now, current, back to an earlier
+// snapshot of it: lists and cards removed since are recreated, and lists and cards moved,
+// renamed or relabeled since are put back. Cards added since are deleted only if
+// deleteAdded is set; lists added since are left alone.
+func planRestore(snapshot, current boardSnapshot, deleteAdded bool) []restoreAction {
+	actions := []restoreAction{}
+	lists := map[string]snapshotList{}
+	for _, list := range current.Lists {
+		lists[list.ID] = list
+	}
+	for _, list := range snapshot.Lists {
+		now, ok := lists[list.ID]
+		if !ok {
+			actions = append(actions, restoreAction{Action: "createList", ID: list.ID, Name: list.Name, list: list})
+			continue
+		}
+		action := restoreAction{Action: "updateList", ID: list.ID, Name: list.Name, list: list}
+		if now.Position != list.Position {
+			action.Changes = append(action.Changes, "position")
+		}
+		if now.Name != list.Name {
+			action.Changes = append(action.Changes, "name")
+		}
+		if len(action.Changes) > 0 {
+			actions = append(actions, action)
+		}
+	}
+
+	cards := map[string]snapshotCard{}
+	for _, card := range current.Cards {
+		cards[card.ID] = card
+	}
+	kept := map[string]bool{}
+	for _, card := range snapshot.Cards {
+		now, ok := cards[card.ID]
+		if !ok {
+			actions = append(actions, restoreAction{Action: "createCard", ID: card.ID, Name: card.Name, ListID: card.ListID, card: card})
+			continue
+		}
+		kept[card.ID] = true
+
+		action := restoreAction{Action: "updateCard", ID: card.ID, Name: card.Name, ListID: card.ListID, card: card}
+		if now.ListID != card.ListID {
+			action.Changes = append(action.Changes, "list")
+		}
+		if now.Position != card.Position {
+			action.Changes = append(action.Changes, "position")
+		}
+		if now.Name != card.Name {
+			action.Changes = append(action.Changes, "name")
+		}
+		// A due date can be restored, but not removed from a card that got one since
+		if card.DueDate != nil && (now.DueDate == nil || !now.DueDate.Equal(*card.DueDate)) {
+			action.Changes = append(action.Changes, "dueDate")
+		}
+		for _, label := range card.Labels {
+			if !slices.Contains(now.Labels, label) {
+				action.labels = append(action.labels, label)
+			}
+		}
+		if len(action.labels) > 0 {
+			action.Changes = append(action.Changes, "labels")
+		}
+		if len(action.Changes) > 0 {
+			actions = append(actions, action)
+		}
+	}
+
+	if deleteAdded {
+		for _, card := range current.Cards {
+			if !kept[card.ID] {
+				actions = append(actions, restoreAction{Action: "deleteCard", ID: card.ID, Name: card.Name, ListID: card.ListID})
+			}
+		}
+	}
+	return actions
+}
+
+// applyRestoreAction carries out one change of a restore plan
+func applyRestoreAction(ctx context.Context, client PlankaClient, boardID string, action *restoreAction) error {
+	switch action.Action {
+	case "createList":
+		list, err := client.CreateList(ctx, planka.CreateListRequest{Name: action.list.Name, BoardID: boardID, Position: &action.list.Position})
+		if err != nil {
+			return err
+		}
+		action.NewID = list.ID
+		return nil
+	case "updateList":
+		req := planka.UpdateListRequest{}
+		if slices.Contains(action.Changes, "position") {
+			req.Position = &action.list.Position
+		}
+		if slices.Contains(action.Changes, "name") {
+			req.Name = &action.list.Name
+		}
+		_, err := client.UpdateList(ctx, action.ID, req)
+		return err
+	case "createCard":
+		card, err := client.CreateCard(ctx, planka.CreateCardRequest{
+			Name:        action.card.Name,
+			Description: action.card.Description,
+			ListID:      action.ListID,
+			Position:    &action.card.Position,
+			DueDate:     action.card.DueDate,
+		})
+		if err != nil {
+			return err
+		}
+		action.NewID = card.ID
+		for _, label := range action.card.Labels {
+			if _, err := addLabelByName(ctx, client, boardID, card.ID, label); err != nil {
+				return fmt.Errorf("card recreated as %s, but label %q: %w", card.ID, label, err)
+			}
+		}
+		return nil
+	case "updateCard":
+		req := planka.UpdateCardRequest{}
+		if slices.Contains(action.Changes, "list") || slices.Contains(action.Changes, "position") {
+			req.ListID, req.Position = &action.ListID, &action.card.Position
+		}
+		if slices.Contains(action.Changes, "name") {
+			req.Name = &action.card.Name
+		}
+		if slices.Contains(action.Changes, "dueDate") {
+			req.DueDate = action.card.DueDate
+		}
+		if req != (planka.UpdateCardRequest{}) {
+			if _, err := client.UpdateCard(ctx, action.ID, req); err != nil {
+				return err
+			}
+		}
+		for _, label := range action.labels {
+			if _, err := addLabelByName(ctx, client, boardID, action.ID, label); err != nil {
+				return fmt.Errorf("label %q: %w", label, err)
+			}
+		}
+		return nil
+	case "deleteCard":
+		return client.DeleteCard(ctx, action.ID)
+	}
+	return fmt.Errorf("unknown action %q", action.Action)
+}
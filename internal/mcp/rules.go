@@ -0,0 +1,387 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ruleEventBacklog is how many card changes may wait for the rules before new ones are dropped
+const ruleEventBacklog = 100
+
+// rule changes cards that match its condition, e.g. adds the label Bug to cards whose
+// name contains "bug"
+type rule struct {
+	ID string `json:"id"`
+	// Name says what the rule is for
+	Name string `json:"name,omitempty"`
+	// BoardID limits the rule to one board; empty applies it to all boards
+	BoardID string `json:"boardId,omitempty"`
+	// Instance is the Planka instance the rule applies to; empty for the default one
+	Instance  string        `json:"instance,omitempty"`
+	When      ruleCondition `json:"when"`
+	Then      ruleAction    `json:"then"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// ruleCondition is what a card must match for a rule to apply; every given field must match
+type ruleCondition struct {
+	// NameContains and DescriptionContains are matched ignoring case
+	NameContains        string `json:"nameContains,omitempty"`
+	DescriptionContains string `json:"descriptionContains,omitempty"`
+	ListID              string `json:"listId,omitempty"`
+}
+
+// ruleAction is what a rule does to a matching card
+type ruleAction struct {
+	// AddLabel is the name of a label to add; it is created if the board lacks it
+	AddLabel string `json:"addLabel,omitempty"`
+	// MoveToList is the name or ID of a list on the card's board to move the card to
+	MoveToList string `json:"moveToList,omitempty"`
+}
+
+// ruleFile is the layout of the rules state file
+type ruleFile struct {
+	Rules []rule `json:"rules"`
+}
+
+// ruleStore keeps the rules and persists every change to its file
+type ruleStore struct {
+	path string
+
+	mu    sync.Mutex
+	rules []rule
+}
+
+// loadRules reads the rules stored at path; a missing file holds none
+func loadRules(path string) (*ruleStore, error) {
+	store := &ruleStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file ruleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	for _, r := range file.Rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+	}
+	store.rules = file.Rules
+	return store, nil
+}
+
+// validate checks that r has a condition and an action
+func (r rule) validate() error {
+	if r.When == (ruleCondition{}) {
+		return errors.New("a rule needs a condition: nameContains, descriptionContains or listId")
+	}
+	if r.Then == (ruleAction{}) {
+		return errors.New("a rule needs an action: addLabel or moveToList")
+	}
+	return nil
+}
+
+// list returns a copy of the rules in the order they are applied
+func (r *ruleStore) list() []rule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]rule(nil), r.rules...)
+}
+
+// add stores item, which must have an ID, after the other rules
+func (r *ruleStore) add(item rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rules := append(append([]rule{}, r.rules...), item)
+	if err := writeStateFile(r.path, ruleFile{Rules: rules}); err != nil {
+		return fmt.Errorf("failed to save rules: %w", err)
+	}
+	r.rules = rules
+	return nil
+}
+
+// remove deletes the rule with id, reporting whether it existed
+func (r *ruleStore) remove(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rules := []rule{}
+	for _, item := range r.rules {
+		if item.ID != id {
+			rules = append(rules, item)
+		}
+	}
+	if len(rules) == len(r.rules) {
+		return false, nil
+	}
+	if err := writeStateFile(r.path, ruleFile{Rules: rules}); err != nil {
+		return false, fmt.Errorf("failed to save rules: %w", err)
+	}
+	r.rules = rules
+	return true, nil
+}
+
+// EnableRules loads the rules stored in the state file at path and exposes the add_rule,
+// list_rules and remove_rule tools. From then on the rules are applied to the cards
+// created, updated and moved through the server; StartRuleEvents applies them to changes
+// made in Planka too.
+func (s *Server) EnableRules(path string) error {
+	store, err := loadRules(path)
+	if err != nil {
+		return err
+	}
+	s.rules = store
+	s.registerTools()
+	return nil
+}
+
+// StartRuleEvents applies the rules to the cards created or updated in Planka, as received
+// over its socket or webhooks, until ctx is cancelled. Rules only act on cards that are not
+// already as they want them, so the changes they make do not set them off again.
+func (s *Server) StartRuleEvents(ctx context.Context) {
+	events := s.events.Subscribe(ruleEventBacklog)
+	go func() {
+		defer events.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events.C:
+				if event.Entity != "card" || (event.Action != "create" && event.Action != "update") {
+					continue
+				}
+				var card planka.Card
+				if err := json.Unmarshal(event.Item, &card); err != nil || card.ID == "" {
+					continue
+				}
+				if s.client != nil {
+					s.runRules(ctx, s.client, "", &card)
+				}
+			}
+		}
+	}()
+}
+
+// applyRules applies the rules to a card created or changed by a tool call and returns the
+// card as the rules left it
+func (s *Server) applyRules(ctx context.Context, client PlankaClient, card *planka.Card) *planka.Card {
+	if s.rules == nil {
+		return card
+	}
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	if !s.runRules(ctx, client, instance, card) {
+		return card
+	}
+	if updated, err := client.GetCard(ctx, card.ID); err == nil {
+		return updated
+	}
+	return card
+}
+
+// runRules applies the rules of instance that match card, in order, and reports whether
+// any of them changed it. Failures are logged, so one broken rule does not stop the others
+// or fail the tool call that set them off.
+func (s *Server) runRules(ctx context.Context, client PlankaClient, instance string, card *planka.Card) bool {
+	rules := s.rules.list()
+	if len(rules) == 0 {
+		return false
+	}
+	list, err := client.GetList(ctx, card.ListID)
+	if err != nil {
+		log.Printf("Rules: card %s: %v", card.ID, err)
+		return false
+	}
+
+	changed := false
+	for _, r := range rules {
+		if r.Instance != instance || (r.BoardID != "" && r.BoardID != list.BoardID) || !r.When.matches(card) {
+			continue
+		}
+		applied, err := r.Then.apply(ctx, client, list.BoardID, card)
+		if err != nil {
+			log.Printf("Rule %s: card %s: %v", r.ID, card.ID, err)
+		}
+		for _, action := range applied {
+			log.Printf("Rule %s: card %s: %s", r.ID, card.ID, action)
+		}
+		changed = changed || len(applied) > 0
+	}
+	return changed
+}
+
+// matches reports whether card meets the condition
+func (c ruleCondition) matches(card *planka.Card) bool {
+	if c.NameContains != "" && !strings.Contains(strings.ToLower(card.Name), strings.ToLower(c.NameContains)) {
+		return false
+	}
+	if c.DescriptionContains != "" && !strings.Contains(strings.ToLower(card.Description), strings.ToLower(c.DescriptionContains)) {
+		return false
+	}
+	return c.ListID == "" || c.ListID == card.ListID
+}
+
+// apply carries out the action on card, a card of boardID, skipping what is already done.
+// It returns a description of each change made, and updates card to match.
+func (a ruleAction) apply(ctx context.Context, client PlankaClient, boardID string, card *planka.Card) ([]string, error) {
+	var applied []string
+	if a.AddLabel != "" {
+		added, err := addLabelByName(ctx, client, boardID, card.ID, a.AddLabel)
+		if err != nil {
+			return applied, fmt.Errorf("label %q: %w", a.AddLabel, err)
+		}
+		if added {
+			applied = append(applied, fmt.Sprintf("added label %q", a.AddLabel))
+		}
+	}
+	if a.MoveToList != "" {
+		lists, err := client.GetLists(ctx, boardID)
+		if err != nil {
+			return applied, err
+		}
+		var target *planka.List
+		for i, list := range lists {
+			if list.ID == a.MoveToList || strings.EqualFold(list.Name, a.MoveToList) {
+				target = &lists[i]
+				break
+			}
+		}
+		if target == nil {
+			return applied, fmt.Errorf("board %s has no list %q", boardID, a.MoveToList)
+		}
+		if target.ID != card.ListID {
+			pos, err := resolvePosition(ctx, client, &position{keyword: "bottom"}, target.ID, card.ID)
+			if err != nil {
+				return applied, err
+			}
+			if _, err := client.MoveCard(ctx, card.ID, target.ID, *pos); err != nil {
+				return applied, err
+			}
+			card.ListID = target.ID
+			applied = append(applied, fmt.Sprintf("moved to list %q", target.Name))
+		}
+	}
+	return applied, nil
+}
+
+// addLabelByName adds the label named name to a card of boardID, creating the label if the
+// board lacks it, and reports whether the card did not have it yet
+func addLabelByName(ctx context.Context, client PlankaClient, boardID, cardID, name string) (bool, error) {
+	labels, err := client.GetLabels(ctx, boardID)
+	if err != nil {
+		return false, err
+	}
+	labelID := ""
+	for _, label := range labels {
+		if strings.EqualFold(label.Name, name) {
+			labelID = label.ID
+			break
+		}
+	}
+	if labelID == "" {
+		label, err := client.CreateLabel(ctx, planka.CreateLabelRequest{BoardID: boardID, Name: name, Color: labelColor(name)})
+		if err != nil {
+			return false, err
+		}
+		labelID = label.ID
+	} else {
+		cardLabels, err := client.GetCardLabels(ctx, boardID)
+		if err != nil {
+			return false, err
+		}
+		for _, cardLabel := range cardLabels {
+			if cardLabel.CardID == cardID && cardLabel.LabelID == labelID {
+				return false, nil
+			}
+		}
+	}
+	if err := client.AddCardLabel(ctx, cardID, labelID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ruleTools returns the tools managing rules
+func (s *Server) ruleTools() []toolDef {
+	return []toolDef{
+		newTool(&mcpsdk.Tool{
+			Name:         "add_rule",
+			Description:  "Add a rule applied to every card created, updated or moved, e.g. add the label Bug to cards whose name contains \"bug\", or move cards mentioning \"urgent\" to the Today list. Give at least one condition (nameContains, descriptionContains, inListId) and one action (addLabel, moveToList).",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(rule{}),
+		}, s.handleAddRule),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:         "list_rules",
+			Description:  "List the rules in the order they are applied",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(rule{}),
+		}, s.handleListRules)),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:        "remove_rule",
+			Description: "Remove a rule. Changes it already made to cards are kept.",
+			Annotations: destructiveAnnotations(),
+		}, s.handleRemoveRule)),
+	}
+}
+
+func (s *Server) handleAddRule(ctx context.Context, args addRuleArgs) (interface{}, error) {
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	item := rule{
+		ID:       newStateID(),
+		Name:     args.Name,
+		BoardID:  args.BoardID,
+		Instance: instance,
+		When: ruleCondition{
+			NameContains:        strings.TrimSpace(args.NameContains),
+			DescriptionContains: strings.TrimSpace(args.DescriptionContains),
+			ListID:              args.InListID,
+		},
+		Then: ruleAction{
+			AddLabel:   strings.TrimSpace(args.AddLabel),
+			MoveToList: strings.TrimSpace(args.MoveToList),
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := item.validate(); err != nil {
+		return nil, invalidParams("%v", err)
+	}
+	// Check the board exists now rather than when the rule first applies
+	if args.BoardID != "" {
+		if _, err := s.clientFor(ctx).GetBoard(ctx, args.BoardID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.rules.add(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *Server) handleListRules(ctx context.Context, args listRulesArgs) (interface{}, error) {
+	return s.rules.list(), nil
+}
+
+func (s *Server) handleRemoveRule(ctx context.Context, args ruleArgs) (interface{}, error) {
+	removed, err := s.rules.remove(args.RuleID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, invalidParams("no rule with id %q", args.RuleID)
+	}
+	return `{"success": true}`, nil
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	positionType = reflect.TypeOf(position{})
+	dueDateType  = reflect.TypeOf(dueDate(""))
+)
+
+// schemaFor generates a JSON Schema describing how values of type t are encoded by encoding/json
+func schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t == positionType {
+		return map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "string", "pattern": "^(top|bottom|(after|before):.+)$"},
+		}}
+	}
+
+	if t == dueDateType {
+		return map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer"},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _ := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			properties[name] = schemaFor(field.Type)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	default:
+		// interface{} and friends can hold anything
+		return map[string]interface{}{}
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is tagged omitempty
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// inputSchema generates the inputSchema of a tool from its argument struct.
+// Fields are required unless tagged omitempty, described by their jsonschema tag and
+// limited to the comma-separated values of their enum tag;
+// fields of embedded structs are promoted like encoding/json does.
+func inputSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	addInputProperties(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addInputProperties adds the properties of argument struct t to properties
+func addInputProperties(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			addInputProperties(field.Type, properties, required)
+			continue
+		}
+		name, omitempty := jsonFieldName(field)
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		property := schemaFor(field.Type)
+		// The values of enum-like arguments, or of the items of array arguments
+		if values := field.Tag.Get("enum"); values != "" {
+			if items, ok := property["items"].(map[string]interface{}); ok {
+				items["enum"] = strings.Split(values, ",")
+			} else {
+				property["enum"] = strings.Split(values, ",")
+			}
+		}
+		if description := field.Tag.Get("jsonschema"); description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+		if !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// outputSchema returns the outputSchema of a tool returning a single value like v
+func outputSchema(v interface{}) map[string]interface{} {
+	return schemaFor(reflect.TypeOf(v))
+}
+
+// listOutputSchema returns the outputSchema of a list tool returning values like v.
+// List results are wrapped in an object with their counts.
+func listOutputSchema(v interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"items":         schemaFor(reflect.SliceOf(reflect.TypeOf(v))),
+			"totalCount":    map[string]interface{}{"type": "integer"},
+			"returnedCount": map[string]interface{}{"type": "integer"},
+			"truncated":     map[string]interface{}{"type": "boolean"},
+			"nextCursor":    map[string]interface{}{"type": "string"},
+			"page":          map[string]interface{}{"type": "integer"},
+			"pageSize":      map[string]interface{}{"type": "integer"},
+			"hasMore":       map[string]interface{}{"type": "boolean"},
+			"note":          map[string]interface{}{"type": "string"},
+		},
+		"required": []string{"items", "totalCount", "returnedCount", "truncated"},
+	}
+}
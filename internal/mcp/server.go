@@ -1,206 +1,228 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"os"
-
-	"github.com/ayushgarg/mcp-planka/internal/planka"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/eventbus"
+	"github.com/ayushgarg/mcp-planka/internal/version"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // Server represents an MCP server
 type Server struct {
-	client *planka.Client
+	client PlankaClient
+	server *mcpsdk.Server
+
+	// instances are the named Planka servers tools can target; defaultInstance
+	// names the one used when no instance is given
+	instances       map[string]PlankaClient
+	defaultInstance string
+	// filter hides tools operators chose not to expose; nil exposes all
+	filter *toolFilter
+	// readOnly hides the tools that change Planka
+	readOnly bool
+	// tenants holds per-token clients in multi-tenant mode; nil otherwise
+	tenants *clientPool
+	// maxResultBytes caps the size of tool results; zero means unlimited
+	maxResultBytes int
+	// writeTimeout is the HTTP write timeout tool calls must answer within; zero means none
+	writeTimeout time.Duration
+	// metrics are the Planka client metrics get_server_stats reports; nil if not collected
+	metrics *planka.Metrics
+	// started is when the server was created
+	started time.Time
+	// timezone is where due date phrases are interpreted; nil means local time
+	timezone *time.Location
+	// recurrences are the cards created on a schedule; nil unless enabled
+	recurrences *recurrenceStore
+	// velocity holds the sprints sprint_report recorded; nil unless enabled
+	velocity *velocityStore
+	// sortedLists are the lists kept sorted in the background; nil unless enabled
+	sortedLists *sortedListStore
+	// rules change the cards created and updated that match them; nil unless enabled
+	rules *ruleStore
+	// snapshotsDir is where snapshot_board saves snapshots; empty if they are not saved
+	snapshotsDir string
+	// doneLists are the lists cleanup_done_cards cleans up by default
+	doneLists []string
+	// events carries changes in Planka, from its socket or webhooks, to the notifications
+	// sent to clients; notifyEvents starts forwarding them once
+	events       *eventbus.Bus
+	notifyEvents sync.Once
+	// webhooks deliver the successful mutating tool calls; nil unless configured
+	webhooks *webhookSender
+	// gitlabURL and gitlabToken are the GitLab defaults of import_gitlab_issues
+	gitlabURL   string
+	gitlabToken string
+	// names caches the entities fetched to resolve name arguments to IDs
+	names nameIndexes
+	// undo holds the recent changes of each session for undo_last
+	undo undoHistory
+	// dryRun reports the requests tools would send to change Planka instead of sending them
+	dryRun bool
 }
 
-// NewServer creates a new MCP server
-func NewServer(client *planka.Client) *Server {
-	return &Server{
-		client: client,
+// NewServer creates a new MCP server acting on Planka through client
+// Protocol handling (lifecycle, capabilities, cancellation, batching and pagination
+// of tools/list) is provided by the MCP Go SDK; this package supplies the Planka tools.
+func NewServer(client PlankaClient) *Server {
+	// A nil *planka.Client (e.g. multi-tenant mode without server-wide credentials)
+	// must compare equal to nil
+	if c, ok := client.(*planka.Client); ok && c == nil {
+		client = nil
+	}
+	s := &Server{
+		client:  client,
+		started: time.Now(),
+		events:  eventbus.New(),
 	}
-}
 
-// StartStdio starts the MCP server in stdio mode
-func (s *Server) StartStdio() error {
-	// MCP servers communicate via stdio
-	decoder := json.NewDecoder(os.Stdin)
-	encoder := json.NewEncoder(os.Stdout)
-
-	// Wait for and handle initialization request
-	initialized := false
-	for {
-		var request map[string]interface{}
-		if err := decoder.Decode(&request); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("failed to decode request: %w", err)
-		}
+	s.server = mcpsdk.NewServer(&mcpsdk.Implementation{
+		Name:    "planka-mcp",
+		Version: version.Version,
+	}, &mcpsdk.ServerOptions{
+		PageSize:          toolsPageSize,
+		CompletionHandler: s.handleComplete,
+	})
 
-		method, _ := request["method"].(string)
-		id, _ := request["id"]
+	s.registerTools()
 
-		// Handle initialization
-		if method == "initialize" {
-			if err := s.handleInitialize(request, encoder, id); err != nil {
-				return fmt.Errorf("failed to handle initialize: %w", err)
-			}
-			initialized = true
-			continue
-		}
+	return s
+}
 
-		// Handle initialized notification
-		if method == "notifications/initialized" {
-			// Client is now ready, continue to normal request handling
+// registerTools adds the exposed Planka tools to the MCP server, replacing any registered before
+func (s *Server) registerTools() {
+	names := s.instanceNames()
+	for _, def := range s.getTools() {
+		if !s.filter.allowed(def.tool.Name) || (s.readOnly && (def.tool.Annotations == nil || !def.tool.Annotations.ReadOnlyHint)) {
+			s.server.RemoveTools(def.tool.Name)
 			continue
 		}
-
-		// Only handle other requests after initialization
-		if !initialized {
-			return fmt.Errorf("received request before initialization")
+		tool, handler := def.tool, def.handler
+		mutating := tool.Annotations == nil || !tool.Annotations.ReadOnlyHint
+		if s.webhooks != nil && mutating {
+			handler = s.notifyWebhooks(tool.Name, handler)
 		}
-
-		if err := s.handleRequest(request, encoder); err != nil {
-			log.Printf("Error handling request: %v", err)
-			s.sendError(encoder, request, err)
+		if mutating {
+			// Destructive tools can be tried out call by call
+			perCall := tool.Annotations != nil && tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint &&
+				!hasArgument(tool, dryRunArgument)
+			if perCall {
+				tool = withDryRunArgument(tool)
+			}
+			if perCall || s.dryRun {
+				handler = s.withDryRun(tool.Name, perCall, handler)
+			}
 		}
-	}
-
-	return nil
-}
-
-// buildInitializeResponse builds the response for initialize
-func (s *Server) buildInitializeResponse(id interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"protocolVersion": "2024-11-05",
-			"capabilities": map[string]interface{}{
-				"tools": map[string]interface{}{},
-			},
-			"serverInfo": map[string]interface{}{
-				"name":    "planka-mcp",
-				"version": "1.0.0",
-			},
-		},
-		"id": id,
+		handler = s.limitResultSize(handler)
+		// Structured results can be read as Markdown instead of JSON, except for reports
+		// that render themselves; the rendering keeps to the result size limit
+		if tool.OutputSchema != nil && !hasArgument(tool, "format") {
+			tool = withOutputFormatArgument(tool)
+			handler = s.withOutputFormat(handler)
+		}
+		handler = s.withSession(handler)
+		if !def.local {
+			// Agents may name projects, boards, lists and cards instead of knowing their IDs
+			required, _ := tool.InputSchema.(map[string]interface{})["required"].([]string)
+			tool = withNameArguments(tool)
+			handler = s.resolveNames(required, handler)
+		}
+		if len(names) > 0 && !def.local {
+			tool = withInstanceArgument(tool, names, s.defaultInstance)
+			handler = s.routeInstance(handler)
+		}
+		s.server.AddTool(tool, handler)
 	}
 }
 
-// handleInitialize handles the initialize request (stdio mode)
-func (s *Server) handleInitialize(request map[string]interface{}, encoder *json.Encoder, id interface{}) error {
-	response := s.buildInitializeResponse(id)
-	return encoder.Encode(response)
+// StartStdio starts the MCP server in stdio mode
+// Messages without an id are notifications and never receive a response; notifications
+// the server has no handler for (anything besides initialized, cancelled and
+// roots/list_changed) are accepted and ignored rather than answered with an error.
+func (s *Server) StartStdio() error {
+	return s.server.Run(context.Background(), &mcpsdk.StdioTransport{})
 }
 
-// handleMCPRequest handles an MCP request and returns the response map
-// This is the shared request handler used by both stdio and HTTP modes
-func (s *Server) handleMCPRequest(request map[string]interface{}) (map[string]interface{}, error) {
-	method, ok := request["method"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing method in request")
-	}
+// toolHandler adapts a typed tool handler to the SDK: arguments are validated against
+// the tool's inputSchema, decoded into the handler's argument struct, and the result encoded
+func toolHandler[In any](schema map[string]interface{}, handler func(ctx context.Context, args In) (interface{}, error)) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		raw := map[string]interface{}{}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &raw); err != nil {
+				return nil, invalidParams("invalid arguments: %v", err)
+			}
+		}
+		if err := validateArguments(schema, raw); err != nil {
+			return nil, err
+		}
 
-	id, _ := request["id"]
+		var args In
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return nil, invalidParams("invalid arguments: %v", err)
+			}
+		}
 
-	switch method {
-	case "tools/list":
-		return s.buildToolsListResponse(id), nil
-	case "tools/call":
-		return s.buildToolsCallResponse(request, id)
-	default:
-		return nil, fmt.Errorf("unknown method: %s", method)
-	}
-}
+		result, err := handler(ctx, args)
+		if err != nil {
+			// Protocol-level errors (e.g. invalid params) are returned as JSON-RPC errors;
+			// anything else is a tool failure reported in the result so the model can react to it
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				return nil, rpcErr
+			}
+			toolResult := &mcpsdk.CallToolResult{}
+			toolResult.SetError(describeError(err))
+			return toolResult, nil
+		}
 
-// handleRequest handles an MCP request (stdio mode)
-func (s *Server) handleRequest(request map[string]interface{}, encoder *json.Encoder) error {
-	response, err := s.handleMCPRequest(request)
-	if err != nil {
-		return err
+		return buildToolResult(result)
 	}
-	return encoder.Encode(response)
 }
 
-// buildToolsListResponse builds the response for tools/list
-func (s *Server) buildToolsListResponse(id interface{}) map[string]interface{} {
-	tools := s.getTools()
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"tools": tools,
-		},
-		"id": id,
+// buildToolResult encodes a tool handler's return value as an MCP tool result.
+// Plain strings become text content and formatted reports their rendered text; anything
+// else is returned both as structuredContent and, for clients without structured output
+// support, as JSON text.
+func buildToolResult(result interface{}) (*mcpsdk.CallToolResult, error) {
+	if text, ok := result.(string); ok {
+		return &mcpsdk.CallToolResult{
+			Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: text}},
+		}, nil
 	}
-}
-
-// handleToolsList handles the tools/list request (stdio mode)
-func (s *Server) handleToolsList(encoder *json.Encoder, id interface{}) error {
-	response := s.buildToolsListResponse(id)
-	return encoder.Encode(response)
-}
-
-// buildToolsCallResponse builds the response for tools/call
-func (s *Server) buildToolsCallResponse(request map[string]interface{}, id interface{}) (map[string]interface{}, error) {
-	params, ok := request["params"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("missing params in request")
+	if formatted, ok := result.(formattedResult); ok {
+		return &mcpsdk.CallToolResult{
+			Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: formatted.Text}},
+			StructuredContent: formatted.Data,
+		}, nil
 	}
 
-	toolName, ok := params["name"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing name in params")
+	// List results are wrapped with their counts, which also makes them the JSON object
+	// structuredContent must be
+	if v := reflect.ValueOf(result); v.Kind() == reflect.Slice {
+		if v.IsNil() {
+			result = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		}
+		result = pagedResult{Items: result, TotalCount: v.Len(), ReturnedCount: v.Len()}
 	}
+	structured := result
 
-	arguments, _ := params["arguments"].(map[string]interface{})
-
-	result, err := s.callTool(toolName, arguments)
+	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("tool call failed: %w", err)
+		return nil, err
 	}
 
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": result,
-				},
-			},
-		},
-		"id": id,
+	return &mcpsdk.CallToolResult{
+		Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: string(data)}},
+		StructuredContent: structured,
 	}, nil
 }
-
-// handleToolsCall handles the tools/call request (stdio mode)
-func (s *Server) handleToolsCall(request map[string]interface{}, encoder *json.Encoder, id interface{}) error {
-	response, err := s.buildToolsCallResponse(request, id)
-	if err != nil {
-		return err
-	}
-	return encoder.Encode(response)
-}
-
-// buildErrorResponse builds an error response
-func (s *Server) buildErrorResponse(id interface{}, err error) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"error": map[string]interface{}{
-			"code":    -32603,
-			"message": err.Error(),
-		},
-		"id": id,
-	}
-}
-
-// sendError sends an error response (stdio mode)
-func (s *Server) sendError(encoder *json.Encoder, request map[string]interface{}, err error) {
-	id, _ := request["id"]
-	response := s.buildErrorResponse(id, err)
-	encoder.Encode(response)
-}
-
-
@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// snapshotIDPattern matches the IDs of saved snapshots, which name their files
+var snapshotIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// snapshotList is a list as recorded in a board snapshot
+type snapshotList struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Position float64 `json:"position"`
+	// Finished marks done, closed and archive lists; cards moved there count as completed
+	Finished bool `json:"finished,omitempty"`
+}
+
+// snapshotCard is a card as recorded in a board snapshot
+type snapshotCard struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	ListID   string     `json:"listId"`
+	Position float64    `json:"position"`
+	DueDate  *time.Time `json:"dueDate,omitempty"`
+	Labels   []string   `json:"labels,omitempty"`
+	// Description is kept so restore_board can recreate a deleted card with it
+	Description string `json:"description,omitempty"`
+}
+
+// boardSnapshot is the state of a board at one time, reduced to what change reports
+// compare: its lists and cards in board order
+type boardSnapshot struct {
+	// ID names the saved snapshot; empty if it was not saved
+	ID        string         `json:"id,omitempty"`
+	BoardID   string         `json:"boardId"`
+	BoardName string         `json:"boardName"`
+	TakenAt   time.Time      `json:"takenAt"`
+	Lists     []snapshotList `json:"lists"`
+	Cards     []snapshotCard `json:"cards"`
+}
+
+// cardMove is a card that changed lists between two snapshots
+type cardMove struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	FromListID   string `json:"fromListId"`
+	FromListName string `json:"fromListName"`
+	ToListID     string `json:"toListId"`
+	ToListName   string `json:"toListName"`
+}
+
+// snapshotDiff is the result of diff_board_snapshots
+type snapshotDiff struct {
+	BoardID string         `json:"boardId"`
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Added   []snapshotCard `json:"added"`
+	Removed []snapshotCard `json:"removed"`
+	Moved   []cardMove     `json:"moved"`
+	// Completed are the cards that reached a finished list, by a move or added there
+	Completed    []snapshotCard `json:"completed"`
+	ListsAdded   []snapshotList `json:"listsAdded"`
+	ListsRemoved []snapshotList `json:"listsRemoved"`
+}
+
+// SetSnapshotsDir sets the directory snapshot_board saves snapshots in and
+// diff_board_snapshots reads them from
+func (s *Server) SetSnapshotsDir(dir string) {
+	s.snapshotsDir = dir
+}
+
+func (s *Server) handleSnapshotBoard(ctx context.Context, args snapshotBoardArgs) (interface{}, error) {
+	if args.Save && s.snapshotsDir == "" {
+		return nil, invalidParams("save requires the server to run with --snapshots-dir")
+	}
+	snapshot, err := s.takeSnapshot(ctx, s.clientFor(ctx), args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Save {
+		snapshot.ID = fmt.Sprintf("%s-%s", snapshot.BoardID, snapshot.TakenAt.Format("20060102T150405Z"))
+		err := os.MkdirAll(s.snapshotsDir, 0o755)
+		if err == nil {
+			err = writeStateFile(filepath.Join(s.snapshotsDir, snapshot.ID+".json"), snapshot)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save snapshot: %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *Server) handleDiffBoardSnapshots(ctx context.Context, args diffBoardSnapshotsArgs) (interface{}, error) {
+	from, err := s.argumentSnapshot("from", "fromSnapshot", args.From, args.FromSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, invalidParams("from or fromSnapshot is required")
+	}
+	to, err := s.argumentSnapshot("to", "toSnapshot", args.To, args.ToSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		// Without a later snapshot the board is compared as it is now
+		if to, err = s.takeSnapshot(ctx, s.clientFor(ctx), from.BoardID); err != nil {
+			return nil, err
+		}
+	}
+	if from.BoardID != to.BoardID {
+		return nil, invalidParams("the snapshots are of different boards: %s and %s", from.BoardID, to.BoardID)
+	}
+	return diffSnapshots(*from, *to), nil
+}
+
+// argumentSnapshot returns the snapshot given to a tool as the ID of a saved snapshot in
+// the argument name or inline in the argument inlineName, or nil if neither was given
+func (s *Server) argumentSnapshot(name, inlineName, id string, inline *boardSnapshot) (*boardSnapshot, error) {
+	switch {
+	case id != "" && inline != nil:
+		return nil, invalidParams("give %s or %s, not both", name, inlineName)
+	case inline != nil:
+		return inline, nil
+	case id == "":
+		return nil, nil
+	case s.snapshotsDir == "":
+		return nil, invalidParams("%s: saved snapshots require the server to run with --snapshots-dir", name)
+	case !snapshotIDPattern.MatchString(id):
+		return nil, invalidParams("%s: invalid snapshot ID %q", name, id)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.snapshotsDir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, invalidParams("%s: no snapshot with ID %q", name, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot boardSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", id, err)
+	}
+	return &snapshot, nil
+}
+
+// takeSnapshot records the current state of a board
+func (s *Server) takeSnapshot(ctx context.Context, client PlankaClient, boardID string) (*boardSnapshot, error) {
+	board, err := client.GetBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := client.GetLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	cardLabels, err := client.GetCardLabels(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildSnapshot(board, lists, cards, labels, cardLabels, time.Now().UTC()), nil
+}
+
+// buildSnapshot lays out a board as a snapshot taken at takenAt, with lists and cards in
+// board order and label names sorted, so equal boards give equal snapshots
+func (s *Server) buildSnapshot(board *planka.Board, lists []planka.List, cards []planka.Card, labels []planka.Label, cardLabels []planka.CardLabel, takenAt time.Time) *boardSnapshot {
+	snapshot := &boardSnapshot{
+		BoardID:   board.ID,
+		BoardName: board.Name,
+		TakenAt:   takenAt,
+		Lists:     []snapshotList{},
+		Cards:     []snapshotCard{},
+	}
+	listPositions := map[string]float64{}
+	for _, list := range lists {
+		listPositions[list.ID] = list.Position
+		snapshot.Lists = append(snapshot.Lists, snapshotList{ID: list.ID, Name: list.Name, Position: list.Position, Finished: s.isFinishedList(list)})
+	}
+	sort.SliceStable(snapshot.Lists, func(i, j int) bool { return snapshot.Lists[i].Position < snapshot.Lists[j].Position })
+
+	labelNames := map[string]string{}
+	for _, label := range labels {
+		labelNames[label.ID] = label.Name
+	}
+	cardLabelNames := map[string][]string{}
+	for _, cardLabel := range cardLabels {
+		if name, ok := labelNames[cardLabel.LabelID]; ok {
+			cardLabelNames[cardLabel.CardID] = append(cardLabelNames[cardLabel.CardID], name)
+		}
+	}
+	for _, card := range cards {
+		names := cardLabelNames[card.ID]
+		sort.Strings(names)
+		snapshot.Cards = append(snapshot.Cards, snapshotCard{
+			ID:          card.ID,
+			Name:        card.Name,
+			ListID:      card.ListID,
+			Position:    card.Position,
+			DueDate:     card.DueDate,
+			Labels:      names,
+			Description: card.Description,
+		})
+	}
+	sort.SliceStable(snapshot.Cards, func(i, j int) bool {
+		a, b := snapshot.Cards[i], snapshot.Cards[j]
+		if a.ListID != b.ListID {
+			return listPositions[a.ListID] < listPositions[b.ListID]
+		}
+		return a.Position < b.Position
+	})
+	return snapshot
+}
+
+// diffSnapshots reports the cards and lists that changed from one snapshot of a board to a
+// later one
+func diffSnapshots(from, to boardSnapshot) snapshotDiff {
+	diff := snapshotDiff{
+		BoardID:      to.BoardID,
+		From:         from.TakenAt,
+		To:           to.TakenAt,
+		Added:        []snapshotCard{},
+		Removed:      []snapshotCard{},
+		Moved:        []cardMove{},
+		Completed:    []snapshotCard{},
+		ListsAdded:   []snapshotList{},
+		ListsRemoved: []snapshotList{},
+	}
+
+	lists := map[string]snapshotList{}
+	oldLists := map[string]bool{}
+	for _, list := range from.Lists {
+		lists[list.ID] = list
+		oldLists[list.ID] = true
+	}
+	newLists := map[string]bool{}
+	for _, list := range to.Lists {
+		lists[list.ID] = list
+		newLists[list.ID] = true
+		if !oldLists[list.ID] {
+			diff.ListsAdded = append(diff.ListsAdded, list)
+		}
+	}
+	for _, list := range from.Lists {
+		if !newLists[list.ID] {
+			diff.ListsRemoved = append(diff.ListsRemoved, list)
+		}
+	}
+
+	oldCards := map[string]snapshotCard{}
+	for _, card := range from.Cards {
+		oldCards[card.ID] = card
+	}
+	newCards := map[string]bool{}
+	for _, card := range to.Cards {
+		newCards[card.ID] = true
+		old, existed := oldCards[card.ID]
+		switch {
+		case !existed:
+			diff.Added = append(diff.Added, card)
+		case old.ListID != card.ListID:
+			diff.Moved = append(diff.Moved, cardMove{
+				ID:           card.ID,
+				Name:         card.Name,
+				FromListID:   old.ListID,
+				FromListName: lists[old.ListID].Name,
+				ToListID:     card.ListID,
+				ToListName:   lists[card.ListID].Name,
+			})
+		}
+		if lists[card.ListID].Finished && (!existed || !lists[old.ListID].Finished) {
+			diff.Completed = append(diff.Completed, card)
+		}
+	}
+	for _, card := range from.Cards {
+		if !newCards[card.ID] {
+			diff.Removed = append(diff.Removed, card)
+		}
+	}
+	return diff
+}
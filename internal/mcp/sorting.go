@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sort orders of list tools
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+// sortItems returns items sorted by their JSON field sortBy in order (ascending unless
+// desc), leaving items as they are if sortBy is empty. Equal items keep their order, and
+// items without a value, e.g. cards without a due date, come last in either order.
+func sortItems[T any](items []T, sortBy, order string) ([]T, error) {
+	if sortBy == "" {
+		return items, nil
+	}
+	t := reflect.TypeOf(items).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, invalidArgument("sortBy", "these items cannot be sorted")
+	}
+	index, ok := fieldIndex(t, sortBy)
+	if !ok || !sortable(t.FieldByIndex(index).Type) {
+		var fields []string
+		for _, name := range fieldOrder(t) {
+			if index, ok := fieldIndex(t, name); ok && sortable(t.FieldByIndex(index).Type) {
+				fields = append(fields, name)
+			}
+		}
+		return nil, invalidArgument("sortBy", "cannot sort by %q; sortBy must be one of %s", sortBy, strings.Join(fields, ", "))
+	}
+
+	// The items may be shared with a cache, so a copy is sorted
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		x := reflect.ValueOf(a).FieldByIndex(index)
+		y := reflect.ValueOf(b).FieldByIndex(index)
+		if x.Kind() == reflect.Ptr || y.Kind() == reflect.Ptr {
+			switch {
+			case x.IsNil() && y.IsNil():
+				return 0
+			case x.IsNil():
+				return 1
+			case y.IsNil():
+				return -1
+			}
+			x, y = x.Elem(), y.Elem()
+		}
+		c := compareValues(x, y)
+		if order == sortDescending {
+			return -c
+		}
+		return c
+	})
+	return sorted, nil
+}
+
+// fieldIndex returns the index of the field of struct type t named name in JSON,
+// looking into embedded structs like encoding/json does
+func fieldIndex(t reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			if index, ok := fieldIndex(field.Type, name); ok {
+				return append([]int{i}, index...), true
+			}
+			continue
+		}
+		if json, _ := jsonFieldName(field); field.IsExported() && json == name {
+			return []int{i}, true
+		}
+	}
+	return nil, false
+}
+
+// sortable reports whether values of type t can be compared by compareValues
+func sortable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// compareValues compares two values of a sortable type; text is compared ignoring case
+func compareValues(x, y reflect.Value) int {
+	if x.Type() == timeType {
+		return x.Interface().(time.Time).Compare(y.Interface().(time.Time))
+	}
+	switch x.Kind() {
+	case reflect.String:
+		return cmp.Compare(strings.ToLower(x.String()), strings.ToLower(y.String()))
+	case reflect.Bool:
+		switch {
+		case x.Bool() == y.Bool():
+			return 0
+		case x.Bool():
+			return 1
+		}
+		return -1
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(x.Float(), y.Float())
+	}
+	return cmp.Compare(x.Int(), y.Int())
+}
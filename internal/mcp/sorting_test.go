@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestSortItems(t *testing.T) {
+	day := func(d int) *time.Time {
+		due := time.Date(2025, 3, d, 17, 0, 0, 0, time.UTC)
+		return &due
+	}
+	cards := []planka.Card{
+		{ID: "1", Name: "b", DueDate: day(3)},
+		{ID: "2", Name: "C"},
+		{ID: "3", Name: "a", DueDate: day(1)},
+		{ID: "4", Name: "d", DueDate: day(3)},
+	}
+
+	tests := []struct {
+		sortBy, order string
+		want          string
+	}{
+		{"", "", "[1 2 3 4]"},
+		{"name", "", "[3 1 2 4]"},
+		{"name", "desc", "[4 2 1 3]"},
+		// Cards without a due date come last in either order, and ties keep their order
+		{"dueDate", "asc", "[3 1 4 2]"},
+		{"dueDate", "desc", "[1 4 3 2]"},
+	}
+	for _, tt := range tests {
+		sorted, err := sortItems(cards, tt.sortBy, tt.order)
+		if err != nil {
+			t.Fatalf("sortItems(%s %s): %v", tt.sortBy, tt.order, err)
+		}
+		var ids []string
+		for _, card := range sorted {
+			ids = append(ids, card.ID)
+		}
+		if got := fmt.Sprint(ids); got != tt.want {
+			t.Errorf("sortItems(%s %s) = %s, want %s", tt.sortBy, tt.order, got, tt.want)
+		}
+	}
+	if cards[0].ID != "1" || cards[2].ID != "3" {
+		t.Errorf("sortItems changed the items passed in")
+	}
+}
@@ -0,0 +1,268 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// sortStrategies are the orders auto_sort_list can put cards in
+var sortStrategies = []string{"dueDate", "name", "createdAt"}
+
+// sortedCard is a card in the order auto_sort_list put it in
+type sortedCard struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Position float64 `json:"position"`
+}
+
+// sortResult is the result of auto_sort_list
+type sortResult struct {
+	ListID   string       `json:"listId"`
+	Strategy string       `json:"strategy"`
+	Cards    []sortedCard `json:"cards"`
+	// Updated counts the cards whose position changed
+	Updated int `json:"updated"`
+	// KeptSorted tells whether the list is re-sorted in the background
+	KeptSorted bool `json:"keptSorted"`
+}
+
+// sortedList is a list the background sorter keeps in order
+type sortedList struct {
+	ListID   string `json:"listId"`
+	Strategy string `json:"strategy"`
+	// Instance is the Planka instance the list belongs to; empty for the default one
+	Instance  string    `json:"instance,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// sortedListFile is the layout of the sorted lists state file
+type sortedListFile struct {
+	Lists []sortedList `json:"lists"`
+}
+
+// sortedListStore keeps the lists kept sorted and persists every change to its file
+type sortedListStore struct {
+	path string
+
+	mu    sync.Mutex
+	lists []sortedList
+}
+
+// loadSortedLists reads the sorted lists stored at path; a missing file holds none
+func loadSortedLists(path string) (*sortedListStore, error) {
+	store := &sortedListStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file sortedListFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid sorted lists file %s: %w", path, err)
+	}
+	for _, list := range file.Lists {
+		if !slices.Contains(sortStrategies, list.Strategy) {
+			return nil, fmt.Errorf("sorted list %s: unknown strategy %q", list.ListID, list.Strategy)
+		}
+	}
+	store.lists = file.Lists
+	return store, nil
+}
+
+// set keeps a list sorted by its strategy, replacing the strategy it was kept sorted by
+func (l *sortedListStore) set(list sortedList) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lists := []sortedList{}
+	for _, existing := range l.lists {
+		if existing.Instance != list.Instance || existing.ListID != list.ListID {
+			lists = append(lists, existing)
+		}
+	}
+	lists = append(lists, list)
+	if err := writeStateFile(l.path, sortedListFile{Lists: lists}); err != nil {
+		return fmt.Errorf("failed to save sorted lists: %w", err)
+	}
+	l.lists = lists
+	return nil
+}
+
+// remove stops keeping a list sorted
+func (l *sortedListStore) remove(instance, listID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lists := []sortedList{}
+	for _, existing := range l.lists {
+		if existing.Instance != instance || existing.ListID != listID {
+			lists = append(lists, existing)
+		}
+	}
+	if len(lists) == len(l.lists) {
+		return nil
+	}
+	if err := writeStateFile(l.path, sortedListFile{Lists: lists}); err != nil {
+		return fmt.Errorf("failed to save sorted lists: %w", err)
+	}
+	l.lists = lists
+	return nil
+}
+
+// all returns a copy of the lists kept sorted
+func (l *sortedListStore) all() []sortedList {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]sortedList(nil), l.lists...)
+}
+
+// kept reports whether a list is kept sorted
+func (l *sortedListStore) kept(instance, listID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, list := range l.lists {
+		if list.Instance == instance && list.ListID == listID {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableSortedLists loads the lists kept sorted stored in the state file at path, so
+// auto_sort_list can keep lists sorted. StartSortedLists runs the background sorter.
+func (s *Server) EnableSortedLists(path string) error {
+	store, err := loadSortedLists(path)
+	if err != nil {
+		return err
+	}
+	s.sortedLists = store
+	return nil
+}
+
+// StartSortedLists starts a background job that sorts the lists kept sorted every
+// interval, so cards added or changed since fall into place. It stops when ctx is
+// cancelled.
+func (s *Server) StartSortedLists(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, list := range s.sortedLists.all() {
+					client, err := s.backgroundClient(list.Instance)
+					if err == nil {
+						_, err = sortList(ctx, client, list.ListID, list.Strategy)
+					}
+					if err != nil {
+						log.Printf("Sorted list %s: %v", list.ListID, err)
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (s *Server) handleAutoSortList(ctx context.Context, args autoSortListArgs) (interface{}, error) {
+	strategy := args.Strategy
+	if strategy == "" {
+		strategy = "dueDate"
+	}
+	if !slices.Contains(sortStrategies, strategy) {
+		return nil, invalidParams("strategy must be one of: %s", strings.Join(sortStrategies, ", "))
+	}
+	if args.KeepSorted != nil && s.sortedLists == nil {
+		return nil, invalidParams("keepSorted requires the server to run with --sorted-lists-file")
+	}
+
+	client := s.clientFor(ctx)
+	result, err := sortList(ctx, client, args.ListID, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if s.sortedLists == nil {
+		return result, nil
+	}
+
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	if args.KeepSorted != nil {
+		if *args.KeepSorted {
+			err = s.sortedLists.set(sortedList{ListID: args.ListID, Strategy: strategy, Instance: instance, CreatedAt: time.Now().UTC()})
+		} else {
+			err = s.sortedLists.remove(instance, args.ListID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.KeptSorted = s.sortedLists.kept(instance, args.ListID)
+	return result, nil
+}
+
+// sortList rewrites the positions of the cards of listID so they are in the order of
+// strategy, leaving the cards already in place alone
+func sortList(ctx context.Context, client PlankaClient, listID, strategy string) (sortResult, error) {
+	cards, err := client.GetCards(ctx, listID)
+	if err != nil {
+		return sortResult{}, err
+	}
+	sortCards(cards, strategy)
+
+	result := sortResult{ListID: listID, Strategy: strategy, Cards: []sortedCard{}}
+	inOrder := true
+	for i := 1; i < len(cards); i++ {
+		if cards[i].Position <= cards[i-1].Position {
+			inOrder = false
+			break
+		}
+	}
+	for i, card := range cards {
+		position := card.Position
+		if !inOrder {
+			position = float64(i+1) * planka.PositionGap
+		}
+		if position != card.Position {
+			if _, err := client.UpdateCard(ctx, card.ID, planka.UpdateCardRequest{Position: &position}); err != nil {
+				return result, fmt.Errorf("failed to move card %s after sorting %d cards: %w", card.ID, result.Updated, err)
+			}
+			result.Updated++
+		}
+		result.Cards = append(result.Cards, sortedCard{ID: card.ID, Name: card.Name, Position: position})
+	}
+	return result, nil
+}
+
+// sortCards orders cards by strategy, keeping the current order of cards that tie
+func sortCards(cards []planka.Card, strategy string) {
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Position < cards[j].Position })
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := cards[i], cards[j]
+		switch strategy {
+		case "name":
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case "createdAt":
+			return a.CreatedAt.Before(b.CreatedAt)
+		default:
+			// Cards without a due date go last
+			if a.DueDate == nil || b.DueDate == nil {
+				return a.DueDate != nil && b.DueDate == nil
+			}
+			return a.DueDate.Before(*b.DueDate)
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// noLabel is the label breakdown entry of cards without labels
+const noLabel = "(no label)"
+
+// sprintCard is a card counted in a sprint report
+type sprintCard struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	ListName string   `json:"listName"`
+	Labels   []string `json:"labels,omitempty"`
+	// TrackedSeconds is the time on the card's stopwatch
+	TrackedSeconds int64 `json:"trackedSeconds,omitempty"`
+}
+
+// labelCount is how many completed and carried-over cards have a label
+type labelCount struct {
+	Label       string `json:"label"`
+	Completed   int    `json:"completed"`
+	CarriedOver int    `json:"carriedOver"`
+}
+
+// sprintReport is the result of sprint_report
+type sprintReport struct {
+	BoardID     string       `json:"boardId"`
+	BoardName   string       `json:"boardName"`
+	From        time.Time    `json:"from"`
+	To          time.Time    `json:"to"`
+	Completed   []sprintCard `json:"completed"`
+	CarriedOver []sprintCard `json:"carriedOver"`
+	// TrackedSeconds totals the stopwatches of the completed and carried-over cards
+	TrackedSeconds int64  `json:"trackedSeconds"`
+	Tracked        string `json:"tracked"`
+	// CompletedTasks counts the tasks of these cards completed during the sprint
+	CompletedTasks int          `json:"completedTasks"`
+	Labels         []labelCount `json:"labels"`
+	// Archived reports the done cards moved to the archive list, if requested
+	Archived *cleanupResult `json:"archived,omitempty"`
+	// Errors describes cards whose tracked time or tasks could not be read
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (s *Server) handleSprintReport(ctx context.Context, args sprintReportArgs) (interface{}, error) {
+	if err := args.formatArgs.validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now().In(s.location())
+	from, err := parseReportDate(args.From, now.Location(), false)
+	if err != nil {
+		return nil, invalidParams("from: %v", err)
+	}
+	to := now
+	if args.To != "" {
+		if to, err = parseReportDate(args.To, now.Location(), true); err != nil {
+			return nil, invalidParams("to: %v", err)
+		}
+	}
+	if !from.Before(to) {
+		return nil, invalidParams("from must be before to")
+	}
+
+	client := s.clientFor(ctx)
+	board, err := client.GetBoard(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := client.GetLabels(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cardLabels, err := client.GetCardLabels(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint := s.sprintReport(board, lists, cards, labels, cardLabels, args.InProgressLists, from, to)
+	sprint.trackTime(ctx, client, now)
+	sprint.countTasks(ctx, client)
+
+	if args.ArchiveDone {
+		var doneLists []string
+		for _, list := range lists {
+			if s.isFinishedList(list) && list.Type != "archive" && list.Type != "trash" && !strings.EqualFold(list.Name, archiveListName) {
+				doneLists = append(doneLists, list.ID)
+			}
+		}
+		archived := s.cleanupDoneCards(ctx, client, doneLists, time.Now(), false, false)
+		sprint.Archived = &archived
+	}
+	if err := s.recordSprint(ctx, sprint); err != nil {
+		sprint.Errors = append(sprint.Errors, err.Error())
+	}
+	return formatReport(args.Format, sprint, sprint.document(client)), nil
+}
+
+// sprintReport sorts the cards of board into those completed between from and to (moved
+// to a finished list then) and those carried over: open cards in in-progress lists or
+// updated during the sprint. Tracked time is added by trackTime.
+func (s *Server) sprintReport(board *planka.Board, lists []planka.List, cards []planka.Card, labels []planka.Label, cardLabels []planka.CardLabel, inProgressLists []string, from, to time.Time) sprintReport {
+	report := sprintReport{
+		BoardID:     board.ID,
+		BoardName:   board.Name,
+		From:        from,
+		To:          to,
+		Completed:   []sprintCard{},
+		CarriedOver: []sprintCard{},
+		Labels:      []labelCount{},
+	}
+
+	listNames := map[string]string{}
+	finished := map[string]bool{}
+	inProgress := map[string]bool{}
+	trash := map[string]bool{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+		finished[list.ID] = s.isFinishedList(list)
+		trash[list.ID] = list.Type == "trash"
+		if len(inProgressLists) > 0 {
+			inProgress[list.ID] = slices.Contains(inProgressLists, list.ID)
+		} else {
+			inProgress[list.ID] = isInProgressList(list.Name)
+		}
+	}
+	labelNames := map[string]string{}
+	for _, label := range labels {
+		labelNames[label.ID] = label.Name
+	}
+	cardLabelNames := map[string][]string{}
+	for _, cardLabel := range cardLabels {
+		if name, ok := labelNames[cardLabel.LabelID]; ok {
+			cardLabelNames[cardLabel.CardID] = append(cardLabelNames[cardLabel.CardID], name)
+		}
+	}
+
+	counts := map[string]*labelCount{}
+	count := func(card sprintCard, completed bool) {
+		names := card.Labels
+		if len(names) == 0 {
+			names = []string{noLabel}
+		}
+		for _, name := range names {
+			c, ok := counts[name]
+			if !ok {
+				c = &labelCount{Label: name}
+				counts[name] = c
+			}
+			if completed {
+				c.Completed++
+			} else {
+				c.CarriedOver++
+			}
+		}
+	}
+
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Position < cards[j].Position })
+	for _, card := range cards {
+		if trash[card.ListID] || card.CreatedAt.After(to) {
+			continue
+		}
+		entry := sprintCard{ID: card.ID, Name: card.Name, ListName: listNames[card.ListID], Labels: cardLabelNames[card.ID]}
+		// Planka 1 does not record list moves, so the last update stands in for them
+		moved := card.UpdatedAt
+		if card.ListChangedAt != nil {
+			moved = *card.ListChangedAt
+		}
+		switch {
+		case finished[card.ListID]:
+			if moved.Before(from) || moved.After(to) {
+				continue
+			}
+			report.Completed = append(report.Completed, entry)
+			count(entry, true)
+		case inProgress[card.ListID] || (!card.UpdatedAt.Before(from) && !card.UpdatedAt.After(to)):
+			report.CarriedOver = append(report.CarriedOver, entry)
+			count(entry, false)
+		}
+	}
+
+	for _, c := range counts {
+		report.Labels = append(report.Labels, *c)
+	}
+	sort.Slice(report.Labels, func(i, j int) bool {
+		a, b := report.Labels[i], report.Labels[j]
+		if a.Completed+a.CarriedOver != b.Completed+b.CarriedOver {
+			return a.Completed+a.CarriedOver > b.Completed+b.CarriedOver
+		}
+		return a.Label < b.Label
+	})
+	return report
+}
+
+// trackTime reads the stopwatch of every card in the report, counting running ones up to now
+func (r *sprintReport) trackTime(ctx context.Context, client PlankaClient, now time.Time) {
+	for _, cards := range [][]sprintCard{r.Completed, r.CarriedOver} {
+		for i := range cards {
+			stopwatch, err := client.GetStopwatch(ctx, cards[i].ID)
+			if err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("card %s: %v", cards[i].ID, err))
+				continue
+			}
+			cards[i].TrackedSeconds = stopwatch.Duration
+			if stopwatch.StartedAt != nil {
+				cards[i].TrackedSeconds += int64(now.Sub(*stopwatch.StartedAt).Seconds())
+			}
+			r.TrackedSeconds += cards[i].TrackedSeconds
+		}
+	}
+	r.Tracked = formatTracked(r.TrackedSeconds)
+}
+
+// countTasks counts the tasks of the cards in the report completed during the sprint,
+// going by when the task was last updated
+func (r *sprintReport) countTasks(ctx context.Context, client PlankaClient) {
+	for _, cards := range [][]sprintCard{r.Completed, r.CarriedOver} {
+		for _, card := range cards {
+			tasks, err := client.GetTasks(ctx, card.ID)
+			if err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("card %s: %v", card.ID, err))
+				continue
+			}
+			for _, task := range tasks {
+				if task.IsCompleted && !task.UpdatedAt.Before(r.From) && !task.UpdatedAt.After(r.To) {
+					r.CompletedTasks++
+				}
+			}
+		}
+	}
+}
+
+// parseReportDate parses an RFC 3339 timestamp or a date such as 2024-05-31 in loc. A
+// date alone means its start, or with end its end, so a range of dates includes both.
+func parseReportDate(text string, loc *time.Location, end bool) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, nil
+	}
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			if layout == "2006-01-02" && end {
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot understand date %q; use a date such as 2024-05-31 or an RFC 3339 timestamp", text)
+}
+
+// formatTracked formats seconds of tracked time as hours and minutes, e.g. "12h 05m"
+func formatTracked(seconds int64) string {
+	return fmt.Sprintf("%dh %02dm", seconds/3600, seconds%3600/60)
+}
+
+// document lays out the sprint report for the markdown and slack formats
+func (r sprintReport) document(client PlankaClient) report {
+	doc := report{
+		Title: fmt.Sprintf("Sprint report: %s", r.BoardName),
+		Summary: fmt.Sprintf("%s to %s: %d completed, %d carried over, %d tasks done, %s tracked",
+			r.From.Format("Jan 2"), r.To.Format("Jan 2"), len(r.Completed), len(r.CarriedOver), r.CompletedTasks, r.Tracked),
+		Empty: "No cards were worked on.",
+	}
+	cardItems := func(cards []sprintCard) []reportItem {
+		items := make([]reportItem, len(cards))
+		for i, card := range cards {
+			details := []string{}
+			if len(card.Labels) > 0 {
+				details = append(details, strings.Join(card.Labels, ", "))
+			}
+			if card.TrackedSeconds > 0 {
+				details = append(details, formatTracked(card.TrackedSeconds))
+			}
+			items[i] = reportItem{Text: card.Name, URL: cardURL(client, card.ID), Detail: strings.Join(details, "; ")}
+		}
+		return items
+	}
+	labels := make([]reportItem, len(r.Labels))
+	for i, label := range r.Labels {
+		labels[i] = reportItem{Text: label.Label, Detail: fmt.Sprintf("%d completed, %d carried over", label.Completed, label.CarriedOver)}
+	}
+	doc.Sections = []reportSection{
+		{Heading: "Completed", Items: cardItems(r.Completed)},
+		{Heading: "Carried over", Items: cardItems(r.CarriedOver)},
+		{Heading: "Labels", Items: labels},
+	}
+	if r.Archived != nil {
+		doc.Summary += fmt.Sprintf("; %s archived", plural(len(r.Archived.Cards), "done card", "done cards"))
+	}
+	return doc
+}
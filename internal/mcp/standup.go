@@ -0,0 +1,259 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// defaultLookbackHours is the window standup_report covers by default
+const defaultLookbackHours = 24
+
+// inProgressWords mark the lists whose cards are being worked on, e.g. "In Progress" or "Doing"
+var inProgressWords = []string{"progress", "doing", "review", "wip", "active", "started"}
+
+// standupCard is a card listed in a standup report
+type standupCard struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ListName string `json:"listName"`
+	// Reason says why a card is a blocker
+	Reason string `json:"reason,omitempty"`
+}
+
+// standupMember is what one member, or nobody for unassigned cards, worked on
+type standupMember struct {
+	// UserID is empty for the cards nobody is assigned to
+	UserID     string        `json:"userId,omitempty"`
+	Name       string        `json:"name"`
+	Done       []standupCard `json:"done"`
+	InProgress []standupCard `json:"inProgress"`
+	New        []standupCard `json:"new"`
+	Blockers   []standupCard `json:"blockers"`
+}
+
+// standupReport is the result of standup_report
+type standupReport struct {
+	BoardID   string          `json:"boardId"`
+	BoardName string          `json:"boardName"`
+	Since     time.Time       `json:"since"`
+	Members   []standupMember `json:"members"`
+}
+
+func (s *Server) handleStandupReport(ctx context.Context, args standupReportArgs) (interface{}, error) {
+	if err := args.formatArgs.validate(); err != nil {
+		return nil, err
+	}
+	hours := defaultLookbackHours
+	if args.LookbackHours != nil {
+		if *args.LookbackHours < 1 {
+			return nil, invalidParams("lookbackHours must be at least 1")
+		}
+		hours = *args.LookbackHours
+	}
+
+	client := s.clientFor(ctx)
+	board, err := client.GetBoard(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := client.GetCardMemberships(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := client.GetBoardUsers(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := client.GetLabels(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cardLabels, err := client.GetCardLabels(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	standup := s.standupReport(board, lists, cards, memberships, users, blockedCards(labels, cardLabels), args.InProgressLists, now.Add(-time.Duration(hours)*time.Hour), now)
+	return formatReport(args.Format, standup, standup.document(client, s.location())), nil
+}
+
+// standupReport sorts the cards of board by what happened to them since: moved to a
+// finished list, in an in-progress list, created, or blocked. Cards appear under each of
+// their members, and under an "Unassigned" member if they have none.
+func (s *Server) standupReport(board *planka.Board, lists []planka.List, cards []planka.Card, memberships []planka.CardMembership, users []planka.User, blocked map[string]string, inProgressLists []string, since, now time.Time) standupReport {
+	listNames := map[string]string{}
+	finished := map[string]bool{}
+	inProgress := map[string]bool{}
+	trash := map[string]bool{}
+	for _, list := range lists {
+		trash[list.ID] = list.Type == "trash"
+		listNames[list.ID] = list.Name
+		finished[list.ID] = s.isFinishedList(list)
+		if len(inProgressLists) > 0 {
+			inProgress[list.ID] = slices.Contains(inProgressLists, list.ID)
+		} else {
+			inProgress[list.ID] = isInProgressList(list.Name)
+		}
+	}
+	userNames := map[string]string{}
+	for _, user := range users {
+		userNames[user.ID] = user.Name
+		if user.Name == "" {
+			userNames[user.ID] = user.Username
+		}
+	}
+	assignees := map[string][]string{}
+	for _, membership := range memberships {
+		assignees[membership.CardID] = append(assignees[membership.CardID], membership.UserID)
+	}
+
+	members := map[string]*standupMember{}
+	member := func(userID string) *standupMember {
+		if m, ok := members[userID]; ok {
+			return m
+		}
+		m := &standupMember{UserID: userID, Name: userNames[userID], Done: []standupCard{}, InProgress: []standupCard{}, New: []standupCard{}, Blockers: []standupCard{}}
+		if userID == "" {
+			m.Name = "Unassigned"
+		} else if m.Name == "" {
+			m.Name = "User " + userID
+		}
+		members[userID] = m
+		return m
+	}
+
+	sort.Slice(cards, func(i, j int) bool { return cards[i].UpdatedAt.After(cards[j].UpdatedAt) })
+	for _, card := range cards {
+		if trash[card.ListID] {
+			continue
+		}
+		entry := standupCard{ID: card.ID, Name: card.Name, ListName: listNames[card.ListID]}
+		owners := assignees[card.ID]
+		if len(owners) == 0 {
+			owners = []string{""}
+		}
+		// Planka 1 does not record list moves, so the last update stands in for them
+		moved := card.UpdatedAt
+		if card.ListChangedAt != nil {
+			moved = *card.ListChangedAt
+		}
+		reason := blocked[card.ID]
+		if reason == "" && card.DueDate != nil && card.DueDate.Before(now) {
+			reason = "overdue since " + card.DueDate.In(s.location()).Format("Jan 2")
+		}
+
+		for _, owner := range owners {
+			m := member(owner)
+			switch {
+			case finished[card.ListID]:
+				if !moved.Before(since) {
+					m.Done = append(m.Done, entry)
+				}
+				continue
+			case inProgress[card.ListID]:
+				m.InProgress = append(m.InProgress, entry)
+			}
+			if !card.CreatedAt.Before(since) {
+				m.New = append(m.New, entry)
+			}
+			if reason != "" {
+				blocker := entry
+				blocker.Reason = reason
+				m.Blockers = append(m.Blockers, blocker)
+			}
+		}
+	}
+
+	report := standupReport{BoardID: board.ID, BoardName: board.Name, Since: since.UTC(), Members: []standupMember{}}
+	for _, m := range members {
+		if len(m.Done)+len(m.InProgress)+len(m.New)+len(m.Blockers) > 0 {
+			report.Members = append(report.Members, *m)
+		}
+	}
+	sort.Slice(report.Members, func(i, j int) bool {
+		// Unassigned cards come last
+		if (report.Members[i].UserID == "") != (report.Members[j].UserID == "") {
+			return report.Members[j].UserID == ""
+		}
+		return strings.ToLower(report.Members[i].Name) < strings.ToLower(report.Members[j].Name)
+	})
+	return report
+}
+
+// blockedCards returns the cards carrying a label named like "Blocked" or "Blocker",
+// with the label name as the reason
+func blockedCards(labels []planka.Label, cardLabels []planka.CardLabel) map[string]string {
+	blocking := map[string]string{}
+	for _, label := range labels {
+		if strings.Contains(strings.ToLower(label.Name), "block") {
+			blocking[label.ID] = label.Name
+		}
+	}
+	blocked := map[string]string{}
+	for _, cardLabel := range cardLabels {
+		if name, ok := blocking[cardLabel.LabelID]; ok {
+			blocked[cardLabel.CardID] = "labeled " + name
+		}
+	}
+	return blocked
+}
+
+// isInProgressList reports whether a list name says its cards are being worked on
+func isInProgressList(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range inProgressWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// document lays out the standup for the markdown and slack formats, a section per member
+func (r standupReport) document(client PlankaClient, loc *time.Location) report {
+	doc := report{
+		Title:   "Standup: " + r.BoardName,
+		Summary: fmt.Sprintf("Since %s", r.Since.In(loc).Format("Mon Jan 2 15:04 MST")),
+		Empty:   "Nothing happened on the board.",
+	}
+	for _, m := range r.Members {
+		// A card that is e.g. both new and in progress gets one line listing both
+		section := reportSection{Heading: m.Name}
+		lines := map[string]int{}
+		add := func(cards []standupCard, status string) {
+			for _, card := range cards {
+				detail := status
+				if card.Reason != "" {
+					detail = "blocked: " + card.Reason
+				}
+				if i, ok := lines[card.ID]; ok {
+					section.Items[i].Detail += ", " + detail
+					continue
+				}
+				lines[card.ID] = len(section.Items)
+				section.Items = append(section.Items, reportItem{Text: card.Name, URL: cardURL(client, card.ID), Detail: detail})
+			}
+		}
+		add(m.Done, "done")
+		add(m.InProgress, "in progress")
+		add(m.New, "new")
+		add(m.Blockers, "")
+		doc.Sections = append(doc.Sections, section)
+	}
+	return doc
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/version"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// serverStats is the result of get_server_stats
+type serverStats struct {
+	Version       string                 `json:"version"`
+	UptimeSeconds float64                `json:"uptimeSeconds"`
+	Sessions      int                    `json:"sessions"`
+	Planka        []planka.EndpointStats `json:"planka"`
+}
+
+// SetMetrics reports the requests the Planka clients recorded in metrics through the
+// get_server_stats tool and, in HTTP mode, the /metrics endpoint
+func (s *Server) SetMetrics(metrics *planka.Metrics) {
+	s.metrics = metrics
+}
+
+func (s *Server) handleGetServerStats(ctx context.Context, args getServerStatsArgs) (interface{}, error) {
+	sessions := 0
+	for range s.server.Sessions() {
+		sessions++
+	}
+	stats := serverStats{
+		Version:       version.Version,
+		UptimeSeconds: time.Since(s.started).Seconds(),
+		Sessions:      sessions,
+		Planka:        s.metrics.Snapshot(),
+	}
+	if stats.Planka == nil {
+		stats.Planka = []planka.EndpointStats{}
+	}
+	return stats, nil
+}
+
+// handleMetrics serves the Planka client metrics in the Prometheus text format
+func (h *httpServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	h.server.metrics.WritePrometheus(w)
+}
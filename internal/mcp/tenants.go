@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// plankaTokenHeader carries the caller's own Planka token in multi-tenant mode
+	plankaTokenHeader = "X-Planka-Token"
+	// tenantIdleTimeout is how long an unused per-token client is kept before it is dropped
+	tenantIdleTimeout = 30 * time.Minute
+)
+
+// MultiTenantOptions configures per-request Planka credentials
+type MultiTenantOptions struct {
+	// BaseURL is the Planka server the callers' tokens belong to
+	BaseURL string
+	// Passthrough also accepts the Planka token as the request's Authorization bearer token.
+	// It cannot be combined with API keys or OAuth, which use the same header.
+	Passthrough bool
+	// ClientOptions configure the Planka client created for each token
+	ClientOptions []planka.Option
+}
+
+// plankaTokenKey is the context key of the caller's Planka token
+type plankaTokenKey struct{}
+
+// tenantClient is the Planka client of a single token
+type tenantClient struct {
+	client   *planka.Client
+	lastSeen time.Time
+}
+
+// clientPool hands out one Planka client per token
+type clientPool struct {
+	baseURL string
+	opts    []planka.Option
+
+	mu      sync.Mutex
+	clients map[string]*tenantClient
+}
+
+// client returns the pooled client for token, creating it on first use
+func (p *clientPool) client(token string) *planka.Client {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Drop clients of users that went away so tokens are not kept around forever
+	for k, c := range p.clients {
+		if now.Sub(c.lastSeen) > tenantIdleTimeout {
+			delete(p.clients, k)
+		}
+	}
+
+	c, ok := p.clients[key]
+	if !ok {
+		c = &tenantClient{client: planka.NewClient(p.baseURL, token, p.opts...)}
+		p.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.client
+}
+
+// all returns the pooled clients
+func (p *clientPool) all() []*planka.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clients := make([]*planka.Client, 0, len(p.clients))
+	for _, c := range p.clients {
+		clients = append(clients, c.client)
+	}
+	return clients
+}
+
+// EnableMultiTenant makes HTTP requests act on Planka as the caller: the Planka token is
+// taken from the X-Planka-Token header of each request instead of the server's own
+// credentials, which remain in use for requests without one (e.g. over stdio).
+func (s *Server) EnableMultiTenant(opts MultiTenantOptions) {
+	s.tenants = &clientPool{
+		baseURL: opts.BaseURL,
+		opts:    opts.ClientOptions,
+		clients: map[string]*tenantClient{},
+	}
+	s.server.AddReceivingMiddleware(s.tenantMiddleware(opts))
+}
+
+// tenantMiddleware attaches the caller's Planka token to the request context.
+// Requests that need Planka are rejected when there is neither a token nor a server-wide client.
+func (s *Server) tenantMiddleware(opts MultiTenantOptions) mcpsdk.Middleware {
+	return func(next mcpsdk.MethodHandler) mcpsdk.MethodHandler {
+		return func(ctx context.Context, method string, req mcpsdk.Request) (mcpsdk.Result, error) {
+			if token := requestPlankaToken(req, opts.Passthrough); token != "" {
+				ctx = context.WithValue(ctx, plankaTokenKey{}, token)
+			} else if s.client == nil && (method == "tools/call" || method == "completion/complete") {
+				return nil, &jsonrpc.Error{
+					Code:    jsonrpc.CodeInvalidRequest,
+					Message: "missing Planka credentials: send your Planka token in the " + plankaTokenHeader + " header",
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// requestPlankaToken returns the Planka token sent with an HTTP request, if any
+func requestPlankaToken(req mcpsdk.Request, passthrough bool) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.Header == nil {
+		return ""
+	}
+	if token := strings.TrimSpace(extra.Header.Get(plankaTokenHeader)); token != "" {
+		return token
+	}
+	if passthrough {
+		if token, ok := strings.CutPrefix(extra.Header.Get("Authorization"), "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// clientFor returns the Planka client a request acts as: the instance it selected,
+// the caller's own client in multi-tenant mode, otherwise the server-wide one
+func (s *Server) clientFor(ctx context.Context) PlankaClient {
+	if client, ok := s.instanceClient(ctx); ok {
+		return client
+	}
+	if token, ok := ctx.Value(plankaTokenKey{}).(string); ok && s.tenants != nil {
+		return s.tenants.client(token)
+	}
+	return s.client
+}
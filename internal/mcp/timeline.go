@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// timelineItem is a card with a due date as a bar of a Gantt chart
+type timelineItem struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	ListID   string    `json:"listId"`
+	ListName string    `json:"listName"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	// StartSource is where Start comes from: "field" for the start field, else "createdAt"
+	StartSource string `json:"startSource"`
+	// Progress is the share of the card's tasks that are completed, from 0 to 1
+	Progress float64 `json:"progress"`
+	Done     bool    `json:"done"`
+	Overdue  bool    `json:"overdue"`
+}
+
+// timeline is the result of get_timeline
+type timeline struct {
+	BoardID   string `json:"boardId"`
+	BoardName string `json:"boardName"`
+	// Start and End span all items; both are zero without items
+	Start time.Time      `json:"start"`
+	End   time.Time      `json:"end"`
+	Items []timelineItem `json:"items"`
+	// Undated counts the cards left out for having no due date
+	Undated int `json:"undated"`
+	// Errors describes start field values that could not be read as dates
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (s *Server) handleGetTimeline(ctx context.Context, args getTimelineArgs) (interface{}, error) {
+	loc := s.location()
+	var from, to time.Time
+	var err error
+	if args.From != "" {
+		if from, err = parseReportDate(args.From, loc, false); err != nil {
+			return nil, invalidParams("from: %v", err)
+		}
+	}
+	if args.To != "" {
+		if to, err = parseReportDate(args.To, loc, true); err != nil {
+			return nil, invalidParams("to: %v", err)
+		}
+	}
+
+	client := s.clientFor(ctx)
+	board, err := client.GetBoard(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := client.GetLists(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := client.GetBoardCards(ctx, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start dates come from the values of the named custom field, if the board has it
+	starts := map[string]string{}
+	if args.StartField != "" {
+		fields, values, err := client.GetCustomFields(ctx, args.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		fieldIDs := map[string]bool{}
+		for _, field := range fields {
+			if strings.EqualFold(field.Name, args.StartField) {
+				fieldIDs[field.ID] = true
+			}
+		}
+		if len(fieldIDs) == 0 {
+			return nil, invalidParams("board %s has no custom field named %q", args.BoardID, args.StartField)
+		}
+		for _, value := range values {
+			if fieldIDs[value.CustomFieldID] && strings.TrimSpace(value.Content) != "" {
+				starts[value.CardID] = value.Content
+			}
+		}
+	}
+
+	result := s.buildTimeline(board, lists, cards, starts, from, to, time.Now())
+	for i := range result.Items {
+		tasks, err := client.GetTasks(ctx, result.Items[i].ID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", result.Items[i].ID, err))
+			continue
+		}
+		if len(tasks) > 0 {
+			completed := 0
+			for _, task := range tasks {
+				if task.IsCompleted {
+					completed++
+				}
+			}
+			result.Items[i].Progress = float64(completed) / float64(len(tasks))
+		}
+		if result.Items[i].Done {
+			result.Items[i].Progress = 1
+		}
+	}
+	return result, nil
+}
+
+// buildTimeline lays out the cards of board that have a due date, and whose bar overlaps
+// from and to when given, as bars from their start to their due date, earliest start first.
+// A start after the due date is moved to it, so no bar has a negative length.
+func (s *Server) buildTimeline(board *planka.Board, lists []planka.List, cards []planka.Card, starts map[string]string, from, to, now time.Time) timeline {
+	result := timeline{BoardID: board.ID, BoardName: board.Name, Items: []timelineItem{}}
+	listNames := map[string]string{}
+	finished := map[string]bool{}
+	trash := map[string]bool{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+		finished[list.ID] = s.isFinishedList(list)
+		trash[list.ID] = list.Type == "trash"
+	}
+
+	for _, card := range cards {
+		if trash[card.ListID] {
+			continue
+		}
+		if card.DueDate == nil {
+			result.Undated++
+			continue
+		}
+		item := timelineItem{
+			ID:          card.ID,
+			Name:        card.Name,
+			ListID:      card.ListID,
+			ListName:    listNames[card.ListID],
+			Start:       card.CreatedAt,
+			End:         *card.DueDate,
+			StartSource: "createdAt",
+			Done:        finished[card.ListID],
+		}
+		if text, ok := starts[card.ID]; ok {
+			start, err := parseReportDate(text, s.location(), false)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("card %s: start %v", card.ID, err))
+			} else {
+				item.Start, item.StartSource = start, "field"
+			}
+		}
+		if item.Start.After(item.End) {
+			item.Start = item.End
+		}
+		item.Overdue = !item.Done && item.End.Before(now)
+		if (!from.IsZero() && item.End.Before(from)) || (!to.IsZero() && item.Start.After(to)) {
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		if !result.Items[i].Start.Equal(result.Items[j].Start) {
+			return result.Items[i].Start.Before(result.Items[j].Start)
+		}
+		return result.Items[i].End.Before(result.Items[j].End)
+	})
+	for i, item := range result.Items {
+		if i == 0 || item.Start.Before(result.Start) {
+			result.Start = item.Start
+		}
+		if i == 0 || item.End.After(result.End) {
+			result.End = item.End
+		}
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// maxTimeEntry caps the time a single entry may log
+const maxTimeEntry = 24 * time.Hour
+
+// timeEntryPattern matches the ledger comment log_time posts, e.g.
+// "⏱ Logged 1h 30m on 2024-05-01: Fixed the login form"
+var timeEntryPattern = regexp.MustCompile(`(?s)^⏱ Logged (\d+)h (\d{2})m on (\d{4}-\d{2}-\d{2})(?:: (.*))?$`)
+
+// timeEntry is time logged against a card, kept as a comment on it
+type timeEntry struct {
+	CommentID string `json:"commentId"`
+	UserID    string `json:"userId,omitempty"`
+	// Date is the day the work was done, e.g. 2024-05-01
+	Date     string    `json:"date"`
+	Seconds  int64     `json:"seconds"`
+	Duration string    `json:"duration"`
+	Note     string    `json:"note,omitempty"`
+	LoggedAt time.Time `json:"loggedAt"`
+}
+
+// loggedTime is the result of log_time
+type loggedTime struct {
+	Entry timeEntry `json:"entry"`
+	// Stopwatch is the card's stopwatch with the entry added
+	Stopwatch *planka.Stopwatch `json:"stopwatch"`
+}
+
+// timeEntries is the result of get_time_entries
+type timeEntries struct {
+	CardID       string      `json:"cardId"`
+	Entries      []timeEntry `json:"entries"`
+	TotalSeconds int64       `json:"totalSeconds"`
+	Total        string      `json:"total"`
+	// StopwatchSeconds is the time on the card's stopwatch: all logged entries, whatever
+	// their date, plus the time measured by starting and stopping it
+	StopwatchSeconds int64 `json:"stopwatchSeconds"`
+}
+
+func (s *Server) handleLogTime(ctx context.Context, args logTimeArgs) (interface{}, error) {
+	duration, err := parseTimeEntryDuration(args.Duration)
+	if err != nil {
+		return nil, invalidParams("duration: %v", err)
+	}
+	now := time.Now().In(s.location())
+	day := now.Format("2006-01-02")
+	if args.Date != "" {
+		date, err := parseReportDate(args.Date, now.Location(), false)
+		if err != nil {
+			return nil, invalidParams("date: %v", err)
+		}
+		if day < date.In(now.Location()).Format("2006-01-02") {
+			return nil, invalidParams("date must not be in the future")
+		}
+		day = date.In(now.Location()).Format("2006-01-02")
+	}
+
+	client := s.clientFor(ctx)
+	stopwatch, err := client.GetStopwatch(ctx, args.CardID)
+	if err != nil {
+		return nil, err
+	}
+	seconds := int64(duration.Seconds())
+	text := fmt.Sprintf("⏱ Logged %s on %s", formatTracked(seconds), day)
+	if note := strings.TrimSpace(args.Note); note != "" {
+		text += ": " + note
+	}
+	comment, err := client.CreateComment(ctx, planka.CreateCommentRequest{Text: text, CardID: args.CardID})
+	if err != nil {
+		return nil, err
+	}
+	// The entry is only kept if its time made it onto the stopwatch
+	stopwatch, err = client.SetStopwatchDuration(ctx, args.CardID, stopwatch.Duration+seconds)
+	if err != nil {
+		_ = client.DeleteComment(ctx, comment.ID)
+		return nil, err
+	}
+
+	entry, _ := parseTimeEntry(*comment)
+	return loggedTime{Entry: entry, Stopwatch: stopwatch}, nil
+}
+
+func (s *Server) handleGetTimeEntries(ctx context.Context, args getTimeEntriesArgs) (interface{}, error) {
+	loc := s.location()
+	var from, to string
+	if args.From != "" {
+		date, err := parseReportDate(args.From, loc, false)
+		if err != nil {
+			return nil, invalidParams("from: %v", err)
+		}
+		from = date.In(loc).Format("2006-01-02")
+	}
+	if args.To != "" {
+		date, err := parseReportDate(args.To, loc, false)
+		if err != nil {
+			return nil, invalidParams("to: %v", err)
+		}
+		to = date.In(loc).Format("2006-01-02")
+	}
+
+	client := s.clientFor(ctx)
+	comments, err := client.GetComments(ctx, args.CardID)
+	if err != nil {
+		return nil, err
+	}
+	stopwatch, err := client.GetStopwatch(ctx, args.CardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := timeEntries{CardID: args.CardID, Entries: []timeEntry{}, StopwatchSeconds: stopwatch.Duration}
+	if stopwatch.StartedAt != nil {
+		result.StopwatchSeconds += int64(time.Since(*stopwatch.StartedAt).Seconds())
+	}
+	for _, comment := range comments {
+		entry, ok := parseTimeEntry(comment)
+		if !ok || (from != "" && entry.Date < from) || (to != "" && entry.Date > to) {
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+		result.TotalSeconds += entry.Seconds
+	}
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		if result.Entries[i].Date != result.Entries[j].Date {
+			return result.Entries[i].Date < result.Entries[j].Date
+		}
+		return result.Entries[i].LoggedAt.Before(result.Entries[j].LoggedAt)
+	})
+	result.Total = formatTracked(result.TotalSeconds)
+	return result, nil
+}
+
+// parseTimeEntryDuration parses the time spent on a time entry, a Go duration such as
+// 1h30m or a number of minutes, rounded to whole minutes
+func parseTimeEntryDuration(text string) (time.Duration, error) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), " ", "")
+	var duration time.Duration
+	if minutes, err := strconv.ParseFloat(text, 64); err == nil {
+		duration = time.Duration(minutes * float64(time.Minute))
+	} else if duration, err = time.ParseDuration(text); err != nil {
+		return 0, fmt.Errorf("cannot understand %q; use a duration such as 1h30m, 45m or 2.5h", text)
+	}
+	duration = duration.Round(time.Minute)
+	if duration < time.Minute {
+		return 0, fmt.Errorf("must be at least a minute")
+	}
+	if duration > maxTimeEntry {
+		return 0, fmt.Errorf("must be at most %s; log longer work as one entry per day", formatTracked(int64(maxTimeEntry.Seconds())))
+	}
+	return duration, nil
+}
+
+// parseTimeEntry reads the time entry kept in a ledger comment
+func parseTimeEntry(comment planka.Comment) (timeEntry, bool) {
+	m := timeEntryPattern.FindStringSubmatch(strings.TrimSpace(comment.Text))
+	if m == nil {
+		return timeEntry{}, false
+	}
+	hours, _ := strconv.ParseInt(m[1], 10, 64)
+	minutes, _ := strconv.ParseInt(m[2], 10, 64)
+	seconds := hours*3600 + minutes*60
+	return timeEntry{
+		CommentID: comment.ID,
+		UserID:    comment.UserID,
+		Date:      m[3],
+		Seconds:   seconds,
+		Duration:  formatTracked(seconds),
+		Note:      m[4],
+		LoggedAt:  comment.CreatedAt,
+	}, true
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"fmt"
+	"path"
+)
+
+// toolFilter decides which tools are exposed to clients
+type toolFilter struct {
+	allow []string
+	deny  []string
+}
+
+// allowed reports whether the tool named name is exposed: it must match an allow
+// pattern (when any are configured) and no deny pattern
+func (f *toolFilter) allowed(name string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.allow) > 0 && !matchAny(f.allow, name) {
+		return false
+	}
+	return !matchAny(f.deny, name)
+}
+
+// matchAny reports whether name matches any of the glob patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTools limits the tools exposed to clients. allow and deny are glob patterns such as
+// "get_*" or "*_card": when allow is non-empty only matching tools are exposed, and tools
+// matching deny are always hidden. Hidden tools can neither be listed nor called.
+func (s *Server) FilterTools(allow, deny []string) error {
+	for _, pattern := range append(append([]string{}, allow...), deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	s.filter = &toolFilter{allow: allow, deny: deny}
+	s.registerTools()
+	return nil
+}
+
+// SetReadOnly exposes only the tools that do not change Planka, i.e. those annotated as
+// read-only, on top of any filter. Hidden tools can neither be listed nor called.
+func (s *Server) SetReadOnly() {
+	s.readOnly = true
+	s.registerTools()
+}
@@ -1,1111 +1,755 @@
 package mcp
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"time"
+	"reflect"
 
-	"github.com/ayushgarg/mcp-planka/internal/planka"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// getTools returns the list of available tools
-func (s *Server) getTools() []map[string]interface{} {
-	return []map[string]interface{}{
-		{
-			"name":        "get_projects",
-			"description": "Get all projects",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			"name":        "get_project",
-			"description": "Get a project by ID",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"projectId": map[string]interface{}{
-						"type":        "string",
-						"description": "The project ID",
-					},
-				},
-				"required": []string{"projectId"},
-			},
-		},
-		{
-			"name":        "create_project",
-			"description": "Create a new project",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The project name",
-					},
-					"description": map[string]interface{}{
-						"type":        "string",
-						"description": "The project description",
-					},
-				},
-				"required": []string{"name"},
-			},
-		},
-		{
-			"name":        "delete_project",
-			"description": "Delete a project",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"projectId": map[string]interface{}{
-						"type":        "string",
-						"description": "The project ID",
-					},
-				},
-				"required": []string{"projectId"},
-			},
-		},
-		{
-			"name":        "get_boards",
-			"description": "Get all boards for a project",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"projectId": map[string]interface{}{
-						"type":        "string",
-						"description": "The project ID",
-					},
-				},
-				"required": []string{"projectId"},
-			},
-		},
-		{
-			"name":        "get_board",
-			"description": "Get a board by ID",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"boardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The board ID",
-					},
-				},
-				"required": []string{"boardId"},
-			},
-		},
-		{
-			"name":        "create_board",
-			"description": "Create a new board",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The board name",
-					},
-					"description": map[string]interface{}{
-						"type":        "string",
-						"description": "The board description",
-					},
-					"projectId": map[string]interface{}{
-						"type":        "string",
-						"description": "The project ID",
-					},
-				},
-				"required": []string{"name", "projectId"},
-			},
-		},
-		{
-			"name":        "delete_board",
-			"description": "Delete a board",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"boardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The board ID",
-					},
-				},
-				"required": []string{"boardId"},
-			},
-		},
-		{
-			"name":        "get_lists",
-			"description": "Get all lists for a board",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"boardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The board ID",
-					},
-				},
-				"required": []string{"boardId"},
-			},
-		},
-		{
-			"name":        "get_list",
-			"description": "Get a list by ID",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The list ID",
-					},
-				},
-				"required": []string{"listId"},
-			},
-		},
-		{
-			"name":        "create_list",
-			"description": "Create a new list",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The list name",
-					},
-					"boardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The board ID",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The list position",
-					},
-				},
-				"required": []string{"name", "boardId"},
-			},
-		},
-		{
-			"name":        "delete_list",
-			"description": "Delete a list",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The list ID",
-					},
-				},
-				"required": []string{"listId"},
-			},
-		},
-		{
-			"name":        "get_cards",
-			"description": "Get all cards for a list",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The list ID",
-					},
-				},
-				"required": []string{"listId"},
-			},
-		},
-		{
-			"name":        "get_card",
-			"description": "Get a card by ID",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "create_card",
-			"description": "Create a new card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The card name",
-					},
-					"description": map[string]interface{}{
-						"type":        "string",
-						"description": "The card description",
-					},
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The list ID",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The card position",
-					},
-					"dueDate": map[string]interface{}{
-						"type":        "string",
-						"description": "The due date (ISO 8601 format)",
-					},
-				},
-				"required": []string{"name", "listId"},
-			},
-		},
-		{
-			"name":        "update_card",
-			"description": "Update a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The card name",
-					},
-					"description": map[string]interface{}{
-						"type":        "string",
-						"description": "The card description",
-					},
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The list ID (to move card)",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The card position",
-					},
-					"dueDate": map[string]interface{}{
-						"type":        "string",
-						"description": "The due date (ISO 8601 format)",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "delete_card",
-			"description": "Delete a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "move_card",
-			"description": "Move a card to a different list",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-					"listId": map[string]interface{}{
-						"type":        "string",
-						"description": "The target list ID",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The card position in the new list",
-					},
-				},
-				"required": []string{"cardId", "listId"},
-			},
-		},
-		{
-			"name":        "get_tasks",
-			"description": "Get all tasks for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "create_task",
-			"description": "Create a new task",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The task name",
-					},
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The task position",
-					},
-				},
-				"required": []string{"name", "cardId"},
-			},
-		},
-		{
-			"name":        "update_task",
-			"description": "Update a task",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"taskId": map[string]interface{}{
-						"type":        "string",
-						"description": "The task ID",
-					},
-					"name": map[string]interface{}{
-						"type":        "string",
-						"description": "The task name",
-					},
-					"isCompleted": map[string]interface{}{
-						"type":        "boolean",
-						"description": "Whether the task is completed",
-					},
-					"position": map[string]interface{}{
-						"type":        "number",
-						"description": "The task position",
-					},
-				},
-				"required": []string{"taskId"},
-			},
-		},
-		{
-			"name":        "delete_task",
-			"description": "Delete a task",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"taskId": map[string]interface{}{
-						"type":        "string",
-						"description": "The task ID",
-					},
-				},
-				"required": []string{"taskId"},
-			},
-		},
-		{
-			"name":        "get_comments",
-			"description": "Get all comments for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "create_comment",
-			"description": "Create a new comment",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"text": map[string]interface{}{
-						"type":        "string",
-						"description": "The comment text",
-					},
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"text", "cardId"},
-			},
-		},
-		{
-			"name":        "delete_comment",
-			"description": "Delete a comment",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"commentId": map[string]interface{}{
-						"type":        "string",
-						"description": "The comment ID",
-					},
-				},
-				"required": []string{"commentId"},
-			},
-		},
-		{
-			"name":        "get_stopwatch",
-			"description": "Get the stopwatch for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "start_stopwatch",
-			"description": "Start the stopwatch for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "stop_stopwatch",
-			"description": "Stop the stopwatch for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
-		{
-			"name":        "reset_stopwatch",
-			"description": "Reset the stopwatch for a card",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"cardId": map[string]interface{}{
-						"type":        "string",
-						"description": "The card ID",
-					},
-				},
-				"required": []string{"cardId"},
-			},
-		},
+// toolDef pairs a tool definition with the handler that serves it
+type toolDef struct {
+	tool    *mcpsdk.Tool
+	handler mcpsdk.ToolHandler
+	// local tools report on the server itself and do not act on a Planka instance
+	local bool
+}
+
+// newTool defines a tool whose inputSchema is generated from its argument struct.
+// Arguments are validated against that schema before they are decoded and passed to handler.
+func newTool[In any](tool *mcpsdk.Tool, handler func(ctx context.Context, args In) (interface{}, error)) toolDef {
+	schema := inputSchema(reflect.TypeFor[In]())
+	tool.InputSchema = schema
+	return toolDef{
+		tool:    tool,
+		handler: toolHandler(schema, handler),
 	}
 }
 
-// callTool calls a tool by name with the given arguments
-func (s *Server) callTool(name string, arguments map[string]interface{}) (string, error) {
-	switch name {
-	case "get_projects":
-		return s.handleGetProjects()
-	case "get_project":
-		return s.handleGetProject(arguments)
-	case "create_project":
-		return s.handleCreateProject(arguments)
-	case "delete_project":
-		return s.handleDeleteProject(arguments)
-	case "get_boards":
-		return s.handleGetBoards(arguments)
-	case "get_board":
-		return s.handleGetBoard(arguments)
-	case "create_board":
-		return s.handleCreateBoard(arguments)
-	case "delete_board":
-		return s.handleDeleteBoard(arguments)
-	case "get_lists":
-		return s.handleGetLists(arguments)
-	case "get_list":
-		return s.handleGetList(arguments)
-	case "create_list":
-		return s.handleCreateList(arguments)
-	case "delete_list":
-		return s.handleDeleteList(arguments)
-	case "get_cards":
-		return s.handleGetCards(arguments)
-	case "get_card":
-		return s.handleGetCard(arguments)
-	case "create_card":
-		return s.handleCreateCard(arguments)
-	case "update_card":
-		return s.handleUpdateCard(arguments)
-	case "delete_card":
-		return s.handleDeleteCard(arguments)
-	case "move_card":
-		return s.handleMoveCard(arguments)
-	case "get_tasks":
-		return s.handleGetTasks(arguments)
-	case "create_task":
-		return s.handleCreateTask(arguments)
-	case "update_task":
-		return s.handleUpdateTask(arguments)
-	case "delete_task":
-		return s.handleDeleteTask(arguments)
-	case "get_comments":
-		return s.handleGetComments(arguments)
-	case "create_comment":
-		return s.handleCreateComment(arguments)
-	case "delete_comment":
-		return s.handleDeleteComment(arguments)
-	case "get_stopwatch":
-		return s.handleGetStopwatch(arguments)
-	case "start_stopwatch":
-		return s.handleStartStopwatch(arguments)
-	case "stop_stopwatch":
-		return s.handleStopStopwatch(arguments)
-	case "reset_stopwatch":
-		return s.handleResetStopwatch(arguments)
-	default:
-		return "", fmt.Errorf("unknown tool: %s", name)
+// getTools returns the list of available tools
+func (s *Server) getTools() []toolDef {
+	tools := []toolDef{
+		newTool(&mcpsdk.Tool{
+			Name:         "get_projects",
+			Description:  "Get all projects. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.Project{}),
+		}, s.handleGetProjects),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_project",
+			Description:  "Get a project by ID",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(planka.Project{}),
+		}, s.handleGetProject),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_project",
+			Description:  "Create a new project",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Project{}),
+		}, s.handleCreateProject),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_project",
+			Description: "Delete a project",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteProject),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_boards",
+			Description:  "Get all boards for a project. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.Board{}),
+		}, s.handleGetBoards),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_board",
+			Description:  "Get a board by ID",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(planka.Board{}),
+		}, s.handleGetBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "audit_board",
+			Description:  "Check a board's hygiene: empty lists, open cards without a due date or assignee, cards untouched for staleDays days, and cards with duplicate-looking titles. Returns the findings with suggested actions; pass format markdown or slack for a report ready to post.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(boardAudit{}),
+		}, s.handleAuditBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "standup_report",
+			Description:  "Summarize a board for a standup, per member: cards moved to done within the last lookbackHours hours, cards in progress, cards created, and blockers (cards labeled Blocked or overdue). Pass format markdown or slack for a message ready to post.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(standupReport{}),
+		}, s.handleStandupReport),
+		newTool(&mcpsdk.Tool{
+			Name:         "sprint_report",
+			Description:  "Close out a sprint: the cards completed between from and to versus those carried over, the time tracked on them with stopwatches, and a breakdown by label. Pass archiveDone to move the done lists' cards to the archive list afterwards, and format markdown or slack for a report ready to post.",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(sprintReport{}),
+		}, s.handleSprintReport),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_timeline",
+			Description:  "Get the cards of a board that have a due date as a Gantt-style timeline: each card runs from its start (a custom start date field, or when it was created) to its due date, with its task progress and whether it is done or overdue. Use it to draw schedules.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(timeline{}),
+		}, s.handleGetTimeline),
+		newTool(&mcpsdk.Tool{
+			Name:         "watch_board",
+			Description:  "Wait until cards or lists of a board change, then return what changed. Returns right away if something changed after since, else waits up to timeoutSeconds. Call it again with the returned cursor to keep watching without missing changes.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(boardWatch{}),
+		}, s.handleWatchBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "snapshot_board",
+			Description:  "Record the current state of a board, its lists and cards with their labels and due dates, to compare with diff_board_snapshots later. Pass save to keep it on the server and get an ID for it.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(boardSnapshot{}),
+		}, s.handleSnapshotBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "diff_board_snapshots",
+			Description:  "Compare two snapshots of a board, or a snapshot with the board as it is now: the cards added, removed, moved between lists and completed, and the lists added and removed",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(snapshotDiff{}),
+		}, s.handleDiffBoardSnapshots),
+		newTool(&mcpsdk.Tool{
+			Name:         "restore_board",
+			Description:  "Bring a board back to a snapshot taken with snapshot_board: lists and cards deleted since are recreated (under new IDs, cards with their descriptions and labels but without tasks or comments), and lists and cards moved, renamed or relabeled since are put back. Cards added since are kept unless deleteAdded is set. Pass dryRun to review the plan first.",
+			Annotations:  destructiveAnnotations(),
+			OutputSchema: outputSchema(boardRestore{}),
+		}, s.handleRestoreBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "find_board",
+			Description:  "Find boards by name, optionally within a project. Matching ignores case and tolerates typos and partial names; results include the board and project IDs and names, best match first.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(boardMatch{}),
+		}, s.handleFindBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_board",
+			Description:  "Create a new board",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Board{}),
+		}, s.handleCreateBoard),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_board",
+			Description: "Delete a board",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteBoard),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_lists",
+			Description:  "Get all lists for a board. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.List{}),
+		}, s.handleGetLists),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_list_by_name",
+			Description:  "Find a list on a board by name, such as Done or In Progress, and return its ID and number of cards. An exact name (ignoring case) wins; otherwise the closest name is used.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(listMatch{}),
+		}, s.handleGetListByName),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_list",
+			Description:  "Get a list by ID",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(planka.List{}),
+		}, s.handleGetList),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_list",
+			Description:  "Create a new list",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.List{}),
+		}, s.handleCreateList),
+		newTool(&mcpsdk.Tool{
+			Name:         "swap_list_positions",
+			Description:  "Swap the positions of two lists on a board",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: listOutputSchema(planka.List{}),
+		}, s.handleSwapListPositions),
+		newTool(&mcpsdk.Tool{
+			Name:         "reorder_lists",
+			Description:  "Rearrange the lists (columns) of a board: give the lists by name in their new order from left to right, and the rest follow in their current order",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(listOrder{}),
+		}, s.handleReorderLists),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_list",
+			Description: "Delete a list",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteList),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_cards",
+			Description:  "Get all cards for a list. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.Card{}),
+		}, s.handleGetCards),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_card",
+			Description:  "Get a card by ID. List tasks, comments, labels, members, attachments or stopwatch in include to get those with the card instead of calling a tool for each.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(cardDetails{}),
+		}, s.handleGetCard),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_card",
+			Description:  "Create a new card",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Card{}),
+		}, s.handleCreateCard),
+		newTool(&mcpsdk.Tool{
+			Name:         "update_card",
+			Description:  "Update a card. Omitted fields are left unchanged; list description or dueDate in clearFields to remove them.",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(planka.Card{}),
+		}, s.handleUpdateCard),
+		newTool(&mcpsdk.Tool{
+			Name:         "append_to_description",
+			Description:  "Add text to a card description without touching the rest: at the end, or at the end of a named Markdown section such as Notes, which is created if missing",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Card{}),
+		}, s.handleAppendToDescription),
+		newTool(&mcpsdk.Tool{
+			Name:         "set_description_section",
+			Description:  "Replace the content of one Markdown section of a card description, e.g. Acceptance Criteria, keeping the rest of the description. The section is added at the end if missing; empty content removes it.",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(planka.Card{}),
+		}, s.handleSetDescriptionSection),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_card",
+			Description: "Delete a card",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteCard),
+		newTool(&mcpsdk.Tool{
+			Name:         "move_card",
+			Description:  "Move a card to a different list",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(planka.Card{}),
+		}, s.handleMoveCard),
+		newTool(&mcpsdk.Tool{
+			Name:         "move_all_cards",
+			Description:  "Move every card of a list to another list, keeping their order, e.g. to empty a column. Filter by label or due date to move only some of them, and pass dryRun to preview the cards first.",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(moveAllResult{}),
+		}, s.handleMoveAllCards),
+		newTool(&mcpsdk.Tool{
+			Name:         "auto_sort_list",
+			Description:  "Sort the cards of a list by due date, name or creation date. Pass keepSorted to have the server keep sorting the list as cards are added or changed.",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(sortResult{}),
+		}, s.handleAutoSortList),
+		newTool(&mcpsdk.Tool{
+			Name:         "cleanup_done_cards",
+			Description:  "Archive or delete the cards of done lists that have not changed for olderThanDays days. Archiving moves them to the board's archive list (or a list named Archive). Pass dryRun to preview the cards first.",
+			Annotations:  destructiveAnnotations(),
+			OutputSchema: outputSchema(cleanupResult{}),
+		}, s.handleCleanupDoneCards),
+		newTool(&mcpsdk.Tool{
+			Name:         "import_gitlab_issues",
+			Description:  "Create a card for each issue of a GitLab project, with the issue's labels (missing board labels are created) and assignees mapped to board members. Issues already imported to the board are skipped, so the import can be repeated. Pass dryRun to preview the cards first.",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(gitlabImport{}),
+		}, s.handleImportGitLabIssues),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_tasks",
+			Description:  "Get all tasks for a card. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.Task{}),
+		}, s.handleGetTasks),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_task",
+			Description:  "Create a new task",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Task{}),
+		}, s.handleCreateTask),
+		newTool(&mcpsdk.Tool{
+			Name:         "update_task",
+			Description:  "Update a task",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(planka.Task{}),
+		}, s.handleUpdateTask),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_task",
+			Description: "Delete a task",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteTask),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_comments",
+			Description:  "Get all comments for a card. Pass page/pageSize, limit/offset or cursor to page through the results, and sortBy/sortOrder to sort them.",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: listOutputSchema(planka.Comment{}),
+		}, s.handleGetComments),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_comment",
+			Description:  "Create a new comment",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Comment{}),
+		}, s.handleCreateComment),
+		newTool(&mcpsdk.Tool{
+			Name:         "create_comments_bulk",
+			Description:  "Post a comment on each of a set of cards, e.g. \"Moving to next sprint\" on all carried-over cards. The text may use {{id}}, {{name}}, {{list}} and {{due}} to mention each card's details. Returns the outcome per card; one card failing does not stop the others.",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(bulkCommentsResult{}),
+		}, s.handleCreateCommentsBulk),
+		newTool(&mcpsdk.Tool{
+			Name:        "delete_comment",
+			Description: "Delete a comment",
+			Annotations: destructiveAnnotations(),
+		}, s.handleDeleteComment),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_stopwatch",
+			Description:  "Get the stopwatch for a card",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(planka.Stopwatch{}),
+		}, s.handleGetStopwatch),
+		newTool(&mcpsdk.Tool{
+			Name:         "start_stopwatch",
+			Description:  "Start the stopwatch for a card",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(planka.Stopwatch{}),
+		}, s.handleStartStopwatch),
+		newTool(&mcpsdk.Tool{
+			Name:         "stop_stopwatch",
+			Description:  "Stop the stopwatch for a card",
+			Annotations:  idempotentAnnotations(),
+			OutputSchema: outputSchema(planka.Stopwatch{}),
+		}, s.handleStopStopwatch),
+		newTool(&mcpsdk.Tool{
+			Name:         "reset_stopwatch",
+			Description:  "Reset the stopwatch for a card",
+			Annotations:  destructiveAnnotations(),
+			OutputSchema: outputSchema(planka.Stopwatch{}),
+		}, s.handleResetStopwatch),
+		newTool(&mcpsdk.Tool{
+			Name:         "log_time",
+			Description:  "Log time spent on a card after the fact, e.g. 1h30m yesterday: adds it to the card's stopwatch and records the entry, with its date and note, as a comment",
+			Annotations:  additiveAnnotations(),
+			OutputSchema: outputSchema(loggedTime{}),
+		}, s.handleLogTime),
+		newTool(&mcpsdk.Tool{
+			Name:         "get_time_entries",
+			Description:  "Get the time entries logged on a card with log_time, by date, with their total",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(timeEntries{}),
+		}, s.handleGetTimeEntries),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:         "get_server_stats",
+			Description:  "Get diagnostics of this MCP server: its version, uptime, sessions, and per-endpoint request counts, error rates and latency percentiles of its Planka requests",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(serverStats{}),
+		}, s.handleGetServerStats)),
+		localTool(newTool(&mcpsdk.Tool{
+			Name:         "undo_last",
+			Description:  "Revert the latest change made in this session, e.g. to fix a mangled board: created cards, lists, tasks and comments are deleted, moved and updated cards get their previous list, position and values back, deleted cards are recreated (under a new ID, without labels, tasks or comments) and reordered lists return to their positions. Call it again to undo earlier changes; the last 20 are kept. Updated and deleted tasks, deleted lists and comments, and stopwatch changes cannot be undone.",
+			Annotations:  destructiveAnnotations(),
+			OutputSchema: outputSchema(undoResult{}),
+		}, s.handleUndoLast)),
+	}
+	if s.recurrences != nil {
+		tools = append(tools, s.recurrenceTools()...)
+	}
+	if s.velocity != nil {
+		tools = append(tools, s.velocityTools()...)
+	}
+	if s.rules != nil {
+		tools = append(tools, s.ruleTools()...)
+	}
+	return tools
+}
+
+// localTool marks def as reporting on the server itself rather than acting on Planka
+func localTool(def toolDef) toolDef {
+	def.local = true
+	return def
+}
+
+// toolAnnotations builds the behavior hints hosts use to decide when a tool call needs confirmation
+func toolAnnotations(readOnly, destructive, idempotent bool) *mcpsdk.ToolAnnotations {
+	openWorld := false
+	return &mcpsdk.ToolAnnotations{
+		ReadOnlyHint:    readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  idempotent,
+		OpenWorldHint:   &openWorld,
 	}
 }
 
+// readOnlyAnnotations marks tools that only read from Planka
+func readOnlyAnnotations() *mcpsdk.ToolAnnotations {
+	return toolAnnotations(true, false, true)
+}
+
+// additiveAnnotations marks tools that create new entities; repeating them creates duplicates
+func additiveAnnotations() *mcpsdk.ToolAnnotations {
+	return toolAnnotations(false, false, false)
+}
+
+// idempotentAnnotations marks tools that modify entities in place; repeating them has no further effect
+func idempotentAnnotations() *mcpsdk.ToolAnnotations {
+	return toolAnnotations(false, false, true)
+}
+
+// destructiveAnnotations marks tools that delete or reset data
+func destructiveAnnotations() *mcpsdk.ToolAnnotations {
+	return toolAnnotations(false, true, true)
+}
+
 // Helper functions to handle each tool
 
-func (s *Server) handleGetProjects() (string, error) {
-	projects, err := s.client.GetProjects()
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(projects, "", "  ")
+func (s *Server) handleGetProjects(ctx context.Context, args getProjectsArgs) (interface{}, error) {
+	projects, err := s.clientFor(ctx).GetProjects(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return listResult(projects, args.pageArgs)
 }
 
-func (s *Server) handleGetProject(args map[string]interface{}) (string, error) {
-	projectID, ok := args["projectId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing projectId")
-	}
-	project, err := s.client.GetProject(projectID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(project, "", "  ")
+func (s *Server) handleGetProject(ctx context.Context, args projectArgs) (interface{}, error) {
+	project, err := s.clientFor(ctx).GetProject(ctx, args.ProjectID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return project, nil
 }
 
-func (s *Server) handleCreateProject(args map[string]interface{}) (string, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing name")
-	}
+func (s *Server) handleCreateProject(ctx context.Context, args createProjectArgs) (interface{}, error) {
 	req := planka.CreateProjectRequest{
-		Name: name,
-	}
-	if desc, ok := args["description"].(string); ok {
-		req.Description = desc
-	}
-	project, err := s.client.CreateProject(req)
-	if err != nil {
-		return "", err
+		Name:        args.Name,
+		Description: args.Description,
 	}
-	data, err := json.MarshalIndent(project, "", "  ")
+	project, err := s.clientFor(ctx).CreateProject(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return project, nil
 }
 
-func (s *Server) handleDeleteProject(args map[string]interface{}) (string, error) {
-	projectID, ok := args["projectId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing projectId")
+func (s *Server) handleDeleteProject(ctx context.Context, args projectArgs) (interface{}, error) {
+	if err := s.clientFor(ctx).DeleteProject(ctx, args.ProjectID); err != nil {
+		return nil, err
 	}
-	if err := s.client.DeleteProject(projectID); err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("Project %s deleted successfully", projectID), nil
+	return fmt.Sprintf("Project %s deleted successfully", args.ProjectID), nil
 }
 
-func (s *Server) handleGetBoards(args map[string]interface{}) (string, error) {
-	projectID, ok := args["projectId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing projectId")
-	}
-	boards, err := s.client.GetBoards(projectID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(boards, "", "  ")
+func (s *Server) handleGetBoards(ctx context.Context, args getBoardsArgs) (interface{}, error) {
+	boards, err := s.clientFor(ctx).GetBoards(ctx, args.ProjectID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return listResult(boards, args.pageArgs)
 }
 
-func (s *Server) handleGetBoard(args map[string]interface{}) (string, error) {
-	boardID, ok := args["boardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing boardId")
-	}
-	board, err := s.client.GetBoard(boardID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(board, "", "  ")
+func (s *Server) handleGetBoard(ctx context.Context, args boardArgs) (interface{}, error) {
+	board, err := s.clientFor(ctx).GetBoard(ctx, args.BoardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return board, nil
 }
 
-func (s *Server) handleCreateBoard(args map[string]interface{}) (string, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing name")
-	}
-	projectID, ok := args["projectId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing projectId")
-	}
+func (s *Server) handleCreateBoard(ctx context.Context, args createBoardArgs) (interface{}, error) {
 	req := planka.CreateBoardRequest{
-		Name:      name,
-		ProjectID: projectID,
-	}
-	if desc, ok := args["description"].(string); ok {
-		req.Description = desc
+		Name:        args.Name,
+		ProjectID:   args.ProjectID,
+		Description: args.Description,
 	}
-	board, err := s.client.CreateBoard(req)
+	board, err := s.clientFor(ctx).CreateBoard(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(board, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return board, nil
 }
 
-func (s *Server) handleDeleteBoard(args map[string]interface{}) (string, error) {
-	boardID, ok := args["boardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing boardId")
+func (s *Server) handleDeleteBoard(ctx context.Context, args boardArgs) (interface{}, error) {
+	if err := s.clientFor(ctx).DeleteBoard(ctx, args.BoardID); err != nil {
+		return nil, err
 	}
-	if err := s.client.DeleteBoard(boardID); err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("Board %s deleted successfully", boardID), nil
+	return fmt.Sprintf("Board %s deleted successfully", args.BoardID), nil
 }
 
-func (s *Server) handleGetLists(args map[string]interface{}) (string, error) {
-	boardID, ok := args["boardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing boardId")
-	}
-	lists, err := s.client.GetLists(boardID)
+func (s *Server) handleGetLists(ctx context.Context, args getListsArgs) (interface{}, error) {
+	lists, err := s.clientFor(ctx).GetLists(ctx, args.BoardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(lists, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return listResult(lists, args.pageArgs)
 }
 
-func (s *Server) handleGetList(args map[string]interface{}) (string, error) {
-	listID, ok := args["listId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing listId")
-	}
-	list, err := s.client.GetList(listID)
+func (s *Server) handleGetList(ctx context.Context, args listArgs) (interface{}, error) {
+	list, err := s.clientFor(ctx).GetList(ctx, args.ListID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(list, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return list, nil
 }
 
-func (s *Server) handleCreateList(args map[string]interface{}) (string, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing name")
-	}
-	boardID, ok := args["boardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing boardId")
-	}
+func (s *Server) handleCreateList(ctx context.Context, args createListArgs) (interface{}, error) {
 	req := planka.CreateListRequest{
-		Name:    name,
-		BoardID: boardID,
-	}
-	// Position is required - use provided value or default
-	if pos, ok := args["position"].(float64); ok && pos > 0 {
-		req.Position = pos
-	} else {
-		req.Position = 65535 // Default position
-	}
-	list, err := s.client.CreateList(req)
-	if err != nil {
-		return "", err
+		Name:     args.Name,
+		BoardID:  args.BoardID,
+		Position: args.Position,
 	}
-	data, err := json.MarshalIndent(list, "", "  ")
+	// Without a position the client adds the list after the board's other lists
+	client := s.clientFor(ctx)
+	list, err := client.CreateList(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	s.recordDeleteUndo(ctx, "create_list", "list", list.ID, client.DeleteList)
+	return list, nil
 }
 
-func (s *Server) handleDeleteList(args map[string]interface{}) (string, error) {
-	listID, ok := args["listId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing listId")
+func (s *Server) handleDeleteList(ctx context.Context, args listArgs) (interface{}, error) {
+	if err := s.clientFor(ctx).DeleteList(ctx, args.ListID); err != nil {
+		return nil, err
 	}
-	if err := s.client.DeleteList(listID); err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("List %s deleted successfully", listID), nil
+	return fmt.Sprintf("List %s deleted successfully", args.ListID), nil
 }
 
-func (s *Server) handleGetCards(args map[string]interface{}) (string, error) {
-	listID, ok := args["listId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing listId")
-	}
-	cards, err := s.client.GetCards(listID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(cards, "", "  ")
+func (s *Server) handleGetCards(ctx context.Context, args getCardsArgs) (interface{}, error) {
+	cards, err := s.clientFor(ctx).GetCards(ctx, args.ListID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return listResult(cards, args.pageArgs)
 }
 
-func (s *Server) handleGetCard(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
+func (s *Server) handleGetCard(ctx context.Context, args getCardArgs) (interface{}, error) {
+	if len(args.Include) > 0 {
+		return getCardDetails(ctx, s.clientFor(ctx), args.CardID, args.Include)
 	}
-	card, err := s.client.GetCard(cardID)
+	card, err := s.clientFor(ctx).GetCard(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(card, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return card, nil
 }
 
-func (s *Server) handleCreateCard(args map[string]interface{}) (string, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing name")
+func (s *Server) handleCreateCard(ctx context.Context, args createCardArgs) (interface{}, error) {
+	due, err := s.resolveDueDate("dueDate", args.DueDate)
+	if err != nil {
+		return nil, err
 	}
-	listID, ok := args["listId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing listId")
+	client := s.clientFor(ctx)
+	pos, err := resolvePosition(ctx, client, args.Position, args.ListID, "")
+	if err != nil {
+		return nil, err
 	}
 	req := planka.CreateCardRequest{
-		Name:   name,
-		ListID: listID,
-	}
-	if desc, ok := args["description"].(string); ok {
-		req.Description = desc
-	}
-	if pos, ok := args["position"].(float64); ok {
-		req.Position = pos
-	}
-	if dueDateStr, ok := args["dueDate"].(string); ok {
-		dueDate, err := time.Parse(time.RFC3339, dueDateStr)
-		if err != nil {
-			return "", fmt.Errorf("invalid dueDate format: %w", err)
-		}
-		req.DueDate = &dueDate
-	}
-	card, err := s.client.CreateCard(req)
-	if err != nil {
-		return "", err
+		Name:        args.Name,
+		ListID:      args.ListID,
+		Description: args.Description,
+		Position:    pos,
+		DueDate:     due,
 	}
-	data, err := json.MarshalIndent(card, "", "  ")
+	card, err := client.CreateCard(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	s.recordDeleteUndo(ctx, "create_card", "card", card.ID, client.DeleteCard)
+	return s.applyRules(ctx, client, card), nil
 }
 
-func (s *Server) handleUpdateCard(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	req := planka.UpdateCardRequest{}
-	if name, ok := args["name"].(string); ok {
-		req.Name = &name
-	}
-	if desc, ok := args["description"].(string); ok {
-		req.Description = &desc
-	}
-	if listID, ok := args["listId"].(string); ok {
-		req.ListID = &listID
+func (s *Server) handleUpdateCard(ctx context.Context, args updateCardArgs) (interface{}, error) {
+	due, err := s.resolveDueDate("dueDate", args.DueDate)
+	if err != nil {
+		return nil, err
+	}
+	// Omitted fields are left unchanged; clearFields removes them
+	var clearDescription, clearDueDate bool
+	for _, field := range args.ClearFields {
+		switch {
+		case field == "description" && args.Description == nil:
+			clearDescription = true
+		case field == "dueDate" && args.DueDate == nil:
+			clearDueDate = true
+		case field == "description" || field == "dueDate":
+			return nil, invalidParams("clearFields: %s cannot be cleared and set at once", field)
+		default:
+			return nil, invalidParams("clearFields: cannot clear %q, only description and dueDate", field)
+		}
 	}
-	if pos, ok := args["position"].(float64); ok {
-		req.Position = &pos
+	client := s.clientFor(ctx)
+	// The card as it was is kept so the update can be undone
+	current, err := client.GetCard(ctx, args.CardID)
+	if err != nil {
+		return nil, err
 	}
-	if dueDateStr, ok := args["dueDate"].(string); ok {
-		dueDate, err := time.Parse(time.RFC3339, dueDateStr)
-		if err != nil {
-			return "", fmt.Errorf("invalid dueDate format: %w", err)
+	// Relative positions refer to the list the card ends up in
+	var pos *float64
+	if args.Position != nil {
+		listID := current.ListID
+		if args.ListID != nil {
+			listID = *args.ListID
+		}
+		if pos, err = resolvePosition(ctx, client, args.Position, listID, args.CardID); err != nil {
+			return nil, err
 		}
-		req.DueDate = &dueDate
 	}
-	card, err := s.client.UpdateCard(cardID, req)
-	if err != nil {
-		return "", err
+	req := planka.UpdateCardRequest{
+		Name:        args.Name,
+		Description: args.Description,
+		ListID:      args.ListID,
+		Position:    pos,
+		DueDate:     due,
+
+		ClearDescription: clearDescription,
+		ClearDueDate:     clearDueDate,
 	}
-	data, err := json.MarshalIndent(card, "", "  ")
+	card, err := client.UpdateCard(ctx, args.CardID, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	card = s.applyRules(ctx, client, card)
+	s.recordCardUndo(ctx, client, "update_card", current, card)
+	return card, nil
 }
 
-func (s *Server) handleDeleteCard(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	if err := s.client.DeleteCard(cardID); err != nil {
-		return "", err
-	}
+func (s *Server) handleDeleteCard(ctx context.Context, args cardArgs) (interface{}, error) {
+	client := s.clientFor(ctx)
+	card, err := client.GetCard(ctx, args.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.DeleteCard(ctx, args.CardID); err != nil {
+		return nil, err
+	}
+	// Undoing recreates the card under a new ID, without its labels, tasks and comments
+	s.recordUndo(ctx, "delete_card", fmt.Sprintf("deleted card %s %q", card.ID, card.Name), func(ctx context.Context) error {
+		_, err := client.CreateCard(ctx, planka.CreateCardRequest{
+			Name:        card.Name,
+			Description: card.Description,
+			ListID:      card.ListID,
+			Position:    &card.Position,
+			DueDate:     card.DueDate,
+		})
+		return err
+	})
 	return `{"success": true}`, nil
 }
 
-func (s *Server) handleMoveCard(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	listID, ok := args["listId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing listId")
-	}
-	position := 0.0
-	if pos, ok := args["position"].(float64); ok {
-		position = pos
-	}
-	card, err := s.client.MoveCard(cardID, listID, position)
+func (s *Server) handleMoveCard(ctx context.Context, args moveCardArgs) (interface{}, error) {
+	client := s.clientFor(ctx)
+	current, err := client.GetCard(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(card, "", "  ")
+	pos, err := resolvePosition(ctx, client, args.Position, args.ListID, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
-}
-
-func (s *Server) handleGetTasks(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
+	var position float64
+	if pos != nil {
+		position = *pos
 	}
-	tasks, err := s.client.GetTasks(cardID)
+	card, err := client.MoveCard(ctx, args.CardID, args.ListID, position)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	card = s.applyRules(ctx, client, card)
+	s.recordCardUndo(ctx, client, "move_card", current, card)
+	return card, nil
+}
+
+func (s *Server) handleGetTasks(ctx context.Context, args getTasksArgs) (interface{}, error) {
+	tasks, err := s.clientFor(ctx).GetTasks(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return listResult(tasks, args.pageArgs)
 }
 
-func (s *Server) handleCreateTask(args map[string]interface{}) (string, error) {
-	name, ok := args["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing name")
-	}
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
+func (s *Server) handleCreateTask(ctx context.Context, args createTaskArgs) (interface{}, error) {
 	req := planka.CreateTaskRequest{
-		Name:   name,
-		CardID: cardID,
-	}
-	if pos, ok := args["position"].(float64); ok {
-		req.Position = pos
-	}
-	task, err := s.client.CreateTask(req)
-	if err != nil {
-		return "", err
+		Name:     args.Name,
+		CardID:   args.CardID,
+		Position: args.Position,
 	}
-	data, err := json.MarshalIndent(task, "", "  ")
+	client := s.clientFor(ctx)
+	task, err := client.CreateTask(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	s.recordDeleteUndo(ctx, "create_task", "task", task.ID, client.DeleteTask)
+	return task, nil
 }
 
-func (s *Server) handleUpdateTask(args map[string]interface{}) (string, error) {
-	taskID, ok := args["taskId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing taskId")
-	}
-	req := planka.UpdateTaskRequest{}
-	if name, ok := args["name"].(string); ok {
-		req.Name = &name
-	}
-	if isCompleted, ok := args["isCompleted"].(bool); ok {
-		req.IsCompleted = &isCompleted
-	}
-	if pos, ok := args["position"].(float64); ok {
-		req.Position = &pos
+func (s *Server) handleUpdateTask(ctx context.Context, args updateTaskArgs) (interface{}, error) {
+	req := planka.UpdateTaskRequest{
+		Name:        args.Name,
+		IsCompleted: args.IsCompleted,
+		Position:    args.Position,
 	}
-	task, err := s.client.UpdateTask(taskID, req)
+	task, err := s.clientFor(ctx).UpdateTask(ctx, args.TaskID, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(task, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return task, nil
 }
 
-func (s *Server) handleDeleteTask(args map[string]interface{}) (string, error) {
-	taskID, ok := args["taskId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing taskId")
-	}
-	if err := s.client.DeleteTask(taskID); err != nil {
-		return "", err
+func (s *Server) handleDeleteTask(ctx context.Context, args taskArgs) (interface{}, error) {
+	if err := s.clientFor(ctx).DeleteTask(ctx, args.TaskID); err != nil {
+		return nil, err
 	}
 	return `{"success": true}`, nil
 }
 
-func (s *Server) handleGetComments(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
+func (s *Server) handleGetComments(ctx context.Context, args getCommentsArgs) (interface{}, error) {
+	comments, err := s.clientFor(ctx).GetComments(ctx, args.CardID)
+	truncated := errors.Is(err, planka.ErrTruncated)
+	if err != nil && !truncated {
+		return nil, err
 	}
-	comments, err := s.client.GetComments(cardID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(comments, "", "  ")
-	if err != nil {
-		return "", err
+	result, err := listResult(comments, args.pageArgs)
+	if err != nil || !truncated {
+		return result, err
 	}
-	return string(data), nil
+	return truncatedAtSource(result, "the card has more comments than the server fetches (PLANKA_MAX_ITEMS); only the newest are listed"), nil
 }
 
-func (s *Server) handleCreateComment(args map[string]interface{}) (string, error) {
-	text, ok := args["text"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing text")
-	}
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
+func (s *Server) handleCreateComment(ctx context.Context, args createCommentArgs) (interface{}, error) {
 	req := planka.CreateCommentRequest{
-		Text:   text,
-		CardID: cardID,
-	}
-	comment, err := s.client.CreateComment(req)
-	if err != nil {
-		return "", err
+		Text:   args.Text,
+		CardID: args.CardID,
 	}
-	data, err := json.MarshalIndent(comment, "", "  ")
+	client := s.clientFor(ctx)
+	comment, err := client.CreateComment(ctx, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	s.recordDeleteUndo(ctx, "create_comment", "comment", comment.ID, client.DeleteComment)
+	return comment, nil
 }
 
-func (s *Server) handleDeleteComment(args map[string]interface{}) (string, error) {
-	commentID, ok := args["commentId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing commentId")
-	}
-	if err := s.client.DeleteComment(commentID); err != nil {
-		return "", err
+func (s *Server) handleDeleteComment(ctx context.Context, args commentArgs) (interface{}, error) {
+	if err := s.clientFor(ctx).DeleteComment(ctx, args.CommentID); err != nil {
+		return nil, err
 	}
 	return `{"success": true}`, nil
 }
 
-func (s *Server) handleGetStopwatch(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	stopwatch, err := s.client.GetStopwatch(cardID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(stopwatch, "", "  ")
+func (s *Server) handleGetStopwatch(ctx context.Context, args cardArgs) (interface{}, error) {
+	stopwatch, err := s.clientFor(ctx).GetStopwatch(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return stopwatch, nil
 }
 
-func (s *Server) handleStartStopwatch(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	stopwatch, err := s.client.StartStopwatch(cardID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(stopwatch, "", "  ")
+func (s *Server) handleStartStopwatch(ctx context.Context, args cardArgs) (interface{}, error) {
+	stopwatch, err := s.clientFor(ctx).StartStopwatch(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return stopwatch, nil
 }
 
-func (s *Server) handleStopStopwatch(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	stopwatch, err := s.client.StopStopwatch(cardID)
-	if err != nil {
-		return "", err
-	}
-	data, err := json.MarshalIndent(stopwatch, "", "  ")
+func (s *Server) handleStopStopwatch(ctx context.Context, args cardArgs) (interface{}, error) {
+	stopwatch, err := s.clientFor(ctx).StopStopwatch(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(data), nil
+	return stopwatch, nil
 }
 
-func (s *Server) handleResetStopwatch(args map[string]interface{}) (string, error) {
-	cardID, ok := args["cardId"].(string)
-	if !ok {
-		return "", fmt.Errorf("missing cardId")
-	}
-	stopwatch, err := s.client.ResetStopwatch(cardID)
+func (s *Server) handleResetStopwatch(ctx context.Context, args cardArgs) (interface{}, error) {
+	stopwatch, err := s.clientFor(ctx).ResetStopwatch(ctx, args.CardID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	data, err := json.MarshalIndent(stopwatch, "", "  ")
-	if err != nil {
-		return "", err
-	}
-	return string(data), nil
+	return stopwatch, nil
 }
-
@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connect starts s and returns a client session connected to it in memory
+func connect(t *testing.T, s *Server) *mcpsdk.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcpsdk.NewInMemoryTransports()
+	if _, err := s.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// callTool calls a tool and decodes its text result into out, failing on tool errors
+func callTool(t *testing.T, session *mcpsdk.ClientSession, name string, args map[string]interface{}, out interface{}) {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcpsdk.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	text := result.Content[0].(*mcpsdk.TextContent).Text
+	if result.IsError {
+		t.Fatalf("%s failed: %s", name, text)
+	}
+	if out != nil {
+		if err := json.Unmarshal([]byte(text), out); err != nil {
+			t.Fatalf("%s: decoding %q: %v", name, text, err)
+		}
+	}
+}
+
+func TestToolsAgainstFakePlanka(t *testing.T) {
+	planka := plankatest.NewServer(t)
+	session := connect(t, NewServer(planka.Client()))
+
+	var project, board, list, card struct {
+		ID string `json:"id"`
+	}
+	callTool(t, session, "create_project", map[string]interface{}{"name": "Launch"}, &project)
+	callTool(t, session, "create_board", map[string]interface{}{"name": "Roadmap", "projectId": project.ID}, &board)
+	callTool(t, session, "create_list", map[string]interface{}{"name": "Todo", "boardId": board.ID}, &list)
+	callTool(t, session, "create_card", map[string]interface{}{"name": "Write docs", "listId": list.ID}, &card)
+	callTool(t, session, "create_task", map[string]interface{}{"name": "Outline", "cardId": card.ID}, nil)
+	callTool(t, session, "create_comment", map[string]interface{}{"text": "Looks good", "cardId": card.ID}, nil)
+
+	var cards struct {
+		Items []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"items"`
+		TotalCount int `json:"totalCount"`
+	}
+	callTool(t, session, "get_cards", map[string]interface{}{"listId": list.ID}, &cards)
+	if len(cards.Items) != 1 || cards.Items[0].ID != card.ID || cards.Items[0].Name != "Write docs" || cards.TotalCount != 1 {
+		t.Errorf("get_cards = %+v, want the created card", cards)
+	}
+
+	var tasks struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	callTool(t, session, "get_tasks", map[string]interface{}{"cardId": card.ID}, &tasks)
+	if len(tasks.Items) != 1 || tasks.Items[0].Name != "Outline" {
+		t.Errorf("get_tasks = %+v, want the created task", tasks)
+	}
+
+	var comments struct {
+		Items []struct {
+			Text string `json:"text"`
+		} `json:"items"`
+	}
+	callTool(t, session, "get_comments", map[string]interface{}{"cardId": card.ID}, &comments)
+	if len(comments.Items) != 1 || comments.Items[0].Text != "Looks good" {
+		t.Errorf("get_comments = %+v, want the created comment", comments)
+	}
+}
+
+func TestToolErrorForMissingCard(t *testing.T) {
+	planka := plankatest.NewServer(t)
+	session := connect(t, NewServer(planka.Client()))
+
+	result, err := session.CallTool(context.Background(), &mcpsdk.CallToolParams{
+		Name:      "get_card",
+		Arguments: map[string]interface{}{"cardId": "404"},
+	})
+	if err != nil {
+		t.Fatalf("get_card: %v", err)
+	}
+	text := result.Content[0].(*mcpsdk.TextContent).Text
+	if !result.IsError || !strings.HasPrefix(text, "card not found") {
+		t.Errorf("get_card of a missing card = %q (isError %v), want a card not found tool error", text, result.IsError)
+	}
+}
+
+func TestGetCardIncludes(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	boardID := srv.AddBoard(srv.AddProject("Launch"), "Roadmap")
+	cardID := srv.AddCard(srv.AddList(boardID, "Todo"), "Write docs")
+	srv.AddTask(cardID, "Outline")
+	srv.AddComment(cardID, "Looks good")
+	labelID := srv.AddLabel(boardID, "Docs", "sky-blue")
+	client := srv.Client()
+	if err := client.AddCardLabel(context.Background(), cardID, labelID); err != nil {
+		t.Fatalf("AddCardLabel: %v", err)
+	}
+	if err := client.AddCardMember(context.Background(), cardID, "1"); err != nil {
+		t.Fatalf("AddCardMember: %v", err)
+	}
+	session := connect(t, NewServer(srv.Client()))
+
+	var card struct {
+		Name  string `json:"name"`
+		Tasks []struct {
+			Name string `json:"name"`
+		} `json:"tasks"`
+		Comments []struct {
+			Text string `json:"text"`
+		} `json:"comments"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Members []struct {
+			Username string `json:"username"`
+		} `json:"members"`
+		Stopwatch *struct {
+			Duration int64 `json:"duration"`
+		} `json:"stopwatch"`
+	}
+	callTool(t, session, "get_card", map[string]interface{}{
+		"cardId":  cardID,
+		"include": []string{"tasks", "comments", "labels", "members", "stopwatch"},
+	}, &card)
+	if card.Name != "Write docs" || len(card.Tasks) != 1 || card.Tasks[0].Name != "Outline" ||
+		len(card.Comments) != 1 || card.Comments[0].Text != "Looks good" {
+		t.Errorf("get_card = %+v, want the card with its task and comment", card)
+	}
+	if len(card.Labels) != 1 || card.Labels[0].Name != "Docs" {
+		t.Errorf("get_card labels = %+v, want the Docs label", card.Labels)
+	}
+	if len(card.Members) != 1 || card.Members[0].Username != plankatest.Username {
+		t.Errorf("get_card members = %+v, want the demo user", card.Members)
+	}
+	if card.Stopwatch == nil {
+		t.Error("get_card has no stopwatch, want it included")
+	}
+
+	var plain map[string]interface{}
+	callTool(t, session, "get_card", map[string]interface{}{"cardId": cardID, "include": []string{"tasks"}}, &plain)
+	for _, key := range []string{"comments", "labels", "members", "stopwatch"} {
+		if _, ok := plain[key]; ok {
+			t.Errorf("get_card with only tasks included has %s", key)
+		}
+	}
+}
+
+func TestWebhookEventForgetsEveryClient(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	boardID := srv.AddBoard(srv.AddProject("Project"), "Board")
+	listID := srv.AddList(boardID, "Todo")
+	srv.AddCard(listID, "First")
+	cached := planka.WithCache(time.Minute)
+
+	s := NewServer(srv.Client(cached))
+	work, home := srv.Client(cached), srv.Client(cached)
+	s.SetInstances(map[string]PlankaClient{"work": work, "home": home}, "work")
+	s.EnableMultiTenant(MultiTenantOptions{BaseURL: srv.URL, ClientOptions: []planka.Option{cached}})
+	tenant := s.tenants.client(plankatest.Token)
+
+	ctx := context.Background()
+	clients := map[string]*planka.Client{"default": s.client.(*planka.Client), "work": work, "home": home, "tenant": tenant}
+	for name, client := range clients {
+		if _, err := client.GetBoardCards(ctx, boardID); err != nil {
+			t.Fatalf("%s GetBoardCards: %v", name, err)
+		}
+	}
+
+	// A card added in Planka's UI reaches the server as a webhook
+	cardID := srv.AddCard(listID, "Second")
+	s.forgetEvent(planka.Event{Name: "cardCreate", Entity: "card", Action: "create", BoardID: boardID, Item: json.RawMessage(`{"id":"` + cardID + `"}`)})
+	for name, client := range clients {
+		if cards, err := client.GetBoardCards(ctx, boardID); err != nil || len(cards) != 2 {
+			t.Errorf("%s GetBoardCards after the webhook = %d cards, %v, want both", name, len(cards), err)
+		}
+	}
+}
+
+func TestRestoreBoardRecreatesDescription(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	boardID := srv.AddBoard(srv.AddProject("Project"), "Board")
+	listID := srv.AddList(boardID, "Todo")
+	session := connect(t, NewServer(srv.Client()))
+
+	var card struct {
+		ID string `json:"id"`
+	}
+	callTool(t, session, "create_card", map[string]interface{}{"name": "Write docs", "listId": listID, "description": "Cover the API"}, &card)
+	var snapshot map[string]interface{}
+	callTool(t, session, "snapshot_board", map[string]interface{}{"boardId": boardID}, &snapshot)
+	callTool(t, session, "delete_card", map[string]interface{}{"cardId": card.ID}, nil)
+
+	var restore struct {
+		Actions []struct {
+			Action string `json:"action"`
+			NewID  string `json:"newId"`
+		} `json:"actions"`
+	}
+	callTool(t, session, "restore_board", map[string]interface{}{"snapshot": snapshot}, &restore)
+	if len(restore.Actions) != 1 || restore.Actions[0].Action != "createCard" {
+		t.Fatalf("restore_board actions = %+v, want the card recreated", restore.Actions)
+	}
+	var recreated struct {
+		Description string `json:"description"`
+	}
+	callTool(t, session, "get_card", map[string]interface{}{"cardId": restore.Actions[0].NewID}, &recreated)
+	if recreated.Description != "Cover the API" {
+		t.Errorf("recreated card description = %q, want the one in the snapshot", recreated.Description)
+	}
+}
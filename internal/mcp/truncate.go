@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LimitResultSize caps the size of tool results at maxBytes of JSON text so large boards
+// do not blow through the model's context budget. List results are cut down to the items
+// that fit and flagged as truncated; other results have their text cut off with a marker.
+// Zero or less disables the limit.
+func (s *Server) LimitResultSize(maxBytes int) {
+	s.maxResultBytes = maxBytes
+}
+
+// limitResultSize wraps a tool handler so its results respect the configured maximum size
+func (s *Server) limitResultSize(next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || s.maxResultBytes <= 0 {
+			return result, err
+		}
+		return truncateResult(result, s.maxResultBytes)
+	}
+}
+
+// truncateResult shrinks result to at most maxBytes of text if it is larger
+func truncateResult(result *mcpsdk.CallToolResult, maxBytes int) (*mcpsdk.CallToolResult, error) {
+	if len(result.Content) != 1 {
+		return result, nil
+	}
+	text, ok := result.Content[0].(*mcpsdk.TextContent)
+	if !ok || len(text.Text) <= maxBytes {
+		return result, nil
+	}
+
+	// An empty list has no items to drop, so its text is cut like any other result's
+	if paged, ok := result.StructuredContent.(pagedResult); ok && reflect.ValueOf(paged.Items).Len() > 0 {
+		return truncateList(paged, len(text.Text), maxBytes)
+	}
+
+	return &mcpsdk.CallToolResult{
+		Content:           []mcpsdk.Content{&mcpsdk.TextContent{Text: cutText(text.Text, maxBytes)}},
+		StructuredContent: result.StructuredContent,
+	}, nil
+}
+
+// cutText cuts text longer than maxBytes off with a marker saying so
+func cutText(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	// Cut on a character boundary so the text stays valid UTF-8
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + fmt.Sprintf("\n[truncated: result was %d bytes, showing the first %d]", len(text), cut)
+}
+
+// truncateList keeps as many leading items of a list result as fit in maxBytes
+func truncateList(paged pagedResult, size, maxBytes int) (*mcpsdk.CallToolResult, error) {
+	items := reflect.ValueOf(paged.Items)
+	count := items.Len()
+
+	paged.Truncated = true
+	paged.HasMore = true
+
+	build := func(n int) (pagedResult, []byte, error) {
+		page := paged
+		page.Items = items.Slice(0, n).Interface()
+		page.ReturnedCount = n
+		if paged.cursor {
+			page.NextCursor = encodeCursor(paged.offset + n)
+		}
+		data, err := json.MarshalIndent(page, "", "  ")
+		return page, data, err
+	}
+
+	// Find the largest number of items that still fits. At least one item is always
+	// returned, even if it alone is too large, so callers can make progress.
+	low, high := 1, count-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		_, data, err := build(mid)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) <= maxBytes {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	page, data, err := build(low)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := "request the rest with the returned nextCursor"
+	if !paged.cursor {
+		hint = fmt.Sprintf("use page/pageSize (e.g. pageSize %d) to see the rest", low)
+	}
+	marker := fmt.Sprintf("[truncated: result was %d bytes, showing %d of %d items; %s]", size, low, count, hint)
+
+	return &mcpsdk.CallToolResult{
+		Content: []mcpsdk.Content{
+			&mcpsdk.TextContent{Text: string(data)},
+			&mcpsdk.TextContent{Text: marker},
+		},
+		StructuredContent: page,
+	}, nil
+}
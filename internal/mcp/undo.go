@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// undoHistorySize is how many changes of a session undo_last can revert
+	undoHistorySize = 20
+	// undoSessionIdle is how long the history of a session without changes is kept
+	undoSessionIdle = time.Hour
+)
+
+// sessionKey is the context key of the ID of the MCP session a tool call belongs to
+type sessionKey struct{}
+
+// undoStep is a change made through a tool, with how to revert it
+type undoStep struct {
+	Tool        string
+	Description string
+	At          time.Time
+	revert      func(ctx context.Context) error
+}
+
+// sessionHistory is the changes of one session, oldest first
+type sessionHistory struct {
+	steps []undoStep
+	used  time.Time
+}
+
+// undoHistory keeps the most recent changes of each session so they can be undone
+type undoHistory struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionHistory
+}
+
+// undoResult is the result of undo_last
+type undoResult struct {
+	// Tool and Undone describe the change that was reverted
+	Tool      string    `json:"tool"`
+	Undone    string    `json:"undone"`
+	ChangedAt time.Time `json:"changedAt"`
+	// Remaining counts the earlier changes undo_last can still revert
+	Remaining int `json:"remaining"`
+}
+
+// push adds a change to the history of a session, forgetting its oldest change once
+// the history is full and the sessions idle for too long
+func (h *undoHistory) push(session string, step undoStep) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions == nil {
+		h.sessions = map[string]*sessionHistory{}
+	}
+	for id, history := range h.sessions {
+		if time.Since(history.used) > undoSessionIdle {
+			delete(h.sessions, id)
+		}
+	}
+	history, ok := h.sessions[session]
+	if !ok {
+		history = &sessionHistory{}
+		h.sessions[session] = history
+	}
+	history.steps = append(history.steps, step)
+	if len(history.steps) > undoHistorySize {
+		history.steps = history.steps[len(history.steps)-undoHistorySize:]
+	}
+	history.used = time.Now()
+}
+
+// pop removes the latest change of a session from its history and returns it along with
+// the number of changes left
+func (h *undoHistory) pop(session string) (undoStep, int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	history, ok := h.sessions[session]
+	if !ok || len(history.steps) == 0 {
+		return undoStep{}, 0, false
+	}
+	n := len(history.steps) - 1
+	step := history.steps[n]
+	history.steps = history.steps[:n]
+	if n == 0 {
+		delete(h.sessions, session)
+	}
+	return step, n, true
+}
+
+// withSession makes the ID of the session calling a tool available to its handler, so
+// changes are recorded in the history of that session
+func (s *Server) withSession(next mcpsdk.ToolHandler) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		if session := req.GetSession(); session != nil {
+			ctx = context.WithValue(ctx, sessionKey{}, session.ID())
+		}
+		return next(ctx, req)
+	}
+}
+
+// recordUndo remembers how to revert a change a tool just made in the history of the
+// calling session
+func (s *Server) recordUndo(ctx context.Context, tool, description string, revert func(ctx context.Context) error) {
+	// A dry run changed nothing
+	if planka.DryRunFromContext(ctx) != nil {
+		return
+	}
+	session, _ := ctx.Value(sessionKey{}).(string)
+	s.undo.push(session, undoStep{Tool: tool, Description: description, At: time.Now().UTC(), revert: revert})
+}
+
+func (s *Server) handleUndoLast(ctx context.Context, args undoLastArgs) (interface{}, error) {
+	session, _ := ctx.Value(sessionKey{}).(string)
+	step, remaining, ok := s.undo.pop(session)
+	if !ok {
+		return nil, invalidParams("there is nothing to undo in this session")
+	}
+	// A change that cannot be reverted, e.g. because the card was deleted since, is
+	// dropped so the changes before it can still be undone
+	if err := step.revert(ctx); err != nil {
+		return nil, fmt.Errorf("failed to undo %s (%s), it was removed from the history: %w", step.Tool, step.Description, err)
+	}
+	// A dry run only shows how the change would be undone, so it can still be
+	if planka.DryRunFromContext(ctx) != nil {
+		s.undo.push(session, step)
+		remaining++
+	}
+	return undoResult{Tool: step.Tool, Undone: step.Description, ChangedAt: step.At, Remaining: remaining}, nil
+}
+
+// recordCardUndo remembers how to restore a card changed by a tool to how it was before,
+// reverting the fields that differ, including those changed by rules
+func (s *Server) recordCardUndo(ctx context.Context, client PlankaClient, tool string, before, after *planka.Card) {
+	var req planka.UpdateCardRequest
+	changed := false
+	if after.Name != before.Name {
+		req.Name, changed = &before.Name, true
+	}
+	if after.Description != before.Description {
+		req.Description, req.ClearDescription, changed = &before.Description, before.Description == "", true
+	}
+	if after.ListID != before.ListID || after.Position != before.Position {
+		req.ListID, req.Position, changed = &before.ListID, &before.Position, true
+	}
+	if before.DueDate == nil && after.DueDate != nil {
+		req.ClearDueDate, changed = true, true
+	} else if before.DueDate != nil && (after.DueDate == nil || !after.DueDate.Equal(*before.DueDate)) {
+		req.DueDate, changed = before.DueDate, true
+	}
+	if !changed {
+		return
+	}
+	s.recordUndo(ctx, tool, fmt.Sprintf("changes to card %s %q", before.ID, before.Name), func(ctx context.Context) error {
+		_, err := client.UpdateCard(ctx, before.ID, req)
+		return err
+	})
+}
+
+// recordDeleteUndo remembers how to delete an entity a tool created
+func (s *Server) recordDeleteUndo(ctx context.Context, tool, entity, id string, remove func(ctx context.Context, id string) error) {
+	s.recordUndo(ctx, tool, fmt.Sprintf("created %s %s", entity, id), func(ctx context.Context) error {
+		return remove(ctx, id)
+	})
+}
+
+// recordListPositionsUndo remembers how to put lists back at the positions they had
+// before a tool moved them
+func (s *Server) recordListPositionsUndo(ctx context.Context, client PlankaClient, tool string, lists []planka.List) {
+	if len(lists) == 0 {
+		return
+	}
+	s.recordUndo(ctx, tool, fmt.Sprintf("positions of %d lists", len(lists)), func(ctx context.Context) error {
+		for _, list := range lists {
+			position := list.Position
+			if _, err := client.UpdateList(ctx, list.ID, planka.UpdateListRequest{Position: &position}); err != nil {
+				return fmt.Errorf("list %s: %w", list.ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// plankaIDPattern matches Planka's IDs, 64-bit integers sent as strings, and the
+// placeholder IDs of entities created in a dry run
+var plankaIDPattern = regexp.MustCompile(`^(\d{1,20}|dry-run-\d+)$`)
+
+// localIDArguments name records of the server itself rather than Planka entities
+var localIDArguments = []string{"snapshotId", "recurrenceId", "ruleId"}
+
+// isPlankaIDArgument reports whether the argument name holds Planka IDs, e.g. cardId or listIds
+func isPlankaIDArgument(name string) bool {
+	return (strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "Ids")) && !slices.Contains(localIDArguments, name)
+}
+
+// validateArguments checks tool arguments against the tool's inputSchema.
+// It reports the first problem found as an invalid params error naming the argument,
+// so callers get "position must be a number" instead of the value being silently ignored.
+func validateArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			value, ok := args[name]
+			if !ok || value == nil {
+				return invalidArgument(name, "missing required argument %s", name)
+			}
+			if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+				return invalidArgument(name, "%s must not be empty", name)
+			}
+		}
+	}
+
+	// Check arguments in a stable order so the same call always reports the same error
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Arguments the tool does not declare are ignored, and an explicit null
+		// is treated like an omitted optional argument
+		property, ok := properties[name].(map[string]interface{})
+		if !ok || args[name] == nil {
+			continue
+		}
+		if err := validateValue(name, name, property, args[name]); err != nil {
+			return err
+		}
+		if isPlankaIDArgument(name) {
+			if err := validateIDs(name, args[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateValue checks a single decoded JSON value of argument field against its property
+// schema; name describes the value in errors
+func validateValue(field, name string, property map[string]interface{}, value interface{}) error {
+	if branches, ok := property["anyOf"].([]interface{}); ok {
+		return validateAnyOf(field, name, branches, value)
+	}
+	switch property["type"] {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return invalidArgument(field, "%s must be a string", name)
+		}
+		if property["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return invalidArgument(field, "%s must be an RFC 3339 date-time (e.g. 2024-01-31T17:00:00Z)", name)
+			}
+		}
+		if values, ok := property["enum"].([]string); ok && !slices.Contains(values, str) {
+			return invalidArgument(field, "%s must be one of %s, got %q", name, strings.Join(values, ", "), str)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return invalidArgument(field, "%s must be a number", name)
+		}
+	case "integer":
+		num, ok := value.(float64)
+		if !ok || num != math.Trunc(num) {
+			return invalidArgument(field, "%s must be an integer", name)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalidArgument(field, "%s must be a boolean", name)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return invalidArgument(field, "%s must be an array", name)
+		}
+		if itemSchema, ok := property["items"].(map[string]interface{}); ok {
+			for _, item := range items {
+				if err := validateValue(field, name+" items", itemSchema, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return invalidArgument(field, "%s must be an object", name)
+		}
+	}
+	return nil
+}
+
+// validateAnyOf checks a value against the branches of an anyOf schema, accepting it if
+// any branch does. A value of a type no branch has is reported with all the types, e.g.
+// "position must be a number or a string"; otherwise the matching branch's error is.
+func validateAnyOf(field, name string, branches []interface{}, value interface{}) error {
+	var types []string
+	var typeErr error
+	for _, branch := range branches {
+		branch, ok := branch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		err := validateValue(field, name, branch, value)
+		if err == nil {
+			return nil
+		}
+		kind, _ := branch["type"].(string)
+		types = append(types, withArticle(kind))
+		if typeErr == nil && (kind == jsonType(value) || kind == "integer" && jsonType(value) == "number") {
+			typeErr = err
+		}
+	}
+	if typeErr != nil {
+		return typeErr
+	}
+	return invalidArgument(field, "%s must be %s", name, strings.Join(types, " or "))
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "null"
+}
+
+// withArticle returns a JSON Schema type name with its indefinite article, e.g. "an integer"
+func withArticle(kind string) string {
+	if kind == "integer" || kind == "array" || kind == "object" {
+		return "an " + kind
+	}
+	return "a " + kind
+}
+
+// validateIDs checks that the ID argument name, a string or an array of strings, holds
+// Planka IDs, so a name passed as an ID is rejected before it reaches Planka
+func validateIDs(name string, value interface{}) error {
+	ids := []interface{}{value}
+	if items, ok := value.([]interface{}); ok {
+		ids = items
+	}
+	for _, id := range ids {
+		id, _ := id.(string)
+		if id == "" && !strings.HasSuffix(name, "Ids") {
+			// An empty optional ID is treated like an omitted one
+			continue
+		}
+		if plankaIDPattern.MatchString(id) {
+			continue
+		}
+		for _, arg := range nameArguments {
+			if arg.id == name {
+				return invalidArgument(name, "%s must be a Planka ID such as 1357158568008091264, got %q; pass %s to refer to the %s by name", name, id, arg.name, arg.kind)
+			}
+		}
+		return invalidArgument(name, "%s must be a Planka ID such as 1357158568008091264, got %q", name, id)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultVelocitySprints is how many past sprints get_velocity reports by default
+const defaultVelocitySprints = 6
+
+// sprintSnapshot is what a board's sprint delivered, recorded by sprint_report
+type sprintSnapshot struct {
+	BoardID   string `json:"boardId"`
+	BoardName string `json:"boardName"`
+	// Instance is the Planka instance the board belongs to; empty for the default one
+	Instance       string    `json:"instance,omitempty"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	CompletedCards int       `json:"completedCards"`
+	CompletedTasks int       `json:"completedTasks"`
+	CarriedOver    int       `json:"carriedOver"`
+	TrackedSeconds int64     `json:"trackedSeconds"`
+	RecordedAt     time.Time `json:"recordedAt"`
+}
+
+// velocityFile is the layout of the velocity state file
+type velocityFile struct {
+	Sprints []sprintSnapshot `json:"sprints"`
+}
+
+// velocityStore keeps the sprint snapshots and persists every change to its file
+type velocityStore struct {
+	path string
+
+	mu      sync.Mutex
+	sprints []sprintSnapshot
+}
+
+// velocityReport is the result of get_velocity
+type velocityReport struct {
+	BoardID string `json:"boardId"`
+	// Sprints are the recorded sprints, oldest first
+	Sprints []sprintSnapshot `json:"sprints"`
+	// AverageCards and AverageTasks are the completed cards and tasks per sprint, the
+	// basis of a forecast of the next sprint
+	AverageCards float64 `json:"averageCards"`
+	AverageTasks float64 `json:"averageTasks"`
+}
+
+// loadVelocity reads the sprint snapshots stored at path; a missing file holds none
+func loadVelocity(path string) (*velocityStore, error) {
+	store := &velocityStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file velocityFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid velocity file %s: %w", path, err)
+	}
+	store.sprints = file.Sprints
+	return store, nil
+}
+
+// record stores snapshot, replacing an earlier one of the same board and dates, so
+// reporting a sprint again updates it
+func (v *velocityStore) record(snapshot sprintSnapshot) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	sprints := []sprintSnapshot{}
+	for _, sprint := range v.sprints {
+		if sprint.Instance != snapshot.Instance || sprint.BoardID != snapshot.BoardID || !sprint.From.Equal(snapshot.From) || !sprint.To.Equal(snapshot.To) {
+			sprints = append(sprints, sprint)
+		}
+	}
+	sprints = append(sprints, snapshot)
+	if err := writeStateFile(v.path, velocityFile{Sprints: sprints}); err != nil {
+		return fmt.Errorf("failed to save velocity: %w", err)
+	}
+	v.sprints = sprints
+	return nil
+}
+
+// board returns the last n sprints of a board, oldest first
+func (v *velocityStore) board(instance, boardID string, n int) []sprintSnapshot {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	sprints := []sprintSnapshot{}
+	for _, sprint := range v.sprints {
+		if sprint.Instance == instance && sprint.BoardID == boardID {
+			sprints = append(sprints, sprint)
+		}
+	}
+	sort.SliceStable(sprints, func(i, j int) bool { return sprints[i].From.Before(sprints[j].From) })
+	if len(sprints) > n {
+		sprints = sprints[len(sprints)-n:]
+	}
+	return sprints
+}
+
+// EnableVelocity loads the sprint snapshots stored in the state file at path. From then
+// on every sprint_report is recorded there, and get_velocity reports the recorded sprints.
+func (s *Server) EnableVelocity(path string) error {
+	store, err := loadVelocity(path)
+	if err != nil {
+		return err
+	}
+	s.velocity = store
+	s.registerTools()
+	return nil
+}
+
+// recordSprint stores the snapshot of a sprint report, if velocity tracking is enabled
+func (s *Server) recordSprint(ctx context.Context, sprint sprintReport) error {
+	if s.velocity == nil {
+		return nil
+	}
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	return s.velocity.record(sprintSnapshot{
+		BoardID:        sprint.BoardID,
+		BoardName:      sprint.BoardName,
+		Instance:       instance,
+		From:           sprint.From.UTC(),
+		To:             sprint.To.UTC(),
+		CompletedCards: len(sprint.Completed),
+		CompletedTasks: sprint.CompletedTasks,
+		CarriedOver:    len(sprint.CarriedOver),
+		TrackedSeconds: sprint.TrackedSeconds,
+		RecordedAt:     time.Now().UTC(),
+	})
+}
+
+// velocityTools returns the tools reporting recorded sprints
+func (s *Server) velocityTools() []toolDef {
+	return []toolDef{
+		newTool(&mcpsdk.Tool{
+			Name:         "get_velocity",
+			Description:  "Report the cards and tasks completed in a board's past sprints, as recorded by sprint_report, with the averages per sprint for forecasting how much the next sprint can take",
+			Annotations:  readOnlyAnnotations(),
+			OutputSchema: outputSchema(velocityReport{}),
+		}, s.handleGetVelocity),
+	}
+}
+
+func (s *Server) handleGetVelocity(ctx context.Context, args getVelocityArgs) (interface{}, error) {
+	if err := args.formatArgs.validate(); err != nil {
+		return nil, err
+	}
+	n := defaultVelocitySprints
+	if args.Sprints != nil {
+		if *args.Sprints < 1 {
+			return nil, invalidParams("sprints must be at least 1")
+		}
+		n = *args.Sprints
+	}
+
+	instance, _ := ctx.Value(instanceKey{}).(string)
+	report := velocityReport{BoardID: args.BoardID, Sprints: s.velocity.board(instance, args.BoardID, n)}
+	if len(report.Sprints) > 0 {
+		cards, tasks := 0, 0
+		for _, sprint := range report.Sprints {
+			cards += sprint.CompletedCards
+			tasks += sprint.CompletedTasks
+		}
+		report.AverageCards = float64(cards) / float64(len(report.Sprints))
+		report.AverageTasks = float64(tasks) / float64(len(report.Sprints))
+	}
+	return formatReport(args.Format, report, report.document()), nil
+}
+
+// document lays out the velocity for the markdown and slack formats
+func (r velocityReport) document() report {
+	doc := report{Title: "Velocity", Empty: "No sprints recorded yet; run sprint_report at the end of each sprint."}
+	if len(r.Sprints) > 0 {
+		doc.Title = "Velocity: " + r.Sprints[len(r.Sprints)-1].BoardName
+		doc.Summary = fmt.Sprintf("%.1f cards and %.1f tasks completed per sprint over the last %s",
+			r.AverageCards, r.AverageTasks, plural(len(r.Sprints), "sprint", "sprints"))
+	}
+	items := make([]reportItem, len(r.Sprints))
+	for i, sprint := range r.Sprints {
+		items[i] = reportItem{
+			Text: fmt.Sprintf("%s to %s", sprint.From.Format("Jan 2"), sprint.To.Format("Jan 2")),
+			Detail: fmt.Sprintf("%d cards, %d tasks completed; %d carried over; %s tracked",
+				sprint.CompletedCards, sprint.CompletedTasks, sprint.CarriedOver, formatTracked(sprint.TrackedSeconds)),
+		}
+	}
+	doc.Sections = []reportSection{{Heading: "Sprints", Items: items}}
+	return doc
+}
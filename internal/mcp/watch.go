@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+const (
+	// defaultWatchTimeout and maxWatchTimeout bound how long watch_board waits for a change
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = 5 * time.Minute
+	// watchPollInterval is how often watch_board looks at the board while it waits
+	watchPollInterval = 5 * time.Second
+	// watchWriteMargin is the time left within the HTTP write timeout to send the result
+	watchWriteMargin = 10 * time.Second
+)
+
+// boardChange is a card or list that changed on a watched board
+type boardChange struct {
+	// Entity is "card" or "list"; Action "create", "update" or "delete"
+	Entity string `json:"entity"`
+	Action string `json:"action"`
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	// ListID and ListName are the list a card is in
+	ListID   string    `json:"listId,omitempty"`
+	ListName string    `json:"listName,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// boardWatch is the result of watch_board
+type boardWatch struct {
+	BoardID string `json:"boardId"`
+	// Changed is false when the wait timed out without a change
+	Changed bool          `json:"changed"`
+	Changes []boardChange `json:"changes"`
+	// Cursor is the since of the next call, so no change is missed or reported twice
+	Cursor time.Time `json:"cursor"`
+}
+
+func (s *Server) handleWatchBoard(ctx context.Context, args watchBoardArgs) (interface{}, error) {
+	// Planka keeps whole seconds, so a change in the second of the call must not count as before it
+	since := time.Now().UTC().Truncate(time.Second).Add(-time.Second)
+	if args.Since != "" {
+		t, err := time.Parse(time.RFC3339, args.Since)
+		if err != nil {
+			return nil, invalidParams("since must be an RFC 3339 timestamp, e.g. the cursor of the last call")
+		}
+		since = t
+	}
+	maxTimeout := s.maxWatchTimeout()
+	timeout := min(defaultWatchTimeout, maxTimeout)
+	if args.TimeoutSeconds != nil {
+		if *args.TimeoutSeconds < 0 || time.Duration(*args.TimeoutSeconds)*time.Second > maxTimeout {
+			return nil, invalidArgument("timeoutSeconds", "timeoutSeconds must be between 0 and %d", int(maxTimeout.Seconds()))
+		}
+		timeout = time.Duration(*args.TimeoutSeconds) * time.Second
+	}
+
+	// Deletions leave nothing to find on the board, so they are only known from the
+	// events Planka sends while the call waits
+	events := s.events.Subscribe(eventBacklog)
+	defer events.Close()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	// Cached responses would hide the changes made elsewhere in Planka until they expire
+	client := s.clientFor(ctx)
+	fresh := planka.WithFreshReads(ctx)
+	var deleted []boardChange
+	for {
+		result, err := boardChanges(fresh, client, args.BoardID, since)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, deleted...)
+		for _, change := range deleted {
+			if change.At.After(result.Cursor) {
+				result.Cursor = change.At
+			}
+		}
+		result.Changed = len(result.Changes) > 0
+		if result.Changed {
+			return result, nil
+		}
+
+		// Look again at the next poll, or as soon as Planka reports a change to the board
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-deadline.C:
+				return result, nil
+			case <-poll.C:
+				break wait
+			case event := <-events.C:
+				if event.BoardID != args.BoardID {
+					continue
+				}
+				if event.Action == "delete" && (event.Entity == "card" || event.Entity == "list") {
+					var item struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					}
+					json.Unmarshal(event.Item, &item)
+					deleted = append(deleted, boardChange{Entity: event.Entity, Action: "delete", ID: item.ID, Name: item.Name, At: time.Now().UTC()})
+				}
+				break wait
+			}
+		}
+	}
+}
+
+// maxWatchTimeout returns the longest watch_board may wait. Over HTTP it answers within the
+// write timeout, which cuts off a longer response without a reply.
+func (s *Server) maxWatchTimeout() time.Duration {
+	if s.writeTimeout > 0 {
+		return max(min(maxWatchTimeout, s.writeTimeout-watchWriteMargin), 0)
+	}
+	return maxWatchTimeout
+}
+
+// boardChanges returns the cards and lists of a board created or updated after since,
+// oldest change first
+func boardChanges(ctx context.Context, client PlankaClient, boardID string, since time.Time) (boardWatch, error) {
+	result := boardWatch{BoardID: boardID, Changes: []boardChange{}, Cursor: since}
+	lists, err := client.GetLists(ctx, boardID)
+	if err != nil {
+		return result, err
+	}
+	cards, err := client.GetBoardCards(ctx, boardID)
+	if err != nil {
+		return result, err
+	}
+
+	listNames := map[string]string{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+		if change, ok := entityChange("list", list.CreatedAt, list.UpdatedAt, since); ok {
+			change.ID, change.Name = list.ID, list.Name
+			result.Changes = append(result.Changes, change)
+		}
+	}
+	for _, card := range cards {
+		if change, ok := entityChange("card", card.CreatedAt, card.UpdatedAt, since); ok {
+			change.ID, change.Name = card.ID, card.Name
+			change.ListID, change.ListName = card.ListID, listNames[card.ListID]
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	sort.SliceStable(result.Changes, func(i, j int) bool { return result.Changes[i].At.Before(result.Changes[j].At) })
+	if n := len(result.Changes); n > 0 {
+		result.Cursor = result.Changes[n-1].At
+	}
+	return result, nil
+}
+
+// entityChange describes an entity created or last updated at the given times, if that
+// was after since
+func entityChange(entity string, created, updated time.Time, since time.Time) (boardChange, bool) {
+	switch {
+	case created.After(since):
+		return boardChange{Entity: entity, Action: "create", At: latest(created, updated)}, true
+	case updated.After(since):
+		return boardChange{Entity: entity, Action: "update", At: updated}, true
+	}
+	return boardChange{}, false
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// handlePlankaWebhook receives the events Planka's webhooks POST and publishes them on the
+// event bus, from where they reach connected clients as notifications. Planka sends the
+// access token configured on the webhook as a bearer token.
+func (h *httpServer) handlePlankaWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := requestAPIKey(r)
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.webhookToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="planka-mcp"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBody))
+	if err != nil {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	event, err := planka.ParseWebhook(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Events that do not change an entity are acknowledged and ignored
+	if event.Action == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Cached responses are stale now, as after a change over the socket
+	h.server.forgetEvent(event)
+	log.Printf("Planka webhook: %s on board %s", event.Name, event.BoardID)
+	h.server.publishEvent(event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// forgetEvent drops the cached responses event has made stale from every client of the
+// server: its own, each instance's and each tenant's, as an event does not tell which of
+// them hold the changed board
+func (s *Server) forgetEvent(event planka.Event) {
+	clients := []PlankaClient{s.client}
+	for _, client := range s.instances {
+		clients = append(clients, client)
+	}
+	if s.tenants != nil {
+		for _, client := range s.tenants.all() {
+			clients = append(clients, client)
+		}
+	}
+	for _, client := range clients {
+		if client, ok := client.(interface{ Forget(planka.Event) }); ok {
+			client.Forget(event)
+		}
+	}
+}
@@ -0,0 +1,663 @@
+// Package plankatest provides an in-memory fake of the Planka API for tests. It serves the
+// endpoints the Planka client uses with Planka's item/included response shapes, so tools
+// can be exercised end to end without a real instance.
+package plankatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+const (
+	// Token is the access token the fake server accepts
+	Token = "plankatest-token"
+	// Username and Password are the credentials the fake server accepts on login
+	Username = "demo"
+	Password = "demo"
+	// commentsPageSize is the number of comments per page, as in Planka
+	commentsPageSize = 50
+)
+
+// entity is a stored Planka entity in its JSON form
+type entity map[string]interface{}
+
+// Server is a fake Planka server backed by in-memory data
+type Server struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	nextID          int
+	projects        map[string]entity
+	boards          map[string]entity
+	lists           map[string]entity
+	cards           map[string]entity
+	tasks           map[string]entity
+	labels          map[string]entity
+	cardLabels      map[string]entity
+	cardMemberships map[string]entity
+	comments        map[string]entity
+	stopwatches     map[string]entity
+	requests        []string
+}
+
+// NewServer starts a fake Planka server that is closed when the test ends
+func NewServer(tb testing.TB) *Server {
+	s := &Server{
+		projects:        map[string]entity{},
+		boards:          map[string]entity{},
+		lists:           map[string]entity{},
+		cards:           map[string]entity{},
+		tasks:           map[string]entity{},
+		labels:          map[string]entity{},
+		cardLabels:      map[string]entity{},
+		cardMemberships: map[string]entity{},
+		comments:        map[string]entity{},
+		stopwatches:     map[string]entity{},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a Planka client authenticated against the fake server
+func (s *Server) Client(opts ...planka.Option) *planka.Client {
+	// Failures are reported right away rather than retried
+	opts = append([]planka.Option{planka.WithRetry(planka.RetryPolicy{Attempts: 1})}, opts...)
+	return planka.NewClient(s.URL, Token, opts...)
+}
+
+// Requests returns the requests served so far as "METHOD /path?query", in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// AddProject stores a project and returns its ID
+func (s *Server) AddProject(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.projects, entity{"name": name, "description": ""})
+}
+
+// AddBoard stores a board in a project and returns its ID
+func (s *Server) AddBoard(projectID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.boards, entity{"projectId": projectID, "name": name, "description": "", "position": 65535.0})
+}
+
+// AddList stores a list on a board and returns its ID
+func (s *Server) AddList(boardID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.lists, entity{"boardId": boardID, "name": name, "position": s.nextPosition(s.lists, "boardId", boardID)})
+}
+
+// AddCard stores a card in a list and returns its ID
+func (s *Server) AddCard(listID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.cards, entity{
+		"boardId":     s.lists[listID]["boardId"],
+		"listId":      listID,
+		"name":        name,
+		"description": "",
+		"position":    s.nextPosition(s.cards, "listId", listID),
+	})
+}
+
+// AddTask stores a task on a card and returns its ID
+func (s *Server) AddTask(cardID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.tasks, entity{"cardId": cardID, "name": name, "isCompleted": false, "position": s.nextPosition(s.tasks, "cardId", cardID)})
+}
+
+// AddLabel stores a label on a board and returns its ID
+func (s *Server) AddLabel(boardID, name, color string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.labels, entity{"boardId": boardID, "name": name, "color": color})
+}
+
+// AddComment stores a comment on a card and returns its ID
+func (s *Server) AddComment(cardID, text string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(s.comments, entity{"cardId": cardID, "userId": "1", "text": text})
+}
+
+// insert stores e in table under a new ID. It must be called with s.mu held.
+func (s *Server) insert(table map[string]entity, e entity) string {
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	now := time.Now().UTC().Format(time.RFC3339)
+	e["id"] = id
+	e["createdAt"] = now
+	e["updatedAt"] = now
+	table[id] = e
+	return id
+}
+
+// nextPosition returns a position after the last entity whose key equals value.
+// It must be called with s.mu held.
+func (s *Server) nextPosition(table map[string]entity, key, value string) float64 {
+	position := 0.0
+	for _, e := range table {
+		if e[key] == value {
+			if p, ok := e["position"].(float64); ok && p > position {
+				position = p
+			}
+		}
+	}
+	return position + 65535
+}
+
+// routes registers the fake API endpoints
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/access-tokens", s.login)
+	mux.HandleFunc("GET /api/users/me", s.authed(s.getMe))
+
+	mux.HandleFunc("GET /api/projects", s.authed(s.getProjects))
+	mux.HandleFunc("POST /api/projects", s.authed(s.createProject))
+	mux.HandleFunc("GET /api/projects/{id}", s.authed(s.getProject))
+	mux.HandleFunc("DELETE /api/projects/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.projects })))
+	mux.HandleFunc("POST /api/projects/{id}/boards", s.authed(s.createChild(func() map[string]entity { return s.boards }, "projectId", func() map[string]entity { return s.projects })))
+
+	mux.HandleFunc("GET /api/boards/{id}", s.authed(s.getBoard))
+	mux.HandleFunc("DELETE /api/boards/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.boards })))
+	mux.HandleFunc("POST /api/boards/{id}/lists", s.authed(s.createChild(func() map[string]entity { return s.lists }, "boardId", func() map[string]entity { return s.boards })))
+	mux.HandleFunc("POST /api/boards/{id}/labels", s.authed(s.createChild(func() map[string]entity { return s.labels }, "boardId", func() map[string]entity { return s.boards })))
+
+	mux.HandleFunc("GET /api/lists/{id}", s.authed(s.getOne(func() map[string]entity { return s.lists })))
+	mux.HandleFunc("PATCH /api/lists/{id}", s.authed(s.update(func() map[string]entity { return s.lists }, "name", "position")))
+	mux.HandleFunc("DELETE /api/lists/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.lists })))
+	mux.HandleFunc("POST /api/lists/{id}/cards", s.authed(s.createCard))
+
+	mux.HandleFunc("GET /api/cards/{id}", s.authed(s.getCard))
+	mux.HandleFunc("PATCH /api/cards/{id}", s.authed(s.updateCard))
+	mux.HandleFunc("DELETE /api/cards/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.cards })))
+	mux.HandleFunc("POST /api/cards/{id}/tasks", s.authed(s.createChild(func() map[string]entity { return s.tasks }, "cardId", func() map[string]entity { return s.cards })))
+	mux.HandleFunc("POST /api/cards/{id}/card-labels", s.authed(s.attach(func() map[string]entity { return s.cardLabels }, "labelId")))
+	mux.HandleFunc("POST /api/cards/{id}/card-memberships", s.authed(s.attach(func() map[string]entity { return s.cardMemberships }, "userId")))
+	mux.HandleFunc("GET /api/cards/{id}/comments", s.authed(s.getComments))
+	mux.HandleFunc("GET /api/cards/{id}/stopwatch", s.authed(s.stopwatch(nil)))
+	mux.HandleFunc("POST /api/cards/{id}/stopwatch/start", s.authed(s.stopwatch(startStopwatch)))
+	mux.HandleFunc("POST /api/cards/{id}/stopwatch/stop", s.authed(s.stopwatch(stopStopwatch)))
+	mux.HandleFunc("POST /api/cards/{id}/stopwatch/reset", s.authed(s.stopwatch(resetStopwatch)))
+	mux.HandleFunc("PATCH /api/cards/{id}/stopwatch", s.authed(s.setStopwatch))
+
+	mux.HandleFunc("PATCH /api/tasks/{id}", s.authed(s.update(func() map[string]entity { return s.tasks }, "name", "isCompleted", "position")))
+	mux.HandleFunc("DELETE /api/tasks/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.tasks })))
+
+	mux.HandleFunc("POST /api/comments", s.authed(s.createComment))
+	mux.HandleFunc("DELETE /api/comments/{id}", s.authed(s.deleteFrom(func() map[string]entity { return s.comments })))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		s.mu.Unlock()
+
+		if _, pattern := mux.Handler(r); pattern == "" {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Route not found")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authed rejects requests without the fake server's access token, like Planka does
+func (s *Server) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, "E_UNAUTHORIZED", "Access token is missing, invalid or expired")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// login exchanges the fake credentials for the access token
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		EmailOrUsername string `json:"emailOrUsername"`
+		Password        string `json:"password"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	if body.EmailOrUsername != Username || body.Password != Password {
+		writeError(w, http.StatusUnauthorized, "E_UNAUTHORIZED", "Invalid credentials")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"item": Token})
+}
+
+func (s *Server) getMe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{"item": demoUser()})
+}
+
+// demoUser is the only user of the fake server, the one the credentials log in as
+func demoUser() entity {
+	return entity{"id": "1", "email": "demo@example.com", "name": "Demo", "username": Username}
+}
+
+func (s *Server) getProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"items":    sorted(s.projects),
+		"included": map[string]interface{}{"boards": sorted(s.boards)},
+	})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r, "name")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.insert(s.projects, entity{"name": body["name"], "description": valueOr(body["description"], "")})
+	writeJSON(w, map[string]interface{}{"item": s.projects[id]})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, ok := s.projects[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Project not found")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"item":     project,
+		"included": map[string]interface{}{"boards": where(s.boards, "projectId", project["id"])},
+	})
+}
+
+func (s *Server) getBoard(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	board, ok := s.boards[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Board not found")
+		return
+	}
+	cards := where(s.cards, "boardId", board["id"])
+	var tasks, cardLabels, cardMemberships []entity
+	for _, card := range cards {
+		tasks = append(tasks, where(s.tasks, "cardId", card["id"])...)
+		cardLabels = append(cardLabels, where(s.cardLabels, "cardId", card["id"])...)
+		cardMemberships = append(cardMemberships, where(s.cardMemberships, "cardId", card["id"])...)
+	}
+	writeJSON(w, map[string]interface{}{
+		"item": board,
+		"included": map[string]interface{}{
+			"users":           []entity{demoUser()},
+			"lists":           where(s.lists, "boardId", board["id"]),
+			"cards":           cards,
+			"labels":          where(s.labels, "boardId", board["id"]),
+			"tasks":           nonNil(tasks),
+			"cardLabels":      nonNil(cardLabels),
+			"cardMemberships": nonNil(cardMemberships),
+		},
+	})
+}
+
+func (s *Server) getCard(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	card, ok := s.cards[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"item": card,
+		"included": map[string]interface{}{
+			"tasks":           where(s.tasks, "cardId", card["id"]),
+			"cardLabels":      where(s.cardLabels, "cardId", card["id"]),
+			"cardMemberships": where(s.cardMemberships, "cardId", card["id"]),
+			"attachments":     []entity{},
+		},
+	})
+}
+
+func (s *Server) createCard(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r, "name")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, ok := s.lists[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "List not found")
+		return
+	}
+	card := entity{
+		"boardId":     list["boardId"],
+		"listId":      list["id"],
+		"name":        body["name"],
+		"description": valueOr(body["description"], ""),
+		"position":    valueOr(body["position"], s.nextPosition(s.cards, "listId", list["id"].(string))),
+	}
+	if due, ok := body["dueDate"]; ok {
+		card["dueDate"] = due
+	}
+	id := s.insert(s.cards, card)
+	writeJSON(w, map[string]interface{}{"item": s.cards[id]})
+}
+
+func (s *Server) updateCard(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	card, ok := s.cards[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+		return
+	}
+	if listID, ok := body["listId"].(string); ok {
+		list, ok := s.lists[listID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "List not found")
+			return
+		}
+		card["boardId"] = list["boardId"]
+		if listID != card["listId"] {
+			card["listChangedAt"] = time.Now().UTC().Format(time.RFC3339)
+		}
+	}
+	for _, key := range []string{"name", "description", "listId", "position", "dueDate"} {
+		if value, ok := body[key]; ok {
+			card[key] = value
+		}
+	}
+	card["updatedAt"] = time.Now().UTC().Format(time.RFC3339)
+	writeJSON(w, map[string]interface{}{"item": card})
+}
+
+func (s *Server) getComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.cards[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+		return
+	}
+
+	// Comments are paged newest first; beforeId continues after the last one seen
+	comments := where(s.comments, "cardId", r.PathValue("id"))
+	sort.Slice(comments, func(i, j int) bool { return idOrder(comments[j]["id"]) < idOrder(comments[i]["id"]) })
+	if beforeID := r.URL.Query().Get("beforeId"); beforeID != "" {
+		before := idOrder(beforeID)
+		for len(comments) > 0 && idOrder(comments[0]["id"]) >= before {
+			comments = comments[1:]
+		}
+	}
+	if len(comments) > commentsPageSize {
+		comments = comments[:commentsPageSize]
+	}
+	writeJSON(w, map[string]interface{}{"items": comments, "included": map[string]interface{}{}})
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r, "cardId", "text")
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.cards[fmt.Sprint(body["cardId"])]; !ok {
+		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+		return
+	}
+	id := s.insert(s.comments, entity{"cardId": body["cardId"], "userId": "1", "text": body["text"]})
+	writeJSON(w, map[string]interface{}{"item": s.comments[id]})
+}
+
+// stopwatch serves a card's stopwatch, applying change to it first if given
+func (s *Server) stopwatch(change func(entity)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		cardID := r.PathValue("id")
+		if _, ok := s.cards[cardID]; !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+			return
+		}
+		watch, ok := s.stopwatches[cardID]
+		if !ok {
+			watch = entity{"id": cardID, "cardId": cardID, "duration": 0.0}
+			s.stopwatches[cardID] = watch
+		}
+		if change != nil {
+			change(watch)
+		}
+		writeJSON(w, map[string]interface{}{"item": watch})
+	}
+}
+
+func startStopwatch(watch entity) {
+	if watch["startedAt"] == nil {
+		watch["startedAt"] = time.Now().UTC().Format(time.RFC3339)
+	}
+}
+
+func stopStopwatch(watch entity) {
+	started, ok := watch["startedAt"].(string)
+	if !ok {
+		return
+	}
+	if t, err := time.Parse(time.RFC3339, started); err == nil {
+		watch["duration"] = watch["duration"].(float64) + time.Since(t).Round(time.Second).Seconds()
+	}
+	delete(watch, "startedAt")
+}
+
+func resetStopwatch(watch entity) {
+	delete(watch, "startedAt")
+	watch["duration"] = 0.0
+}
+
+// setStopwatch sets the duration of a card's stopwatch
+func (s *Server) setStopwatch(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeBody(w, r, "duration")
+	if !ok {
+		return
+	}
+	s.stopwatch(func(watch entity) { watch["duration"] = body["duration"] })(w, r)
+}
+
+// getOne serves a single entity of a table
+func (s *Server) getOne(table func() map[string]entity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e, ok := table()[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Not found")
+			return
+		}
+		writeJSON(w, map[string]interface{}{"item": e})
+	}
+}
+
+// createChild creates an entity under the parent named in the path, e.g. a board of a project
+func (s *Server) createChild(table func() map[string]entity, parentKey string, parents func() map[string]entity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r, "name")
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		parentID := r.PathValue("id")
+		if _, ok := parents()[parentID]; !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Not found")
+			return
+		}
+		e := entity{parentKey: parentID}
+		for key, value := range body {
+			e[key] = value
+		}
+		if _, ok := e["position"]; !ok {
+			e["position"] = s.nextPosition(table(), parentKey, parentID)
+		}
+		if parentKey == "cardId" {
+			e["isCompleted"] = false
+		}
+		id := s.insert(table(), e)
+		writeJSON(w, map[string]interface{}{"item": table()[id]})
+	}
+}
+
+// attach links the card in the path to the entity named by key, e.g. a label to a card
+func (s *Server) attach(table func() map[string]entity, key string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r, key)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		cardID := r.PathValue("id")
+		if _, ok := s.cards[cardID]; !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Card not found")
+			return
+		}
+		for _, e := range where(table(), "cardId", cardID) {
+			if e[key] == body[key] {
+				writeError(w, http.StatusConflict, "E_CONFLICT", "Already exists")
+				return
+			}
+		}
+		id := s.insert(table(), entity{"cardId": cardID, key: body[key]})
+		writeJSON(w, map[string]interface{}{"item": table()[id]})
+	}
+}
+
+// update patches the given fields of an entity
+func (s *Server) update(table func() map[string]entity, fields ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e, ok := table()[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Not found")
+			return
+		}
+		for _, key := range fields {
+			if value, ok := body[key]; ok {
+				e[key] = value
+			}
+		}
+		e["updatedAt"] = time.Now().UTC().Format(time.RFC3339)
+		writeJSON(w, map[string]interface{}{"item": e})
+	}
+}
+
+// deleteFrom deletes an entity and returns it
+func (s *Server) deleteFrom(table func() map[string]entity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := r.PathValue("id")
+		e, ok := table()[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "E_NOT_FOUND", "Not found")
+			return
+		}
+		delete(table(), id)
+		writeJSON(w, map[string]interface{}{"item": e})
+	}
+}
+
+// decodeBody decodes a JSON request body, rejecting it if a required field is missing
+func decodeBody(w http.ResponseWriter, r *http.Request, required ...string) (entity, bool) {
+	body := entity{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "E_MISSING_OR_INVALID_PARAMS", "Invalid JSON body")
+		return nil, false
+	}
+	for _, key := range required {
+		if _, ok := body[key]; !ok {
+			writeError(w, http.StatusBadRequest, "E_MISSING_OR_INVALID_PARAMS", "Missing "+key)
+			return nil, false
+		}
+	}
+	return body, true
+}
+
+// where returns the entities of table whose key equals value, in creation order
+func where(table map[string]entity, key string, value interface{}) []entity {
+	matches := []entity{}
+	for _, e := range sorted(table) {
+		if e[key] == value {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// sorted returns the entities of table in creation order
+func sorted(table map[string]entity) []entity {
+	entities := make([]entity, 0, len(table))
+	for _, e := range table {
+		entities = append(entities, e)
+	}
+	sort.Slice(entities, func(i, j int) bool { return idOrder(entities[i]["id"]) < idOrder(entities[j]["id"]) })
+	return entities
+}
+
+// idOrder returns the creation order of an ID
+func idOrder(id interface{}) int {
+	n, _ := strconv.Atoi(fmt.Sprint(id))
+	return n
+}
+
+// valueOr returns value, or fallback if it is nil
+func valueOr(value, fallback interface{}) interface{} {
+	if value == nil {
+		return fallback
+	}
+	return value
+}
+
+// nonNil returns entities, or an empty slice if it is nil so it encodes as []
+func nonNil(entities []entity) []entity {
+	if entities == nil {
+		return []entity{}
+	}
+	return entities
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Planka error response
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": code, "message": message})
+}
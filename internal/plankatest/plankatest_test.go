@@ -0,0 +1,121 @@
+package plankatest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestClientRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(t)
+	client := server.Client()
+
+	project, err := client.CreateProject(ctx, planka.CreateProjectRequest{Name: "Launch"})
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	board, err := client.CreateBoard(ctx, planka.CreateBoardRequest{Name: "Roadmap", ProjectID: project.ID})
+	if err != nil {
+		t.Fatalf("CreateBoard: %v", err)
+	}
+	todo, err := client.CreateList(ctx, planka.CreateListRequest{Name: "Todo", BoardID: board.ID})
+	if err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	done, err := client.CreateList(ctx, planka.CreateListRequest{Name: "Done", BoardID: board.ID})
+	if err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	card, err := client.CreateCard(ctx, planka.CreateCardRequest{Name: "Write docs", ListID: todo.ID})
+	if err != nil {
+		t.Fatalf("CreateCard: %v", err)
+	}
+
+	boards, err := client.GetBoards(ctx, project.ID)
+	if err != nil || len(boards) != 1 || boards[0].ID != board.ID {
+		t.Fatalf("GetBoards = %v, %v; want board %s", boards, err, board.ID)
+	}
+	lists, err := client.GetLists(ctx, board.ID)
+	if err != nil || len(lists) != 2 {
+		t.Fatalf("GetLists = %v, %v; want 2 lists", lists, err)
+	}
+
+	if _, err := client.MoveCard(ctx, card.ID, done.ID, 1); err != nil {
+		t.Fatalf("MoveCard: %v", err)
+	}
+	cards, err := client.GetCards(ctx, done.ID)
+	if err != nil || len(cards) != 1 || cards[0].ID != card.ID {
+		t.Fatalf("GetCards(done) = %v, %v; want card %s", cards, err, card.ID)
+	}
+	cards, err = client.GetCards(ctx, todo.ID)
+	if err != nil || len(cards) != 0 {
+		t.Fatalf("GetCards(todo) = %v, %v; want no cards", cards, err)
+	}
+
+	task, err := client.CreateTask(ctx, planka.CreateTaskRequest{Name: "Outline", CardID: card.ID})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	completed := true
+	if _, err := client.UpdateTask(ctx, task.ID, planka.UpdateTaskRequest{IsCompleted: &completed}); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	tasks, err := client.GetTasks(ctx, card.ID)
+	if err != nil || len(tasks) != 1 || !tasks[0].IsCompleted {
+		t.Fatalf("GetTasks = %v, %v; want one completed task", tasks, err)
+	}
+
+	if err := client.DeleteCard(ctx, card.ID); err != nil {
+		t.Fatalf("DeleteCard: %v", err)
+	}
+	_, err = client.GetCard(ctx, card.ID)
+	var apiErr *planka.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Entity() != "card" {
+		t.Fatalf("GetCard after delete = %v; want a 404 APIError for a card", err)
+	}
+}
+
+func TestCommentsArePaged(t *testing.T) {
+	server := NewServer(t)
+	project := server.AddProject("Project")
+	board := server.AddBoard(project, "Board")
+	list := server.AddList(board, "List")
+	card := server.AddCard(list, "Card")
+	for i := 0; i < 120; i++ {
+		server.AddComment(card, fmt.Sprintf("comment %d", i))
+	}
+
+	comments, err := server.Client().GetComments(context.Background(), card)
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	if len(comments) != 120 {
+		t.Errorf("GetComments returned %d comments, want 120", len(comments))
+	}
+
+	pages := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "GET /api/cards/"+card+"/comments") {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages of comments, want 3: %v", pages, server.Requests())
+	}
+}
+
+func TestRejectsUnknownToken(t *testing.T) {
+	server := NewServer(t)
+	_, err := planka.NewClient(server.URL, "wrong", planka.WithRetry(planka.RetryPolicy{Attempts: 1})).GetProjects(context.Background())
+
+	var apiErr *planka.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("GetProjects with a wrong token = %v; want a 401 APIError", err)
+	}
+}
@@ -0,0 +1,26 @@
+// Package version holds the build's version metadata
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is the release of planka-mcp. Release builds set it with
+// -ldflags "-X github.com/ayushgarg/mcp-planka/internal/version.Version=v1.2.3";
+// otherwise the module version recorded by go install is used when there is one.
+var Version = "1.0.0"
+
+func init() {
+	if Version != "1.0.0" {
+		return
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+}
+
+// UserAgent identifies planka-mcp in requests it sends, e.g. "planka-mcp/1.0.0 (go1.25.0)"
+func UserAgent() string {
+	return "planka-mcp/" + Version + " (" + runtime.Version() + ")"
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ayushgarg/mcp-planka/internal/mcp"
-	"github.com/ayushgarg/mcp-planka/internal/planka"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
 )
 
 func main() {
@@ -14,55 +24,575 @@ func main() {
 	httpMode := flag.Bool("http", false, "Run in HTTP server mode instead of stdio")
 	httpPort := flag.Int("http-port", 8080, "HTTP server port (only used with --http)")
 	httpAddr := flag.String("http-addr", "0.0.0.0", "HTTP server bind address (only used with --http)")
+	stdioMode := flag.Bool("stdio", false, "Also serve stdio when running the HTTP server, sharing one server between both transports")
+	unixSocket := flag.String("unix-socket", "", "Serve the HTTP transport on this Unix domain socket instead of a TCP port (implies --http)")
+	retryAttempts := flag.Int("planka-retry-attempts", 3, "Attempts for idempotent Planka requests failing with a network error or 5xx response (1 disables retries)")
+	retryDelay := flag.Duration("planka-retry-delay", 250*time.Millisecond, "Delay before the first retry of a Planka request; doubles with every further retry")
+	retryJitter := flag.Float64("planka-retry-jitter", 0.2, "Fraction (0-1) by which retry delays are randomized")
+	rateLimitWait := flag.Duration("planka-rate-limit-wait", 30*time.Second, "How long a request may wait when Planka responds 429 Too Many Requests before failing")
+	plankaChaos := flag.String("planka-chaos", "", "Testing only: inject latency and failures into Planka requests, e.g. latency=200ms,error-rate=0.1,html-rate=0.05,seed=42")
+	instancesFile := flag.String("instances", "", "JSON config file of named Planka instances tools can target (replaces the PLANKA_* environment variables)")
+	multiTenant := flag.Bool("multi-tenant", false, "Act on Planka as each HTTP caller, using the Planka token from their X-Planka-Token header")
+	tokenPassthrough := flag.Bool("planka-token-passthrough", false, "Also accept the caller's Planka token as an Authorization bearer token (only used with --multi-tenant)")
+	basePath := flag.String("base-path", "", "Serve the HTTP endpoints under this path prefix, e.g. /planka for /planka/mcp (only used with --http)")
+	httpSessionTimeout := flag.Duration("http-session-timeout", 30*time.Minute, "Close HTTP sessions idle for longer than this (0 keeps them forever)")
+	httpMaxSessions := flag.Int("http-max-sessions", 1000, "Maximum number of concurrent HTTP sessions (0 for unlimited)")
+	httpMaxBody := flag.Int64("http-max-body-size", 1<<20, "Maximum HTTP request body size in bytes")
+	httpReadHeaderTimeout := flag.Duration("http-read-header-timeout", 10*time.Second, "Maximum time to read HTTP request headers (0 disables)")
+	httpReadTimeout := flag.Duration("http-read-timeout", 30*time.Second, "Maximum time to read an entire HTTP request (0 disables)")
+	httpWriteTimeout := flag.Duration("http-write-timeout", 2*time.Minute, "Maximum time to write an HTTP response, except the GET /mcp event stream (0 disables)")
+	httpIdleTimeout := flag.Duration("http-idle-timeout", 2*time.Minute, "Close idle keep-alive HTTP connections after this long (0 disables)")
+	httpAPIKeys := flag.String("http-api-keys", "", "Comma-separated API keys required on the HTTP MCP endpoint (default: $MCP_API_KEYS)")
+	calendarTokens := flag.String("calendar-tokens", "", "Comma-separated tokens enabling the /calendar/{boardId}.ics due date feeds (default: $MCP_CALENDAR_TOKENS)")
+	oauthIssuer := flag.String("oauth-issuer", "", "OAuth authorization server issuer URL; enables OAuth on the HTTP MCP endpoint")
+	oauthResource := flag.String("oauth-resource", "", "Canonical URL of this MCP server that access tokens must be issued for, e.g. https://mcp.example.com/mcp")
+	oauthIntrospection := flag.String("oauth-introspection-url", "", "Token introspection endpoint (default: discovered from the issuer metadata)")
+	oauthScopes := flag.String("oauth-scopes", "", "Comma-separated scopes required on access tokens")
+	oauthAllowNoAudience := flag.Bool("oauth-allow-missing-audience", false, "Accept access tokens without an audience, for authorization servers that do not set one (tokens with an audience must still include --oauth-resource)")
+	rateLimit := flag.Float64("rate-limit", 0, "Requests per second allowed per HTTP client, keyed by API key, OAuth user or session (default: unlimited)")
+	rateBurst := flag.Int("rate-burst", 20, "Requests an HTTP client may send at once (only used with --rate-limit)")
+	logLevel := flag.String("log-level", "info", "Log verbosity: debug, info, warn or error")
+	logBodies := flag.Bool("log-bodies", false, "Include redacted request params and results in request logs (requires --log-level debug)")
+	logFile := flag.String("log-file", "", "Also write logs to this file, e.g. in stdio mode where stderr is not kept")
+	logMaxSize := flag.Int("log-max-size", 10, "Size in megabytes at which the --log-file is rotated (0 disables rotation)")
+	logMaxBackups := flag.Int("log-max-backups", 3, "Number of rotated --log-file files to keep, as FILE.1 (newest) to FILE.N")
+	maxResultSize := flag.Int("max-result-size", 100000, "Maximum size in bytes of a tool result before it is truncated (0 for unlimited)")
+	dueReminders := flag.Duration("due-reminders", 0, "Notify clients about cards due within this window, e.g. 24h (disabled by default)")
+	dueReminderInterval := flag.Duration("due-reminder-interval", 5*time.Minute, "How often to check for cards coming due (only used with --due-reminders)")
+	recurrencesFile := flag.String("recurrences-file", "", "JSON state file of recurring cards; enables the add_recurrence, list_recurrences and remove_recurrence tools and the scheduler that creates the cards")
+	recurrenceInterval := flag.Duration("recurrence-interval", time.Minute, "How often to check for recurring cards that are due (only used with --recurrences-file)")
+	velocityFile := flag.String("velocity-file", "", "JSON state file sprint_report records each sprint in; enables the get_velocity tool")
+	sortedListsFile := flag.String("sorted-lists-file", "", "JSON state file of the lists auto_sort_list keeps sorted; enables its keepSorted argument and the background sorter")
+	sortInterval := flag.Duration("sort-interval", time.Minute, "How often to re-sort the lists kept sorted (only used with --sorted-lists-file)")
+	rulesFile := flag.String("rules-file", "", "JSON state file of rules applied to the cards created and updated through the server; enables the add_rule, list_rules and remove_rule tools")
+	rulesOnEvents := flag.Bool("rules-on-events", false, "Also apply the rules to card changes received from Planka over --watch-boards or its webhooks (only used with --rules-file)")
+	snapshotsDir := flag.String("snapshots-dir", "", "Directory snapshot_board saves board snapshots in, for diff_board_snapshots to compare by ID")
+	doneLists := flag.String("done-lists", "", "Comma-separated IDs of done lists cleanup_done_cards cleans up by default")
+	cleanupDoneAfter := flag.Duration("cleanup-done-after", 0, "Clean up cards of --done-lists unchanged for this long in the background, e.g. 720h (disabled by default)")
+	cleanupDoneAction := flag.String("cleanup-done-action", "archive", "What the background cleanup does with stale done cards: archive or delete")
+	cleanupInterval := flag.Duration("cleanup-interval", time.Hour, "How often the background cleanup checks the done lists (only used with --cleanup-done-after)")
+	webhookURLs := flag.String("webhook-urls", "", "Comma-separated URLs that receive a JSON event for every change agents make through the server (default: $MCP_WEBHOOK_URLS)")
+	watchBoards := flag.String("watch-boards", "", "Comma-separated board IDs to follow over Planka's realtime socket; their changes are pushed to clients as notifications")
+	envFile := flag.String("env-file", "", "File of KEY=VALUE environment variables to load before reading the configuration (default: .env in the working directory, if present)")
+	configFile := flag.String("config", "", "JSON config file with defaults for the Planka connection, HTTP server, read-only mode, tool filters and any other flag; environment variables and flags override it")
+	readOnly := flag.Bool("read-only", false, "Expose only the tools that do not change Planka")
+	dryRun := flag.Bool("dry-run", false, "Make the tools that change Planka return the API requests they would send instead of sending them")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated origins browsers may call the HTTP endpoints from (default: any origin)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output())
+		printCommands(flag.CommandLine.Output())
+	}
 	flag.Parse()
 
-	// Check if we should run tests instead
-	if len(flag.Args()) > 0 && flag.Args()[0] == "test" {
-		RunTests()
-		return
+	// Variables from a .env file fill in those not set in the environment, as desktop MCP
+	// hosts make it awkward to pass many of them
+	if *envFile != "" {
+		if err := loadEnvFile(*envFile); err != nil {
+			log.Fatalf("Failed to load env file: %v", err)
+		}
+	} else if err := loadEnvFile(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("Failed to load .env: %v", err)
+	}
+
+	// The config file only fills in the settings not given as flags or environment variables
+	var config *fileConfig
+	if *configFile != "" {
+		var err error
+		if config, err = loadConfig(*configFile); err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		if err := config.apply(flag.CommandLine); err != nil {
+			log.Fatalf("Invalid config %s: %v", *configFile, err)
+		}
+	}
+	inlineInstances := config != nil && config.Instances != nil && *instancesFile == ""
+
+	// Logs go to stderr so they never interfere with the stdio transport
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("Invalid --log-level %q: %v", *logLevel, err)
+	}
+	var logOutput io.Writer = os.Stderr
+	if *logFile != "" {
+		file, err := openLogFile(*logFile, int64(*logMaxSize)<<20, *logMaxBackups)
+		if err != nil {
+			log.Fatalf("Failed to open --log-file: %v", err)
+		}
+		logOutput = io.MultiWriter(os.Stderr, file)
+	}
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+
+	// PLANKA_PROXY overrides the standard proxy environment variables for Planka requests
+	var plankaProxy *url.URL
+	if proxy := os.Getenv("PLANKA_PROXY"); proxy != "" {
+		parsed, err := url.Parse(proxy)
+		if err != nil || parsed.Host == "" {
+			log.Fatalf("Invalid PLANKA_PROXY %q: expected a URL such as http://proxy.example.com:3128", proxy)
+		}
+		plankaProxy = parsed
+	}
+
+	// PLANKA_CA_CERT trusts an internal CA in addition to the system ones
+	var plankaCAs *x509.CertPool
+	if caFile := os.Getenv("PLANKA_CA_CERT"); caFile != "" {
+		pool, err := planka.LoadCertPool(caFile)
+		if err != nil {
+			log.Fatalf("Invalid PLANKA_CA_CERT: %v", err)
+		}
+		plankaCAs = pool
+	}
+	skipVerify := os.Getenv("PLANKA_TLS_SKIP_VERIFY") == "true"
+	if skipVerify {
+		log.Println("Warning: PLANKA_TLS_SKIP_VERIFY is set; Planka's TLS certificate is not verified")
+	}
+
+	// Requests of all Planka clients are counted together for /metrics and get_server_stats
+	metrics := planka.NewMetrics()
+	clientOpts := []planka.Option{
+		planka.WithMetrics(metrics),
+		planka.WithRetry(planka.RetryPolicy{
+			Attempts:         *retryAttempts,
+			BaseDelay:        *retryDelay,
+			Jitter:           *retryJitter,
+			MaxRateLimitWait: *rateLimitWait,
+		}),
+		planka.WithTimeout(envDuration("PLANKA_TIMEOUT", 30*time.Second)),
+		planka.WithTransport(planka.TransportOptions{
+			MaxIdleConnsPerHost: envInt("PLANKA_MAX_IDLE_CONNS_PER_HOST", 10),
+			DisableKeepAlives:   os.Getenv("PLANKA_DISABLE_KEEP_ALIVES") == "true",
+			Proxy:               plankaProxy,
+			RootCAs:             plankaCAs,
+			InsecureSkipVerify:  skipVerify,
+		}),
+		planka.WithCache(envDuration("PLANKA_CACHE_TTL", 0)),
+		planka.WithMaxItems(envInt("PLANKA_MAX_ITEMS", 10000)),
+	}
+	// Boards are only kept when asked for, as kept boards miss changes made outside the
+	// server; without a window of their own they are kept as long as PLANKA_CACHE_TTL says
+	if os.Getenv("PLANKA_BOARD_HYDRATION_WINDOW") != "" {
+		clientOpts = append(clientOpts, planka.WithBoardHydration(envDuration("PLANKA_BOARD_HYDRATION_WINDOW", 0)))
+	}
+	// --planka-chaos exercises retries, timeouts and error mapping against a misbehaving Planka
+	if *plankaChaos != "" {
+		chaos, err := planka.ParseChaosOptions(*plankaChaos)
+		if err != nil {
+			log.Fatalf("Invalid --planka-chaos: %v", err)
+		}
+		log.Printf("Warning: --planka-chaos is set; Planka requests are delayed by %s and %.0f%% of them fail", chaos.Latency, (chaos.ErrorRate+chaos.HTMLRate)*100)
+		clientOpts = append(clientOpts, planka.WithChaos(chaos))
+	}
+	// PLANKA_DEBUG logs the HTTP traffic to Planka, e.g. to find out why it answers with HTML
+	if os.Getenv("PLANKA_DEBUG") == "1" || os.Getenv("PLANKA_DEBUG") == "true" {
+		clientOpts = append(clientOpts, planka.WithDebugLog(planka.DebugOptions{
+			Logger:       logger,
+			Bodies:       os.Getenv("PLANKA_DEBUG_BODIES") == "1" || os.Getenv("PLANKA_DEBUG_BODIES") == "true",
+			MaxBodyBytes: envInt("PLANKA_DEBUG_BODY_BYTES", 2048),
+		}))
+	}
+
+	// init asks for the Planka settings, so it runs before they must be given
+	if len(flag.Args()) > 0 && flag.Args()[0] == "init" {
+		os.Exit(runInit(os.Stdin, os.Stdout, clientOpts))
+	}
+	// doctor diagnoses the Planka settings, so it runs before they must be valid
+	if len(flag.Args()) > 0 && flag.Args()[0] == "doctor" {
+		var doctorInstances *instancesConfig
+		doctorPath := *instancesFile
+		if inlineInstances {
+			doctorInstances, doctorPath = config.Instances, *configFile
+		} else if *instancesFile != "" {
+			loaded, err := readInstances(*instancesFile)
+			if err != nil {
+				log.Fatalf("Failed to load Planka instances: %v", err)
+			}
+			doctorInstances = &loaded
+		}
+		os.Exit(runDoctor(os.Stdout, doctorInstances, doctorPath, clientOpts))
 	}
 
 	// Get configuration from environment variables
 	plankaURL := os.Getenv("PLANKA_URL")
-	if plankaURL == "" {
+	if plankaURL == "" && *instancesFile == "" && !inlineInstances {
 		log.Fatal("PLANKA_URL environment variable is required")
 	}
+	if plankaURL != "" && *instancesFile == "" && !inlineInstances {
+		normalized, err := planka.NormalizeBaseURL(plankaURL)
+		if err != nil {
+			log.Fatalf("Invalid PLANKA_URL: %v", err)
+		}
+		plankaURL = normalized
+	}
+	// PLANKA_PROBE checks at startup that the URL serves the Planka API
+	probe := os.Getenv("PLANKA_PROBE") == "true"
 
 	var client *planka.Client
+	var instances map[string]*planka.Client
+	var defaultInstance string
 	var err error
 
-	// Try token authentication first, then username/password
+	// Use the configured instances, or try token authentication first, then username/password
 	plankaToken := os.Getenv("PLANKA_TOKEN")
-	if plankaToken != "" {
-		client = planka.NewClient(plankaURL, plankaToken)
+	if *instancesFile != "" || inlineInstances {
+		if *multiTenant {
+			log.Fatal("--multi-tenant cannot be combined with --instances")
+		}
+		if inlineInstances {
+			instances, defaultInstance, err = connectInstances(*config.Instances, *configFile, probe, clientOpts...)
+		} else {
+			instances, defaultInstance, err = loadInstances(*instancesFile, probe, clientOpts...)
+		}
+		if err != nil {
+			log.Fatalf("Failed to load Planka instances: %v", err)
+		}
+		client = instances[defaultInstance]
+		log.Printf("Loaded %d Planka instances, default %q", len(instances), defaultInstance)
+	} else if plankaToken != "" {
+		if probe {
+			probePlanka(plankaURL, clientOpts)
+		}
+		client = planka.NewClient(plankaURL, plankaToken, clientOpts...)
 	} else {
 		// Try username/password authentication
 		username := os.Getenv("PLANKA_USERNAME")
 		password := os.Getenv("PLANKA_PASSWORD")
-		if username == "" || password == "" {
+		if probe {
+			probePlanka(plankaURL, clientOpts)
+		}
+		if username != "" && password != "" {
+			passwordOpts := clientOpts
+			if os.Getenv("PLANKA_AUTH_MODE") == "cookie" {
+				passwordOpts = append(passwordOpts[:len(passwordOpts):len(passwordOpts)], planka.WithCookieAuth())
+			}
+			client, err = planka.NewClientWithPassword(plankaURL, username, password, passwordOpts...)
+			if err != nil {
+				log.Fatalf("Failed to authenticate with username/password: %v", err)
+			}
+			log.Println("Successfully authenticated with username/password")
+		} else if !*multiTenant {
+			// In multi-tenant mode the server's own credentials are optional
 			log.Fatal("Either PLANKA_TOKEN or both PLANKA_USERNAME and PLANKA_PASSWORD environment variables are required")
 		}
-		client, err = planka.NewClientWithPassword(plankaURL, username, password)
-		if err != nil {
-			log.Fatalf("Failed to authenticate with username/password: %v", err)
+	}
+
+	// Subcommands such as "projects list" act on Planka directly instead of serving MCP
+	if isCommand(flag.Args()) {
+		os.Exit(runCommand(client, flag.Args()))
+	}
+
+	// With systemd socket activation the HTTP transport serves the socket systemd passed
+	activated, err := systemdListener()
+	if err != nil {
+		log.Fatalf("Invalid systemd socket activation: %v", err)
+	}
+	if activated != nil {
+		*httpMode = true
+	}
+
+	if *multiTenant && !*httpMode && *unixSocket == "" {
+		log.Fatal("--multi-tenant requires --http")
+	}
+	// Notifications go to every session, so in multi-tenant mode they would show each tenant
+	// the boards of the server's own account
+	if *multiTenant && *dueReminders > 0 {
+		log.Fatal("--due-reminders cannot be combined with --multi-tenant")
+	}
+	if *multiTenant && *watchBoards != "" {
+		log.Fatal("--watch-boards cannot be combined with --multi-tenant")
+	}
+	if *dueReminders > 0 && client == nil {
+		log.Fatal("--due-reminders requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+	}
+	if *recurrencesFile != "" && client == nil {
+		log.Fatal("--recurrences-file requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+	}
+	if *sortedListsFile != "" && client == nil {
+		log.Fatal("--sorted-lists-file requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+	}
+	if *rulesOnEvents && client == nil {
+		log.Fatal("--rules-on-events requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+	}
+	if *cleanupDoneAfter > 0 {
+		if client == nil {
+			log.Fatal("--cleanup-done-after requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
+		}
+		if *doneLists == "" {
+			log.Fatal("--cleanup-done-after requires --done-lists")
 		}
-		log.Println("Successfully authenticated with username/password")
+		if *cleanupDoneAction != "archive" && *cleanupDoneAction != "delete" {
+			log.Fatalf("Invalid --cleanup-done-action %q: expected archive or delete", *cleanupDoneAction)
+		}
+	}
+	if *watchBoards != "" && client == nil {
+		log.Fatal("--watch-boards requires PLANKA_TOKEN or PLANKA_USERNAME and PLANKA_PASSWORD")
 	}
 
 	// Initialize MCP server
 	server := mcp.NewServer(client)
+	if len(instances) > 0 {
+		named := make(map[string]mcp.PlankaClient, len(instances))
+		for name, instance := range instances {
+			named[name] = instance
+		}
+		server.SetInstances(named, defaultInstance)
+	}
+	server.SetMetrics(metrics)
+	server.EnableRequestLogging(mcp.LoggingOptions{
+		Logger: logger,
+		Bodies: *logBodies,
+	})
+	server.LimitResultSize(*maxResultSize)
+	// PLANKA_TIMEZONE is where due date phrases such as "tomorrow 5pm" are interpreted
+	if tz := os.Getenv("PLANKA_TIMEZONE"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			log.Fatalf("Invalid PLANKA_TIMEZONE %q: %v", tz, err)
+		}
+		server.SetTimezone(loc)
+	}
+
+	// Optionally mirror agent activity to chat or an audit system. URLs and the signing
+	// secret can come from the environment, as chat webhook URLs embed a secret
+	urls := *webhookURLs
+	if urls == "" {
+		urls = os.Getenv("MCP_WEBHOOK_URLS")
+	}
+	if webhooks := splitList(urls); len(webhooks) > 0 {
+		server.SetWebhooks(mcp.WebhookOptions{
+			URLs:   webhooks,
+			Secret: os.Getenv("MCP_WEBHOOK_SECRET"),
+		})
+		log.Printf("Sending changes made through the server to %d webhooks", len(webhooks))
+	}
+
+	// import_gitlab_issues falls back to these when a call names no token or instance
+	server.SetGitLab(os.Getenv("GITLAB_URL"), os.Getenv("GITLAB_TOKEN"))
+	server.SetSnapshotsDir(*snapshotsDir)
+
+	// Operators can narrow the toolset, e.g. to card and task tools only
+	if *readOnly {
+		server.SetReadOnly()
+		log.Println("Read-only mode: tools that change Planka are hidden")
+	}
+	if *dryRun {
+		server.SetDryRun()
+		log.Println("Dry-run mode: tools report the changes they would make to Planka without making them")
+	}
+	toolsAllow := splitList(os.Getenv("PLANKA_MCP_TOOLS_ALLOW"))
+	toolsDeny := splitList(os.Getenv("PLANKA_MCP_TOOLS_DENY"))
+	if len(toolsAllow) > 0 || len(toolsDeny) > 0 {
+		if err := server.FilterTools(toolsAllow, toolsDeny); err != nil {
+			log.Fatalf("Invalid PLANKA_MCP_TOOLS_ALLOW/PLANKA_MCP_TOOLS_DENY: %v", err)
+		}
+	}
+
+	// Optionally push reminders for cards coming due
+	if *dueReminders > 0 {
+		log.Printf("Due-date reminders enabled for cards due within %s", *dueReminders)
+		server.StartReminders(context.Background(), *dueReminders, *dueReminderInterval)
+	}
+
+	// Optionally archive or delete done cards nobody has touched for a while
+	server.SetDoneLists(splitList(*doneLists))
+	if *cleanupDoneAfter > 0 {
+		log.Printf("Cleaning up done cards unchanged for %s (%s)", *cleanupDoneAfter, *cleanupDoneAction)
+		server.StartCleanup(context.Background(), mcp.CleanupOptions{
+			OlderThan: *cleanupDoneAfter,
+			Delete:    *cleanupDoneAction == "delete",
+			Interval:  *cleanupInterval,
+		})
+	}
+
+	// Optionally recreate cards on a schedule, e.g. a weekly review every Monday
+	if *recurrencesFile != "" {
+		if err := server.EnableRecurrences(*recurrencesFile); err != nil {
+			log.Fatalf("Failed to load recurrences: %v", err)
+		}
+		server.StartRecurrences(context.Background(), *recurrenceInterval)
+		log.Printf("Recurring cards enabled, stored in %s", *recurrencesFile)
+	}
+	if *velocityFile != "" {
+		if err := server.EnableVelocity(*velocityFile); err != nil {
+			log.Fatalf("Failed to load velocity: %v", err)
+		}
+		log.Printf("Velocity tracking enabled, stored in %s", *velocityFile)
+	}
+	if *sortedListsFile != "" {
+		if err := server.EnableSortedLists(*sortedListsFile); err != nil {
+			log.Fatalf("Failed to load sorted lists: %v", err)
+		}
+		server.StartSortedLists(context.Background(), *sortInterval)
+		log.Printf("Sorted lists enabled, stored in %s", *sortedListsFile)
+	}
+	if *rulesFile != "" {
+		if err := server.EnableRules(*rulesFile); err != nil {
+			log.Fatalf("Failed to load rules: %v", err)
+		}
+		if *rulesOnEvents {
+			server.StartRuleEvents(context.Background())
+		}
+		log.Printf("Rules enabled, stored in %s", *rulesFile)
+	}
+
+	// Optionally follow boards live; changes also invalidate cached Planka responses
+	if boardIDs := splitList(*watchBoards); len(boardIDs) > 0 {
+		realtime := planka.NewRealtime(client)
+		for _, boardID := range boardIDs {
+			realtime.Watch(context.Background(), boardID)
+		}
+		go realtime.Run(context.Background())
+		server.StartBoardEvents(context.Background(), realtime)
+		log.Printf("Watching %d boards over Planka's realtime socket", len(boardIDs))
+	}
 
 	// Start the MCP server in the appropriate mode
-	if *httpMode {
-		log.Printf("Starting HTTP server on %s:%d", *httpAddr, *httpPort)
-		if err := server.StartHTTP(*httpAddr, *httpPort); err != nil {
+	if *httpMode || *unixSocket != "" {
+		// API keys can come from the flag or the environment so they stay out of process listings
+		apiKeys := *httpAPIKeys
+		if apiKeys == "" {
+			apiKeys = os.Getenv("MCP_API_KEYS")
+		}
+
+		// The webhook token comes from the environment to keep it out of process listings
+		webhookToken := os.Getenv("PLANKA_WEBHOOK_TOKEN")
+
+		calendar := *calendarTokens
+		if calendar == "" {
+			calendar = os.Getenv("MCP_CALENDAR_TOKENS")
+		}
+
+		if *multiTenant {
+			if webhookToken != "" {
+				log.Fatal("PLANKA_WEBHOOK_TOKEN cannot be combined with --multi-tenant: webhook events are sent to every session")
+			}
+			// API keys and OAuth tokens arrive in the Authorization header too
+			if *tokenPassthrough && (apiKeys != "" || *oauthIssuer != "") {
+				log.Fatal("--planka-token-passthrough cannot be combined with API keys or OAuth")
+			}
+			log.Printf("Multi-tenant mode: Planka requests act as the caller")
+			server.EnableMultiTenant(mcp.MultiTenantOptions{
+				BaseURL:       plankaURL,
+				Passthrough:   *tokenPassthrough,
+				ClientOptions: clientOpts,
+			})
+		}
+
+		httpOpts := mcp.HTTPOptions{
+			APIKeys:           splitList(apiKeys),
+			SessionTimeout:    *httpSessionTimeout,
+			MaxSessions:       *httpMaxSessions,
+			MaxBodyBytes:      *httpMaxBody,
+			ReadHeaderTimeout: *httpReadHeaderTimeout,
+			ReadTimeout:       *httpReadTimeout,
+			WriteTimeout:      *httpWriteTimeout,
+			IdleTimeout:       *httpIdleTimeout,
+			BasePath:          *basePath,
+			UnixSocket:        *unixSocket,
+			Listener:          activated,
+			Ready:             notifyReady,
+			CalendarTokens:    splitList(calendar),
+			WebhookToken:      webhookToken,
+			CORSOrigins:       splitList(*corsOrigins),
+		}
+		if *rateLimit > 0 {
+			httpOpts.RateLimit = &mcp.RateLimitOptions{
+				Rate:  *rateLimit,
+				Burst: *rateBurst,
+			}
+		}
+		if *oauthIssuer != "" {
+			if *oauthResource == "" {
+				log.Fatal("--oauth-resource is required with --oauth-issuer")
+			}
+			// Introspection client credentials come from the environment to keep the secret out of process listings
+			httpOpts.OAuth = &mcp.OAuthOptions{
+				Issuer:               *oauthIssuer,
+				ResourceURL:          *oauthResource,
+				IntrospectionURL:     *oauthIntrospection,
+				ClientID:             os.Getenv("OAUTH_CLIENT_ID"),
+				ClientSecret:         os.Getenv("OAUTH_CLIENT_SECRET"),
+				Scopes:               splitList(*oauthScopes),
+				AllowMissingAudience: *oauthAllowNoAudience,
+			}
+		}
+
+		if activated != nil {
+			log.Printf("Starting HTTP server on the socket passed by systemd")
+		} else if *unixSocket != "" {
+			log.Printf("Starting HTTP server on unix socket %s", *unixSocket)
+		} else {
+			log.Printf("Starting HTTP server on %s:%d", *httpAddr, *httpPort)
+		}
+		if *stdioMode {
+			// Both transports share the same server; the process exits once either stops,
+			// e.g. when the local client closes stdin
+			errs := make(chan error, 2)
+			go func() { errs <- server.StartHTTP(*httpAddr, *httpPort, httpOpts) }()
+			go func() { errs <- server.StartStdio() }()
+			if err := <-errs; err != nil {
+				log.Fatalf("MCP server stopped: %v", err)
+			}
+			return
+		}
+		if err := server.StartHTTP(*httpAddr, *httpPort, httpOpts); err != nil {
 			log.Fatalf("Failed to start HTTP server: %v", err)
 		}
 	} else {
 		// Default: stdio mode
+		notifyReady()
 		if err := server.StartStdio(); err != nil {
 			log.Fatalf("Failed to start MCP server: %v", err)
 		}
 	}
 }
 
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// probePlanka exits with a clear message if baseURL does not serve the Planka API
+func probePlanka(baseURL string, opts []planka.Option) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := planka.NewClient(baseURL, "", opts...).Probe(ctx); err != nil {
+		log.Fatalf("Invalid PLANKA_URL: %v", err)
+	}
+}
+
+// envDuration reads a duration such as "90s" or a number of seconds from the environment
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", name, value, err)
+	}
+	return d
+}
+
+// envInt reads an integer from the environment
+func envInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", name, value, err)
+	}
+	return n
+}
@@ -0,0 +1,624 @@
+package planka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetMe returns the current authenticated user
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var resp itemResponse[User]
+	if err := c.get(ctx, "/api/users/me", &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// GetProjects returns all projects
+func (c *Client) GetProjects(ctx context.Context) ([]Project, error) {
+	var resp itemsResponse[Project]
+	if err := c.get(ctx, "/api/projects", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetProject returns a project by ID
+func (c *Client) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	var resp itemResponse[Project]
+	if err := c.get(ctx, fmt.Sprintf("/api/projects/%s", projectID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// CreateProject creates a new project
+func (c *Client) CreateProject(ctx context.Context, req CreateProjectRequest) (*Project, error) {
+	var resp struct {
+		Item Project `json:"item"`
+	}
+	if err := c.post(ctx, "/api/projects", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteProject deletes a project
+func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
+	return c.delete(ctx, fmt.Sprintf("/api/projects/%s", projectID))
+}
+
+// GetBoards returns all boards for a project
+// Note: Boards are included in the project response
+func (c *Client) GetBoards(ctx context.Context, projectID string) ([]Board, error) {
+	var resp itemResponse[Project]
+	if err := c.get(ctx, fmt.Sprintf("/api/projects/%s", projectID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Included.Boards == nil {
+		return []Board{}, nil
+	}
+	return resp.Included.Boards, nil
+}
+
+// GetBoard returns a board by ID
+func (c *Client) GetBoard(ctx context.Context, boardID string) (*Board, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	board := b.board
+	return &board, nil
+}
+
+// CreateBoard creates a new board
+// Note: Boards are created via /api/projects/{projectId}/boards endpoint and require a position
+func (c *Client) CreateBoard(ctx context.Context, req CreateBoardRequest) (*Board, error) {
+	var resp struct {
+		Item Board `json:"item"`
+	}
+	// Position is required - use default if not provided
+	position := req.Position
+	if position == 0 {
+		position = 65535 // Default position
+	}
+	
+	// Create request body without projectId (it's in the URL)
+	requestBody := map[string]interface{}{
+		"name":     req.Name,
+		"position": position,
+	}
+	if req.Description != "" {
+		requestBody["description"] = req.Description
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/projects/%s/boards", req.ProjectID), requestBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteBoard deletes a board
+func (c *Client) DeleteBoard(ctx context.Context, boardID string) error {
+	return c.delete(ctx, fmt.Sprintf("/api/boards/%s", boardID))
+}
+
+// GetLists returns all lists for a board
+// Note: Lists are included in the board response
+func (c *Client) GetLists(ctx context.Context, boardID string) ([]List, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]List{}, b.included.Lists...), nil
+}
+
+// GetLabels returns all labels defined on a board
+// Note: Labels are included in the board response
+func (c *Client) GetLabels(ctx context.Context, boardID string) ([]Label, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Label{}, b.included.Labels...), nil
+}
+
+// GetBoardCards returns all cards on a board, across all of its lists
+// Note: Cards are included in the board response
+func (c *Client) GetBoardCards(ctx context.Context, boardID string) ([]Card, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Card{}, b.included.Cards...), nil
+}
+
+// GetBoardMemberships returns the memberships granting users access to a board
+// Note: Memberships are included in the board response
+func (c *Client) GetBoardMemberships(ctx context.Context, boardID string) ([]BoardMembership, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]BoardMembership{}, b.included.BoardMemberships...), nil
+}
+
+// GetCardMemberships returns the card assignees of all cards on a board, or nil if the
+// board response did not include them
+// Note: Card memberships are included in the board response
+func (c *Client) GetCardMemberships(ctx context.Context, boardID string) ([]CardMembership, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if b.included.CardMemberships == nil {
+		return nil, nil
+	}
+	return append([]CardMembership{}, b.included.CardMemberships...), nil
+}
+
+// GetCardLabels returns the labels attached to the cards of a board, or nil if the board
+// response did not include them
+// Note: Card labels are included in the board response
+func (c *Client) GetCardLabels(ctx context.Context, boardID string) ([]CardLabel, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if b.included.CardLabels == nil {
+		return nil, nil
+	}
+	return append([]CardLabel{}, b.included.CardLabels...), nil
+}
+
+// GetCustomFields returns the custom fields of a board and the values cards have for them
+// Note: Custom fields are included in the board response on Planka 2; earlier versions have none
+func (c *Client) GetCustomFields(ctx context.Context, boardID string) ([]CustomField, []CustomFieldValue, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]CustomField{}, b.included.CustomFields...), append([]CustomFieldValue{}, b.included.CustomFieldValues...), nil
+}
+
+// GetBoardUsers returns the users who are members of a board
+// Note: Users are included in the board response
+func (c *Client) GetBoardUsers(ctx context.Context, boardID string) ([]User, error) {
+	b, err := c.fetchBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]User{}, b.included.Users...), nil
+}
+
+// CreateLabel creates a label on a board
+// Note: Labels are created via /api/boards/{boardId}/labels endpoint and require a position
+func (c *Client) CreateLabel(ctx context.Context, req CreateLabelRequest) (*Label, error) {
+	position := req.Position
+	if position == 0 {
+		position = 65535 // Default position
+	}
+	requestBody := map[string]interface{}{
+		"name":     req.Name,
+		"color":    req.Color,
+		"position": position,
+	}
+
+	var resp itemResponse[Label]
+	if err := c.post(ctx, fmt.Sprintf("/api/boards/%s/labels", req.BoardID), requestBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// AddCardLabel attaches a board label to a card
+func (c *Client) AddCardLabel(ctx context.Context, cardID, labelID string) error {
+	var resp itemResponse[CardLabel]
+	return c.post(ctx, fmt.Sprintf("/api/cards/%s/card-labels", cardID), map[string]interface{}{"labelId": labelID}, &resp)
+}
+
+// AddCardMember assigns a user to a card
+func (c *Client) AddCardMember(ctx context.Context, cardID, userID string) error {
+	var resp itemResponse[CardMembership]
+	return c.post(ctx, fmt.Sprintf("/api/cards/%s/card-memberships", cardID), map[string]interface{}{"userId": userID}, &resp)
+}
+
+// GetList returns a list by ID
+func (c *Client) GetList(ctx context.Context, listID string) (*List, error) {
+	var resp itemResponse[List]
+	if err := c.get(ctx, fmt.Sprintf("/api/lists/%s", listID), &resp); err != nil {
+		return nil, err
+	}
+	c.lists.add(resp.Item)
+	return &resp.Item, nil
+}
+
+// CreateList creates a new list
+// Note: Lists are created via /api/boards/{boardId}/lists endpoint and require a position
+func (c *Client) CreateList(ctx context.Context, req CreateListRequest) (*List, error) {
+	// Position is required - add the list after the board's other lists if not provided
+	var position float64
+	if req.Position != nil {
+		position = *req.Position
+	} else {
+		var err error
+		position, err = nextPosition(ctx, req.BoardID, func(ctx context.Context) ([]List, error) {
+			return c.GetLists(ctx, req.BoardID)
+		}, func(list List) float64 { return list.Position })
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the lists of board %s to compute the position: %w", req.BoardID, err)
+		}
+	}
+	
+	// Create request body without boardId (it's in the URL)
+	requestBody := map[string]interface{}{
+		"name":     req.Name,
+		"position": position,
+	}
+	
+	var resp struct {
+		Item List `json:"item"`
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/boards/%s/lists", req.BoardID), requestBody, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Item.BoardID == "" {
+		resp.Item.BoardID = req.BoardID
+	}
+	c.lists.add(resp.Item)
+	return &resp.Item, nil
+}
+
+// UpdateList updates a list
+func (c *Client) UpdateList(ctx context.Context, listID string, req UpdateListRequest) (*List, error) {
+	var resp struct {
+		Item List `json:"item"`
+	}
+	if err := c.patch(ctx, fmt.Sprintf("/api/lists/%s", listID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteList deletes a list
+func (c *Client) DeleteList(ctx context.Context, listID string) error {
+	if err := c.delete(ctx, fmt.Sprintf("/api/lists/%s", listID)); err != nil {
+		return err
+	}
+	c.lists.remove(listID)
+	return nil
+}
+
+// listBoard returns the ID of the board a list belongs to, or "" if the list is not found.
+// When it had to fetch the board to find out, the board is returned too.
+func (c *Client) listBoard(ctx context.Context, listID string) (string, *hydratedBoard, error) {
+	if boardID, ok := c.lists.board(listID); ok {
+		return boardID, nil, nil
+	}
+
+	// Try getting the list - some Planka versions answer with HTML, so fall back to a search
+	if list, err := c.GetList(ctx, listID); err == nil {
+		return list.BoardID, nil, nil
+	}
+
+	// Search every board for the one with this list
+	boards, err := c.allBoards(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, board := range boards {
+		// Fetching the board records its lists in the index
+		b, err := c.fetchBoard(ctx, board.ID)
+		if err != nil {
+			continue
+		}
+		if boardID, ok := c.lists.board(listID); ok && boardID == board.ID {
+			return boardID, b, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// allBoards returns the boards of every project. The projects response includes their
+// boards; only Planka versions that leave them out need a request per project.
+func (c *Client) allBoards(ctx context.Context) ([]Board, error) {
+	var resp itemsResponse[Project]
+	if err := c.get(ctx, "/api/projects", &resp); err != nil {
+		return nil, fmt.Errorf("failed to get projects to find board: %w", err)
+	}
+	if resp.Included.Boards != nil {
+		return resp.Included.Boards, nil
+	}
+	var boards []Board
+	for _, project := range resp.Items {
+		projectBoards, err := c.GetBoards(ctx, project.ID)
+		if err != nil {
+			continue
+		}
+		boards = append(boards, projectBoards...)
+	}
+	return boards, nil
+}
+
+// GetCards returns all cards for a list
+// Note: Cards are included in the board response, so the board containing the list is needed.
+// It is looked up in the list index, then via the list itself, and as a last resort by
+// searching every board, which also fills the index for later calls.
+func (c *Client) GetCards(ctx context.Context, listID string) ([]Card, error) {
+	boardID, b, err := c.listBoard(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if boardID == "" {
+		return []Card{}, nil
+	}
+	
+	// Get the board which includes all cards, unless the search just fetched it
+	if b == nil {
+		if b, err = c.fetchBoard(ctx, boardID); err != nil {
+			return nil, fmt.Errorf("failed to get board %s: %w", boardID, err)
+		}
+	}
+	return b.cards(listID), nil
+}
+
+// GetCard returns a card by ID
+func (c *Client) GetCard(ctx context.Context, cardID string) (*Card, error) {
+	var resp itemResponse[Card]
+	if err := c.get(ctx, fmt.Sprintf("/api/cards/%s", cardID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// GetCardDetails returns a card with the tasks, card labels, card memberships and
+// attachments Planka includes with it
+func (c *Client) GetCardDetails(ctx context.Context, cardID string) (*CardDetails, error) {
+	var resp itemResponse[struct {
+		Card
+		BoardID string `json:"boardId"`
+	}]
+	if err := c.get(ctx, fmt.Sprintf("/api/cards/%s", cardID), &resp); err != nil {
+		return nil, err
+	}
+	return &CardDetails{Card: resp.Item.Card, BoardID: resp.Item.BoardID, Included: resp.Included}, nil
+}
+
+// CreateCard creates a new card
+// Note: Cards are created via /api/lists/{listId}/cards endpoint
+func (c *Client) CreateCard(ctx context.Context, req CreateCardRequest) (*Card, error) {
+	var resp struct {
+		Item Card `json:"item"`
+	}
+	// Position is required - add the card after the list's other cards if not provided
+	var position float64
+	if req.Position != nil {
+		position = *req.Position
+	} else {
+		var err error
+		position, err = nextPosition(ctx, req.ListID, func(ctx context.Context) ([]Card, error) {
+			return c.GetCards(ctx, req.ListID)
+		}, func(card Card) float64 { return card.Position })
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the cards of list %s to compute the position: %w", req.ListID, err)
+		}
+	}
+	
+	// Create request body without listId (it's in the URL)
+	requestBody := map[string]interface{}{
+		"name":     req.Name,
+		"position": position,
+	}
+	if req.Description != "" {
+		requestBody["description"] = req.Description
+	}
+	if req.DueDate != nil {
+		requestBody["dueDate"] = req.DueDate.Format(time.RFC3339)
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/lists/%s/cards", req.ListID), requestBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// UpdateCard updates a card
+func (c *Client) UpdateCard(ctx context.Context, cardID string, req UpdateCardRequest) (*Card, error) {
+	var resp struct {
+		Item Card `json:"item"`
+	}
+	if err := c.patch(ctx, fmt.Sprintf("/api/cards/%s", cardID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteCard deletes a card
+func (c *Client) DeleteCard(ctx context.Context, cardID string) error {
+	return c.delete(ctx, fmt.Sprintf("/api/cards/%s", cardID))
+}
+
+// MoveCard moves a card to a different list
+func (c *Client) MoveCard(ctx context.Context, cardID, listID string, position float64) (*Card, error) {
+	req := UpdateCardRequest{
+		ListID:   &listID,
+		Position: &position,
+	}
+	return c.UpdateCard(ctx, cardID, req)
+}
+
+// GetTasks returns all tasks for a card
+// Note: Tasks are included in the card response
+func (c *Client) GetTasks(ctx context.Context, cardID string) ([]Task, error) {
+	// A board fetched moments ago already included the card's tasks
+	if b, ok := c.boards.cardBoard(cardID); ok && !freshReads(ctx) {
+		if tasks, ok := b.cardTasks(cardID); ok {
+			return tasks, nil
+		}
+	}
+
+	var resp itemResponse[Card]
+	if err := c.get(ctx, fmt.Sprintf("/api/cards/%s", cardID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Included.Tasks == nil {
+		return []Task{}, nil
+	}
+	return resp.Included.Tasks, nil
+}
+
+// CreateTask creates a new task
+// Note: Tasks are created via /api/cards/{cardId}/tasks endpoint
+func (c *Client) CreateTask(ctx context.Context, req CreateTaskRequest) (*Task, error) {
+	var resp struct {
+		Item Task `json:"item"`
+	}
+	// Position is required - add the task after the card's other tasks if not provided
+	var position float64
+	if req.Position != nil {
+		position = *req.Position
+	} else {
+		var err error
+		position, err = nextPosition(ctx, req.CardID, func(ctx context.Context) ([]Task, error) {
+			return c.GetTasks(ctx, req.CardID)
+		}, func(task Task) float64 { return task.Position })
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the tasks of card %s to compute the position: %w", req.CardID, err)
+		}
+	}
+	
+	// Create request body without cardId (it's in the URL)
+	requestBody := map[string]interface{}{
+		"name":     req.Name,
+		"position": position,
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/cards/%s/tasks", req.CardID), requestBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// UpdateTask updates a task
+func (c *Client) UpdateTask(ctx context.Context, taskID string, req UpdateTaskRequest) (*Task, error) {
+	var resp struct {
+		Item Task `json:"item"`
+	}
+	if err := c.patch(ctx, fmt.Sprintf("/api/tasks/%s", taskID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteTask deletes a task
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	return c.delete(ctx, fmt.Sprintf("/api/tasks/%s", taskID))
+}
+
+// GetComments returns all comments for a card, or the newest ones with an ErrTruncated
+// error if the card has more than the client's item cap
+// Note: Comments endpoint may return HTML, so we try the endpoint first, and if it fails,
+// we check if comments are in the card's included section
+func (c *Client) GetComments(ctx context.Context, cardID string) ([]Comment, error) {
+	// Some Planka versions include comments in the board response
+	if b, ok := c.boards.cardBoard(cardID); ok && !freshReads(ctx) {
+		if comments, ok := b.cardComments(cardID); ok {
+			return comments, nil
+		}
+	}
+
+	// Try the comments endpoint first, following its pages on cards with long discussions
+	comments, err := getAllPages(ctx, c, fmt.Sprintf("/api/cards/%s/comments", cardID), func(comment Comment) string {
+		return comment.ID
+	})
+	if errors.Is(err, ErrTruncated) {
+		return comments, err
+	}
+
+	if err != nil {
+		// Endpoint returned HTML, try getting from card's included section
+		var cardResp itemResponse[Card]
+		if err := c.get(ctx, fmt.Sprintf("/api/cards/%s", cardID), &cardResp); err != nil {
+			return nil, fmt.Errorf("failed to get card: %w", err)
+		}
+		if cardResp.Included.Comments == nil {
+			return []Comment{}, nil
+		}
+		return cardResp.Included.Comments, nil
+	}
+	
+	return comments, nil
+}
+
+// CreateComment creates a new comment
+func (c *Client) CreateComment(ctx context.Context, req CreateCommentRequest) (*Comment, error) {
+	var resp struct {
+		Item Comment `json:"item"`
+	}
+	if err := c.post(ctx, "/api/comments", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// DeleteComment deletes a comment
+func (c *Client) DeleteComment(ctx context.Context, commentID string) error {
+	return c.delete(ctx, fmt.Sprintf("/api/comments/%s", commentID))
+}
+
+// GetStopwatch returns the stopwatch for a card
+func (c *Client) GetStopwatch(ctx context.Context, cardID string) (*Stopwatch, error) {
+	var resp struct {
+		Item Stopwatch `json:"item"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/api/cards/%s/stopwatch", cardID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// StartStopwatch starts the stopwatch for a card
+func (c *Client) StartStopwatch(ctx context.Context, cardID string) (*Stopwatch, error) {
+	var resp struct {
+		Item Stopwatch `json:"item"`
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/cards/%s/stopwatch/start", cardID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// StopStopwatch stops the stopwatch for a card
+func (c *Client) StopStopwatch(ctx context.Context, cardID string) (*Stopwatch, error) {
+	var resp struct {
+		Item Stopwatch `json:"item"`
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/cards/%s/stopwatch/stop", cardID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// SetStopwatchDuration sets the time recorded on the stopwatch for a card, leaving it
+// running if it is
+func (c *Client) SetStopwatchDuration(ctx context.Context, cardID string, duration int64) (*Stopwatch, error) {
+	var resp struct {
+		Item Stopwatch `json:"item"`
+	}
+	body := map[string]int64{"duration": duration}
+	if err := c.patch(ctx, fmt.Sprintf("/api/cards/%s/stopwatch", cardID), body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
+// ResetStopwatch resets the stopwatch for a card
+func (c *Client) ResetStopwatch(ctx context.Context, cardID string) (*Stopwatch, error) {
+	var resp struct {
+		Item Stopwatch `json:"item"`
+	}
+	if err := c.post(ctx, fmt.Sprintf("/api/cards/%s/stopwatch/reset", cardID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Item, nil
+}
+
@@ -0,0 +1,120 @@
+package planka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// webAppSegments are paths of the Planka web app and API that end up in a base URL when it
+// is copied from the browser's address bar or an API call
+var webAppSegments = []string{"api", "login", "projects", "boards", "cards"}
+
+// NormalizeBaseURL validates the base URL of a Planka server and returns it without trailing
+// slashes. It must be an http or https URL of the root Planka is served at, which may include
+// the path prefix of a reverse proxy, e.g. https://example.com/planka.
+func NormalizeBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("the Planka URL is empty")
+	}
+	if !strings.Contains(raw, "://") {
+		return "", fmt.Errorf("%q has no scheme; use e.g. https://%s", raw, raw)
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid URL: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("%q must use http or https, not %s", raw, parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("%q has no host", raw)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("%q must not have a query or fragment", raw)
+	}
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		for _, appSegment := range webAppSegments {
+			if strings.EqualFold(segment, appSegment) {
+				return "", fmt.Errorf("%q contains /%s; use the root URL Planka is served at, e.g. %s://%s", raw, segment, parsed.Scheme, parsed.Host)
+			}
+		}
+	}
+
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	parsed.RawPath = ""
+	return parsed.String(), nil
+}
+
+// Probe checks that the client's base URL serves the Planka API by requesting the
+// unauthenticated /api/config, so a wrong URL is reported up front rather than as
+// HTML responses to later requests
+func (c *Client) Probe(ctx context.Context) error {
+	_, err := c.fetchConfig(ctx)
+	return err
+}
+
+// fetchConfig requests the unauthenticated /api/config, reporting a response that is not
+// Planka's
+func (c *Client) fetchConfig(ctx context.Context) ([]byte, error) {
+	resp, err := c.send(ctx, "GET", "/api/config", nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Planka at %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s/api/config: %w", c.baseURL, err)
+	}
+	body = []byte(strings.TrimSpace(string(body)))
+	if resp.StatusCode != http.StatusOK || len(body) == 0 || body[0] != '{' {
+		return nil, fmt.Errorf("%s does not look like a Planka server: GET /api/config answered with status %d and %s; check the URL and any reverse proxy path prefix",
+			c.baseURL, resp.StatusCode, describeBody(resp.Header.Get("Content-Type"), body))
+	}
+	return body, nil
+}
+
+// describeBody summarizes an unexpected response body for an error message
+func describeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return "an empty body"
+	}
+	if body[0] == '<' {
+		return "an HTML page"
+	}
+	return fmt.Sprintf("%q (%s)", body[:min(80, len(body))], contentType)
+}
+
+// ServerInfo is what Planka tells about itself without authentication
+type ServerInfo struct {
+	// Version is Planka's version, e.g. "2.0.1"; Planka 1 does not report it
+	Version string `json:"version"`
+	// OIDC is set when Planka offers single sign-on through OpenID Connect
+	OIDC *struct {
+		// IsEnforced is set when users cannot log in with a password
+		IsEnforced bool `json:"isEnforced"`
+	} `json:"oidc"`
+}
+
+// ServerInfo returns the unauthenticated /api/config of Planka. Like Probe it reports a
+// base URL that does not serve the Planka API.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	body, err := c.fetchConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Item ServerInfo `json:"item"`
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode %s/api/config: %w", c.baseURL, err)
+	}
+	return &config.Item, nil
+}
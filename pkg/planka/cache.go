@@ -0,0 +1,145 @@
+package planka
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// invalidatedBy maps the resource a write goes to onto the resources whose GET responses
+// may include the changed entity. Boards embed their lists, cards and labels, cards embed
+// their tasks, and projects embed their boards.
+var invalidatedBy = map[string][]string{
+	"projects": {"projects", "boards"},
+	"boards":   {"projects", "boards", "lists", "cards"},
+	"lists":    {"boards", "lists", "cards"},
+	"cards":    {"boards", "lists", "cards"},
+	"tasks":    {"boards", "cards"},
+	"comments": {"cards"},
+}
+
+// WithCache caches GET responses for ttl. Creating, updating or deleting an entity drops the
+// cached responses that may include it, so the client's own writes are always visible;
+// changes made elsewhere in Planka show up once the entry expires. Boards, the largest
+// responses, are also kept decoded for ttl unless WithBoardHydration sets a window of its own.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.cache = &responseCache{ttl: ttl, entries: map[string]cacheEntry{}}
+			if c.boards == nil {
+				c.boards = newBoardStore(ttl)
+			}
+		}
+	}
+}
+
+// freshReadsKey is the context key of contexts whose reads bypass the cache
+type freshReadsKey struct{}
+
+// WithFreshReads returns a context in which clients send every GET request to Planka
+// instead of answering it from the response cache or a hydrated board, for callers that
+// must see changes made elsewhere right away. The responses still refresh the cache.
+func WithFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadsKey{}, true)
+}
+
+// freshReads reports whether reads in ctx bypass the cache
+func freshReads(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadsKey{}).(bool)
+	return fresh
+}
+
+// cacheEntry is a cached response body
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// responseCache is a TTL cache of GET response bodies keyed by endpoint.
+// A nil cache caches nothing.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// lookup returns the cached body of endpoint if it has not expired
+func (c *responseCache) lookup(endpoint string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[endpoint]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// store caches the body of endpoint
+func (c *responseCache) store(endpoint string, body []byte) {
+	if c == nil {
+		return
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop expired entries so the cache does not grow forever
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[endpoint] = cacheEntry{body: body, expiresAt: now.Add(c.ttl)}
+}
+
+// invalidate drops the cached responses that a write to endpoint may have made stale.
+// Writes to unknown resources clear the whole cache.
+func (c *responseCache) invalidate(endpoint string) {
+	if c == nil {
+		return
+	}
+	resources, ok := invalidatedBy[resourceOf(endpoint)]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if !ok || containsResource(resources, resourceOf(key)) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// resourceOf returns the resource an API endpoint belongs to, e.g. "cards" for /api/cards/1/tasks
+func resourceOf(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/api/"), "/")
+	return resource
+}
+
+// containsResource reports whether resources contains resource
+func containsResource(resources []string, resource string) bool {
+	for _, r := range resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// clear drops every cached response
+func (c *responseCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
@@ -0,0 +1,34 @@
+package planka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestFreshReadsBypassCache(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	boardID := srv.AddBoard(srv.AddProject("Project"), "Board")
+	listID := srv.AddList(boardID, "Todo")
+	srv.AddCard(listID, "First")
+	client := srv.Client(planka.WithCache(time.Minute))
+	ctx := context.Background()
+
+	if cards, err := client.GetBoardCards(ctx, boardID); err != nil || len(cards) != 1 {
+		t.Fatalf("GetBoardCards = %v, %v, want the one card", cards, err)
+	}
+	// A card added elsewhere stays hidden by the cache until fresh reads ask Planka again
+	srv.AddCard(listID, "Second")
+	if cards, _ := client.GetBoardCards(ctx, boardID); len(cards) != 1 {
+		t.Errorf("cached GetBoardCards returned %d cards, want the cached one", len(cards))
+	}
+	if cards, err := client.GetBoardCards(planka.WithFreshReads(ctx), boardID); err != nil || len(cards) != 2 {
+		t.Errorf("fresh GetBoardCards = %v, %v, want both cards", cards, err)
+	}
+	if cards, _ := client.GetBoardCards(ctx, boardID); len(cards) != 2 {
+		t.Errorf("GetBoardCards after a fresh read returned %d cards, want the refreshed 2", len(cards))
+	}
+}
@@ -0,0 +1,164 @@
+package planka
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// chaosStatuses are the 5xx statuses injected failures answer with
+var chaosStatuses = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ChaosOptions configures the faults injected into the requests to Planka. It is meant for
+// testing how retries, timeouts and error mapping behave when Planka is slow or failing.
+type ChaosOptions struct {
+	// Latency delays every request by this long before it is sent
+	Latency time.Duration
+	// ErrorRate is the fraction (0 to 1) of requests answered with a 5xx response instead
+	// of being sent
+	ErrorRate float64
+	// HTMLRate is the fraction (0 to 1) of requests answered with an HTML page instead of
+	// being sent, like a reverse proxy's login or error page
+	HTMLRate float64
+	// Seed seeds the choice of the failing requests, so a run can be repeated exactly
+	Seed int64
+}
+
+// WithChaos injects latency and failures into every request to Planka according to opts.
+// Injected failures never reach Planka. Only use it for testing.
+func WithChaos(opts ChaosOptions) Option {
+	return func(c *Client) {
+		c.chaos = &chaos{opts: opts, rand: rand.New(rand.NewSource(opts.Seed))}
+	}
+}
+
+// ParseChaosOptions parses a comma-separated list of key=value settings such as
+// "latency=200ms,error-rate=0.1,html-rate=0.05,seed=42"
+func ParseChaosOptions(spec string) (ChaosOptions, error) {
+	var opts ChaosOptions
+	for _, setting := range strings.Split(spec, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(setting, "=")
+		if !ok {
+			return ChaosOptions{}, fmt.Errorf("invalid setting %q, expected key=value", setting)
+		}
+		var err error
+		switch key {
+		case "latency":
+			opts.Latency, err = time.ParseDuration(value)
+		case "error-rate":
+			opts.ErrorRate, err = parseRate(value)
+		case "html-rate":
+			opts.HTMLRate, err = parseRate(value)
+		case "seed":
+			opts.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return ChaosOptions{}, fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return ChaosOptions{}, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+	}
+	if opts.ErrorRate+opts.HTMLRate > 1 {
+		return ChaosOptions{}, fmt.Errorf("error-rate and html-rate add up to more than 1")
+	}
+	return opts, nil
+}
+
+// parseRate parses a fraction between 0 and 1
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("must be between 0 and 1")
+	}
+	return rate, nil
+}
+
+// chaos injects faults into requests. A nil chaos injects nothing.
+type chaos struct {
+	opts ChaosOptions
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// wrap returns a transport that injects faults before handing requests to next
+func (ch *chaos) wrap(next http.RoundTripper) http.RoundTripper {
+	if ch == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &chaosTransport{chaos: ch, next: next}
+}
+
+// roll returns a random number in [0, 1) from the seeded source
+func (ch *chaos) roll() float64 {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.rand.Float64()
+}
+
+// chaosTransport is a RoundTripper injecting the faults of a chaos
+type chaosTransport struct {
+	chaos *chaos
+	next  http.RoundTripper
+}
+
+// RoundTrip delays req and then either answers it with an injected failure or sends it.
+// The delay runs within the request's context, so the client's timeout covers it.
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	opts := t.chaos.opts
+	if opts.Latency > 0 {
+		if err := sleep(req.Context(), opts.Latency); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	roll := t.chaos.roll()
+	switch {
+	case roll < opts.ErrorRate:
+		status := chaosStatuses[int(roll/opts.ErrorRate*float64(len(chaosStatuses)))%len(chaosStatuses)]
+		return chaosResponse(req, status, "application/json", fmt.Sprintf(`{"code":"E_CHAOS","message":"injected %d"}`, status)), nil
+	case roll < opts.ErrorRate+opts.HTMLRate:
+		return chaosResponse(req, http.StatusOK, "text/html", "<!DOCTYPE html><html><body>Injected HTML page</body></html>"), nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// chaosResponse builds the response of an injected failure
+func chaosResponse(req *http.Request, status int, contentType, body string) *http.Response {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
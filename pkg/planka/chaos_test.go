@@ -0,0 +1,102 @@
+package planka_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestChaosRetriesInjectedErrors(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	projectID := srv.AddProject("Project")
+	client := srv.Client(
+		planka.WithRetry(planka.RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond}),
+		planka.WithChaos(planka.ChaosOptions{ErrorRate: 1}),
+	)
+
+	_, err := client.GetProject(context.Background(), projectID)
+	var apiErr *planka.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode < 500 {
+		t.Fatalf("GetProject error = %v, want a 5xx APIError", err)
+	}
+	if requests := srv.Requests(); len(requests) != 0 {
+		t.Errorf("Planka received %v, want injected failures to never reach it", requests)
+	}
+}
+
+func TestChaosInjectsHTML(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	projectID := srv.AddProject("Project")
+	client := srv.Client(planka.WithChaos(planka.ChaosOptions{HTMLRate: 1}))
+
+	_, err := client.GetProject(context.Background(), projectID)
+	if err == nil || !strings.Contains(err.Error(), "received HTML instead of JSON") {
+		t.Fatalf("GetProject error = %v, want the HTML response to be reported", err)
+	}
+}
+
+func TestChaosLatencyHitsTimeout(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	projectID := srv.AddProject("Project")
+	client := srv.Client(
+		planka.WithTimeout(20*time.Millisecond),
+		planka.WithChaos(planka.ChaosOptions{Latency: time.Second}),
+	)
+
+	start := time.Now()
+	if _, err := client.GetProject(context.Background(), projectID); err == nil {
+		t.Fatal("GetProject succeeded, want the timeout to cut the injected latency short")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetProject took %s, want it to fail at the 20ms timeout", elapsed)
+	}
+}
+
+func TestChaosSeedRepeatsFailures(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	projectID := srv.AddProject("Project")
+
+	// outcomes returns which of 20 requests of a fresh client failed
+	outcomes := func() string {
+		client := srv.Client(planka.WithChaos(planka.ChaosOptions{ErrorRate: 0.3, HTMLRate: 0.2, Seed: 7}))
+		var b strings.Builder
+		for i := 0; i < 20; i++ {
+			if _, err := client.GetProject(context.Background(), projectID); err != nil {
+				b.WriteByte('x')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		return b.String()
+	}
+
+	first, second := outcomes(), outcomes()
+	if first != second {
+		t.Errorf("runs with the same seed failed as %s and %s, want the same requests to fail", first, second)
+	}
+	if !strings.Contains(first, "x") || !strings.Contains(first, ".") {
+		t.Errorf("run failed as %s, want some requests to fail and some to succeed", first)
+	}
+}
+
+func TestParseChaosOptions(t *testing.T) {
+	opts, err := planka.ParseChaosOptions("latency=200ms, error-rate=0.1,html-rate=0.05,seed=42")
+	if err != nil {
+		t.Fatalf("ParseChaosOptions: %v", err)
+	}
+	want := planka.ChaosOptions{Latency: 200 * time.Millisecond, ErrorRate: 0.1, HTMLRate: 0.05, Seed: 42}
+	if opts != want {
+		t.Errorf("ParseChaosOptions = %+v, want %+v", opts, want)
+	}
+
+	for _, spec := range []string{"latency", "latency=soon", "error-rate=2", "jitter=1", "error-rate=0.6,html-rate=0.6"} {
+		if _, err := planka.ParseChaosOptions(spec); err == nil {
+			t.Errorf("ParseChaosOptions(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package planka
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client represents a Planka API client
+type Client struct {
+	baseURL    string
+	session    *session
+	httpClient *http.Client
+	retry      RetryPolicy
+	cache      *responseCache
+	lists      *listIndex
+	maxItems   int
+	// cookieAuth authenticates with the httpOnly cookie issued on login
+	cookieAuth bool
+	// debug logs every HTTP exchange with Planka when set
+	debug *debugLogger
+	// userAgent overrides the default User-Agent when set
+	userAgent string
+	// metrics records every request when set
+	metrics *Metrics
+	// boards holds recently fetched boards with their sub-resources when set
+	boards *boardStore
+	// chaos injects latency and failures into requests when set
+	chaos *chaos
+}
+
+// session holds the access token shared by all requests of a client, so a token
+// renewed by one request is used by all of them
+type session struct {
+	mu    sync.Mutex
+	token string
+	// username and password are kept to log in again once the token expires
+	username string
+	password string
+	// lastWarning is when the expiry of a static token was last warned about
+	lastWarning time.Time
+	// retryAfter is when Planka's rate limit lifts; requests are held back until then
+	retryAfter time.Time
+}
+
+// currentToken returns the access token to authenticate requests with
+func (s *session) currentToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// holdUntil holds back requests until t after Planka rate limited them
+func (s *session) holdUntil(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.After(s.retryAfter) {
+		s.retryAfter = t
+	}
+}
+
+// heldUntil returns when requests may be sent again after Planka rate limited them
+func (s *session) heldUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retryAfter
+}
+
+// canLogin reports whether a new token can be obtained by logging in
+func (s *session) canLogin() bool {
+	return s.username != "" && s.password != ""
+}
+
+// LoginResponse represents the response from a login request
+type LoginResponse struct {
+	Item string `json:"item"` // The access token
+}
+
+// defaultTimeout bounds each Planka HTTP request unless WithTimeout is given
+const defaultTimeout = 30 * time.Second
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTimeout bounds each HTTP request to Planka, including reading the response body.
+// Zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// TransportOptions tunes how the client reuses connections to Planka
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept open to Planka;
+	// zero uses the net/http default of 2
+	MaxIdleConnsPerHost int
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+	// Proxy is the proxy all requests to Planka go through. When nil the proxy is taken
+	// from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy *url.URL
+	// RootCAs are the certificate authorities trusted for Planka's TLS certificate;
+	// nil uses the system pool
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify accepts any TLS certificate from Planka. Only use it for testing.
+	InsecureSkipVerify bool
+}
+
+// WithTransport configures the client's connection handling
+func WithTransport(opts TransportOptions) Option {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		transport.DisableKeepAlives = opts.DisableKeepAlives
+		if opts.Proxy != nil {
+			transport.Proxy = http.ProxyURL(opts.Proxy)
+		}
+		if opts.RootCAs != nil || opts.InsecureSkipVerify {
+			transport.TLSClientConfig = &tls.Config{
+				RootCAs:            opts.RootCAs,
+				InsecureSkipVerify: opts.InsecureSkipVerify,
+			}
+		}
+		c.httpClient.Transport = transport
+	}
+}
+
+// NewClient creates a new Planka API client with a token
+func NewClient(baseURL, token string, opts ...Option) *Client {
+	client := &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		session:  &session{token: token},
+		lists:    newListIndex(),
+		maxItems: defaultMaxItems,
+		httpClient: &http.Client{
+			Timeout: defaultTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	// Faults are injected around whichever transport the options configured
+	client.httpClient.Transport = client.chaos.wrap(client.httpClient.Transport)
+	// Warn right away if the token is about to expire
+	client.checkExpiry(context.Background())
+	return client
+}
+
+// NewClientWithPassword creates a new Planka API client by logging in with username/password.
+// When the access token expires the client logs in again.
+func NewClientWithPassword(baseURL, username, password string, opts ...Option) (*Client, error) {
+	client := &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		session:  &session{username: username, password: password},
+		lists:    newListIndex(),
+		maxItems: defaultMaxItems,
+		httpClient: &http.Client{
+			Timeout: defaultTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	// Faults are injected around whichever transport the options configured
+	client.httpClient.Transport = client.chaos.wrap(client.httpClient.Transport)
+
+	token, err := client.login(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	client.session.token = token
+	return client, nil
+}
+
+// BaseURL returns the URL of the Planka server the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// login obtains a new access token with the session's username and password
+func (c *Client) login(ctx context.Context) (string, error) {
+	loginReq := map[string]string{
+		"emailOrUsername": c.session.username,
+		"password":        c.session.password,
+	}
+
+	endpoint := "/api/access-tokens"
+	if c.cookieAuth {
+		endpoint += httpOnlyTokenQuery
+	}
+
+	var loginResp LoginResponse
+	if err := c.postWithoutAuth(ctx, endpoint, loginReq, &loginResp); err != nil {
+		return "", fmt.Errorf("login failed: %w", err)
+	}
+	return loginResp.Item, nil
+}
+
+// relogin replaces the expired token staleToken by logging in again. If another request
+// already renewed it, the new token is kept.
+func (c *Client) relogin(ctx context.Context, staleToken string) error {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	if c.session.token != staleToken {
+		return nil
+	}
+	token, err := c.login(ctx)
+	if err != nil {
+		return err
+	}
+	c.session.token = token
+	return nil
+}
+
+// postWithoutAuth performs a POST request without authentication (for login)
+func (c *Client) postWithoutAuth(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, "POST", url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req, jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return newAPIError("POST", endpoint, resp.StatusCode, bodyBytes)
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// doRequest performs an HTTP request to the Planka API
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	// In a dry run writes are only recorded, leaving Planka and the caches as they are
+	if dryRun := DryRunFromContext(ctx); dryRun != nil && method != "GET" {
+		return dryRun.record(method, endpoint, jsonData), nil
+	}
+
+	// Writes make cached responses that may include the changed entity stale
+	if method != "GET" {
+		defer c.cache.invalidate(endpoint)
+		// Any write may change something a board response includes
+		defer c.boards.clear()
+	}
+
+	c.checkExpiry(ctx)
+	token := c.session.currentToken()
+	resp, err := c.sendWithRetry(ctx, method, endpoint, jsonData, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens obtained by logging in expire; log in again and retry the request once
+	if resp.StatusCode == http.StatusUnauthorized && c.session.canLogin() {
+		resp.Body.Close()
+		if err := c.relogin(ctx, token); err != nil {
+			return nil, err
+		}
+		resp, err = c.sendWithRetry(ctx, method, endpoint, jsonData, c.session.currentToken())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, newAPIError(method, endpoint, resp.StatusCode, bodyBytes)
+	}
+
+	return resp, nil
+}
+
+// send sends a single authenticated request with a JSON body
+func (c *Client) send(ctx context.Context, method, endpoint string, jsonData []byte, token string) (*http.Response, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	url := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	// With cookie authentication Planka may only set the cookie and return no token
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.do(req, jsonData)
+}
+
+// do sends req with identification headers, decoding a compressed response and logging
+// the exchange when debugging
+func (c *Client) do(req *http.Request, body []byte) (*http.Response, error) {
+	c.identify(req.Header)
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	c.metrics.record(req.Method, req.URL.Path, time.Since(start), err != nil || resp.StatusCode >= 400)
+	if err != nil {
+		c.debug.log(req, body, nil, err, time.Since(start))
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if err := decompress(resp); err != nil {
+		c.debug.log(req, body, nil, err, time.Since(start))
+		return nil, err
+	}
+	c.debug.log(req, body, resp, nil, time.Since(start))
+	return resp, nil
+}
+
+// get performs a GET request
+func (c *Client) get(ctx context.Context, endpoint string, result interface{}) error {
+	// Responses fetched recently are served from the cache
+	var bodyBytes []byte
+	cached := false
+	if !freshReads(ctx) {
+		bodyBytes, cached = c.cache.lookup(endpoint)
+	}
+	if !cached {
+		resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if result == nil {
+			return nil
+		}
+
+		// Read the body first to check if it's valid JSON
+		bodyBytes, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+	}
+
+	if result != nil {
+		// Check if response is HTML (starts with <)
+		if len(bodyBytes) > 0 && bodyBytes[0] == '<' {
+			return fmt.Errorf("received HTML instead of JSON for endpoint %s. Response preview: %s", endpoint, string(bodyBytes[:min(200, len(bodyBytes))]))
+		}
+
+		if err := json.Unmarshal(bodyBytes, result); err != nil {
+			return fmt.Errorf("failed to decode JSON response for endpoint %s: %w. Response preview: %s", endpoint, err, string(bodyBytes[:min(200, len(bodyBytes))]))
+		}
+	}
+
+	if !cached {
+		c.cache.store(endpoint, bodyBytes)
+	}
+	return nil
+}
+
+// min returns the minimum of two integers
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// post performs a POST request
+func (c *Client) post(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	resp, err := c.doRequest(ctx, "POST", endpoint, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// patch performs a PATCH request
+func (c *Client) patch(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	resp, err := c.doRequest(ctx, "PATCH", endpoint, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// delete performs a DELETE request
+func (c *Client) delete(ctx context.Context, endpoint string) error {
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
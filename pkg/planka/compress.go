@@ -0,0 +1,71 @@
+package planka
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding lists the response encodings the client decompresses.
+// Setting it explicitly disables net/http's own gzip handling, so both are decoded here.
+const acceptEncoding = "gzip, deflate"
+
+// decompressedBody is a decoded response body that also closes the raw body
+type decompressedBody struct {
+	io.Reader
+	decoder io.Closer
+	raw     io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	b.decoder.Close()
+	return b.raw.Close()
+}
+
+// decompress replaces the body of a gzip or deflate encoded response with its decoded content
+func decompress(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" || resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+
+	var decoder io.ReadCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return fmt.Errorf("failed to decompress gzip response: %w", err)
+		}
+		decoder = reader
+	case "deflate":
+		// "deflate" is zlib-wrapped per the HTTP spec, but some servers send raw deflate
+		buffered := bufio.NewReader(resp.Body)
+		header, _ := buffered.Peek(2)
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			reader, err := zlib.NewReader(buffered)
+			if err != nil {
+				resp.Body.Close()
+				return fmt.Errorf("failed to decompress deflate response: %w", err)
+			}
+			decoder = reader
+		} else {
+			decoder = flate.NewReader(buffered)
+		}
+	default:
+		resp.Body.Close()
+		return fmt.Errorf("unsupported response encoding %q", encoding)
+	}
+
+	resp.Body = &decompressedBody{Reader: decoder, decoder: decoder, raw: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
@@ -0,0 +1,18 @@
+package planka
+
+import "net/http/cookiejar"
+
+// httpOnlyTokenQuery asks Planka to also issue the access token as an httpOnly cookie on login
+const httpOnlyTokenQuery = "?withHttpOnlyToken=true"
+
+// WithCookieAuth makes a client that logs in with username and password authenticate with
+// the httpOnly cookie Planka sets on login, for deployments that only accept cookie-bound
+// sessions. The cookie is kept in the client's cookie jar and renewed on each login.
+func WithCookieAuth() Option {
+	return func(c *Client) {
+		// cookiejar.New only fails for invalid options
+		jar, _ := cookiejar.New(nil)
+		c.httpClient.Jar = jar
+		c.cookieAuth = true
+	}
+}
@@ -0,0 +1,113 @@
+package planka
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultDebugBodyBytes is how much of each body is logged unless configured otherwise
+const defaultDebugBodyBytes = 2048
+
+// redactedHeaders are the headers whose values are never logged
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DebugOptions configures logging of the HTTP traffic to Planka
+type DebugOptions struct {
+	// Logger receives one record per request
+	Logger *slog.Logger
+	// Bodies adds the request and response bodies, cut to MaxBodyBytes
+	Bodies bool
+	// MaxBodyBytes bounds how much of each body is logged; zero uses 2048
+	MaxBodyBytes int
+}
+
+// WithDebugLog logs the method, URL, status, duration and headers of every request to
+// Planka, with credentials redacted. It helps diagnosing failures such as Planka answering
+// with an HTML page instead of JSON.
+func WithDebugLog(opts DebugOptions) Option {
+	return func(c *Client) {
+		if opts.Logger == nil {
+			opts.Logger = slog.Default()
+		}
+		if opts.MaxBodyBytes <= 0 {
+			opts.MaxBodyBytes = defaultDebugBodyBytes
+		}
+		c.debug = &debugLogger{opts: opts}
+	}
+}
+
+// debugLogger logs HTTP exchanges with Planka. A nil logger logs nothing.
+type debugLogger struct {
+	opts DebugOptions
+}
+
+// log records an exchange; resp is nil when the request failed with err
+func (d *debugLogger) log(req *http.Request, body []byte, resp *http.Response, err error, duration time.Duration) {
+	if d == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("duration", duration),
+		slog.String("request_headers", redactHeaders(req.Header)),
+	}
+	if d.opts.Bodies && len(body) > 0 {
+		attrs = append(attrs, slog.String("request_body", d.bodyPreview(req, body)))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		d.opts.Logger.LogAttrs(context.Background(), slog.LevelWarn, "Planka request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs,
+		slog.Int("status", resp.StatusCode),
+		slog.String("content_type", resp.Header.Get("Content-Type")),
+		slog.String("response_headers", redactHeaders(resp.Header)),
+	)
+	if d.opts.Bodies {
+		// Read the start of the body and put it back for the caller
+		preview, _ := io.ReadAll(io.LimitReader(resp.Body, int64(d.opts.MaxBodyBytes)+1))
+		resp.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(preview), resp.Body), Closer: resp.Body}
+		attrs = append(attrs, slog.String("response_body", d.bodyPreview(req, preview)))
+	}
+	d.opts.Logger.LogAttrs(context.Background(), slog.LevelInfo, "Planka request", attrs...)
+}
+
+// bodyPreview returns body cut to the configured size. Login requests and responses carry
+// credentials and access tokens, so their bodies are never logged.
+func (d *debugLogger) bodyPreview(req *http.Request, body []byte) string {
+	if strings.Contains(req.URL.Path, "/api/access-tokens") {
+		return "[REDACTED]"
+	}
+	if len(body) > d.opts.MaxBodyBytes {
+		return string(body[:d.opts.MaxBodyBytes]) + "…[truncated]"
+	}
+	return string(body)
+}
+
+// redactHeaders formats headers for logging with credentials masked
+func redactHeaders(header http.Header) string {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+	var b strings.Builder
+	redacted.Write(&b)
+	return strings.TrimSuffix(strings.ReplaceAll(b.String(), "\r\n", "; "), "; ")
+}
+
+// replayBody is a response body whose start was read for logging
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,23 @@
+// Package planka is a client for the REST API of Planka, the open source kanban board.
+// It is the client the planka-mcp server acts through, published so other Go programs
+// can script Planka the same way.
+//
+// Create a client with an API token, or by logging in with a username and password, in
+// which case the client logs in again whenever the access token expires:
+//
+//	client := planka.NewClient("https://planka.example.com", token,
+//		planka.WithTimeout(30*time.Second),
+//		planka.WithRetry(planka.RetryPolicy{Attempts: 3, BaseDelay: time.Second}),
+//	)
+//	projects, err := client.GetProjects(ctx)
+//
+// Options tune the client: retries with backoff, response caching, TLS settings, debug
+// logging and metrics. Failed requests return an *APIError carrying Planka's status code
+// and message. Requests made with a context from WithDryRun are recorded instead of
+// changing Planka. Realtime follows the changes Planka pushes over its socket, and
+// ParseWebhook decodes those it sends to webhooks.
+//
+// The package works with Planka 1 and 2. Exported names are kept stable: new methods
+// and fields may be added, but existing ones are not removed or changed incompatibly
+// within a major version.
+package planka
@@ -0,0 +1,65 @@
+package planka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Call is a request that changes Planka, as recorded by a DryRun
+type Call struct {
+	Method string `json:"method"`
+	// Path is the endpoint relative to the base URL, e.g. /api/cards/42
+	Path string          `json:"path"`
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// DryRun records the requests that would change Planka instead of sending them
+type DryRun struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// dryRunKey is the context key of the DryRun of a context
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which clients record the POST, PATCH and DELETE
+// requests they would send in the returned DryRun instead of sending them. GET requests
+// are still sent, so callers see the current state. Recorded requests succeed with an
+// entity whose ID is a placeholder such as "dry-run-1", so later calls referring to a
+// created entity can be told apart.
+func WithDryRun(ctx context.Context) (context.Context, *DryRun) {
+	dryRun := &DryRun{}
+	return context.WithValue(ctx, dryRunKey{}, dryRun), dryRun
+}
+
+// DryRunFromContext returns the DryRun of ctx, or nil if requests are sent
+func DryRunFromContext(ctx context.Context) *DryRun {
+	dryRun, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun
+}
+
+// Calls returns the requests recorded so far, in the order they were made
+func (d *DryRun) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call{}, d.calls...)
+}
+
+// record adds a request to the dry run and returns the response it gets instead of Planka's
+func (d *DryRun) record(method, endpoint string, body []byte) *http.Response {
+	d.mu.Lock()
+	d.calls = append(d.calls, Call{Method: method, Path: endpoint, Body: body})
+	id := fmt.Sprintf("dry-run-%d", len(d.calls))
+	d.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"item":{"id":%q}}`, id))),
+	}
+}
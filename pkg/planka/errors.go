@@ -0,0 +1,53 @@
+package planka
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// APIError is an error response from the Planka API
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Method and Endpoint identify the request that failed
+	Method   string
+	Endpoint string
+	// Code and Message are Planka's error code (e.g. E_NOT_FOUND) and description, if it sent one
+	Code    string
+	Message string
+	// Body is the raw response body
+	Body string
+}
+
+// newAPIError creates an APIError from a failed response, decoding Planka's error details
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		Body:       string(body),
+	}
+	var details struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &details) == nil {
+		apiErr.Code = details.Code
+		apiErr.Message = details.Message
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	detail := e.Body
+	if e.Message != "" {
+		detail = e.Message
+	}
+	return fmt.Sprintf("API error (status %d) on %s %s: %s", e.StatusCode, e.Method, e.Endpoint, detail)
+}
+
+// Entity returns the kind of entity the failed request addressed, e.g. "card" for /api/cards/1/tasks
+func (e *APIError) Entity() string {
+	return strings.TrimSuffix(resourceOf(e.Endpoint), "s")
+}
@@ -0,0 +1,166 @@
+package planka_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// benchmarkInstance seeds srv with projects × boards × lists × cards and returns the IDs
+// of all lists
+func benchmarkInstance(srv *plankatest.Server, projects, boards, lists, cards int) []string {
+	var listIDs []string
+	for p := 0; p < projects; p++ {
+		projectID := srv.AddProject(fmt.Sprintf("Project %d", p))
+		for b := 0; b < boards; b++ {
+			boardID := srv.AddBoard(projectID, fmt.Sprintf("Board %d", b))
+			for l := 0; l < lists; l++ {
+				listID := srv.AddList(boardID, fmt.Sprintf("List %d", l))
+				listIDs = append(listIDs, listID)
+				for c := 0; c < cards; c++ {
+					srv.AddCard(listID, fmt.Sprintf("Card %d", c))
+				}
+			}
+		}
+	}
+	return listIDs
+}
+
+// withoutListEndpoint makes srv answer GET /api/lists/:id with HTML, like the Planka versions
+// that have no such endpoint, so GetCards has to search for the board of a list
+func withoutListEndpoint(srv *plankatest.Server) {
+	next := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/lists/") {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<!DOCTYPE html><html></html>"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BenchmarkGetCards measures GetCards on an instance of 10 projects with 4 boards of 4 lists
+// of 25 cards each, reporting the Planka requests each call makes. The times and allocations
+// include the fake server answering the requests.
+//
+// Before the search for the board of a list used the boards the projects response includes
+// and did not fetch the board it found again, and before boards were kept decoded with the
+// cache and their cards indexed by list (go test -run - -bench GetCards -benchmem ./pkg/planka):
+//
+//	BenchmarkGetCards/search     3  381604233 ns/op  52.00 requests/op  23765850 B/op  4309681 allocs/op
+//	BenchmarkGetCards/indexed  128    9284945 ns/op  1.000 requests/op    585648 B/op   104873 allocs/op
+//	BenchmarkGetCards/cached  6405     175077 ns/op      0 requests/op     71045 B/op      177 allocs/op
+//	BenchmarkGetCards/hydrated 145207    7949 ns/op      0 requests/op     13552 B/op        6 allocs/op
+//
+// After:
+//
+//	BenchmarkGetCards/search     3  404022236 ns/op  41.00 requests/op  25263709 B/op  4208346 allocs/op
+//	BenchmarkGetCards/indexed  121    9708806 ns/op  1.000 requests/op    632451 B/op   104967 allocs/op
+//	BenchmarkGetCards/cached 371180      3049 ns/op      0 requests/op      5376 B/op        1 allocs/op
+//	BenchmarkGetCards/hydrated 368497    3118 ns/op      0 requests/op      5376 B/op        1 allocs/op
+func BenchmarkGetCards(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("search", func(b *testing.B) {
+		srv := plankatest.NewServer(b)
+		lists := benchmarkInstance(srv, 10, 4, 4, 25)
+		withoutListEndpoint(srv)
+		// The last list is on the last board searched, the worst case
+		listID := lists[len(lists)-1]
+		before := len(srv.Requests())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// A new client has not seen any list yet
+			if _, err := srv.Client().GetCards(ctx, listID); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(len(srv.Requests())-before)/float64(b.N), "requests/op")
+	})
+
+	b.Run("indexed", func(b *testing.B) {
+		srv := plankatest.NewServer(b)
+		lists := benchmarkInstance(srv, 10, 4, 4, 25)
+		client := srv.Client()
+		if _, err := client.GetCards(ctx, lists[0]); err != nil {
+			b.Fatal(err)
+		}
+		before := len(srv.Requests())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.GetCards(ctx, lists[0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(len(srv.Requests())-before)/float64(b.N), "requests/op")
+	})
+
+	for _, bench := range []struct {
+		name string
+		opt  planka.Option
+	}{
+		{"cached", planka.WithCache(time.Hour)},
+		{"hydrated", planka.WithBoardHydration(time.Hour)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			srv := plankatest.NewServer(b)
+			lists := benchmarkInstance(srv, 10, 4, 4, 25)
+			client := srv.Client(bench.opt)
+			// The lists of the first board, fetched once
+			for _, listID := range lists[:4] {
+				if _, err := client.GetCards(ctx, listID); err != nil {
+					b.Fatal(err)
+				}
+			}
+			before := len(srv.Requests())
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.GetCards(ctx, lists[i%4]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(srv.Requests())-before)/float64(b.N), "requests/op")
+		})
+	}
+}
+
+func TestGetCardsSearchesIncludedBoards(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	lists := benchmarkInstance(srv, 3, 2, 2, 3)
+	withoutListEndpoint(srv)
+
+	listID := lists[len(lists)-1]
+	cards, err := srv.Client().GetCards(context.Background(), listID)
+	if err != nil {
+		t.Fatalf("GetCards: %v", err)
+	}
+	if len(cards) != 3 {
+		t.Errorf("GetCards returned %d cards, want 3", len(cards))
+	}
+	for _, card := range cards {
+		if card.ListID != listID {
+			t.Errorf("GetCards returned card %s of list %s, want only list %s", card.ID, card.ListID, listID)
+		}
+	}
+
+	// The projects response includes their boards, and the board found is not fetched again
+	var boards int
+	for _, request := range srv.Requests() {
+		if strings.HasPrefix(request, "GET /api/projects/") {
+			t.Errorf("GetCards requested %s, want the boards from the projects response", request)
+		}
+		if strings.HasPrefix(request, "GET /api/boards/") {
+			boards++
+		}
+	}
+	if boards != 6 {
+		t.Errorf("GetCards fetched %d boards, want each of the 6 once", boards)
+	}
+}
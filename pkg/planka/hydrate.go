@@ -0,0 +1,182 @@
+package planka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// hydratedBoard is a board together with everything its response included.
+// Tasks and comments are nil when Planka did not include them.
+type hydratedBoard struct {
+	board    Board
+	included Included
+	// listCards holds the included cards of each list, in the order Planka returned them
+	listCards map[string][]Card
+	fetchedAt time.Time
+}
+
+// newHydratedBoard indexes the cards board's response included by list
+func newHydratedBoard(board Board, included Included) *hydratedBoard {
+	b := &hydratedBoard{board: board, included: included, listCards: map[string][]Card{}, fetchedAt: time.Now()}
+	for _, card := range included.Cards {
+		b.listCards[card.ListID] = append(b.listCards[card.ListID], card)
+	}
+	return b
+}
+
+// cards returns the cards of listID, or nil if it has none
+func (b *hydratedBoard) cards(listID string) []Card {
+	// Callers may change the cards, so they get a copy of the held ones
+	return append([]Card(nil), b.listCards[listID]...)
+}
+
+// cardTasks returns the tasks of cardID, if the board response included tasks
+func (b *hydratedBoard) cardTasks(cardID string) ([]Task, bool) {
+	if b.included.Tasks == nil {
+		return nil, false
+	}
+	tasks := []Task{}
+	for _, task := range b.included.Tasks {
+		if task.CardID == cardID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, true
+}
+
+// cardComments returns the comments of cardID, if the board response included comments
+func (b *hydratedBoard) cardComments(cardID string) ([]Comment, bool) {
+	if b.included.Comments == nil {
+		return nil, false
+	}
+	comments := []Comment{}
+	for _, comment := range b.included.Comments {
+		if comment.CardID == cardID {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, true
+}
+
+// WithBoardHydration keeps every fetched board with its included lists, cards, labels, tasks
+// and memberships for window, so the card, task and comment lookups that usually follow are
+// answered from that one response instead of fetching the board or its cards again.
+// A window of 0 holds no boards, including the ones WithCache would keep.
+func WithBoardHydration(window time.Duration) Option {
+	return func(c *Client) {
+		if window > 0 {
+			c.boards = newBoardStore(window)
+		} else {
+			c.boards = nil
+		}
+	}
+}
+
+// newBoardStore creates a store holding boards for window
+func newBoardStore(window time.Duration) *boardStore {
+	return &boardStore{
+		window: window,
+		boards: map[string]*hydratedBoard{},
+		cards:  map[string]string{},
+	}
+}
+
+// boardStore holds recently fetched boards. A nil store holds nothing.
+type boardStore struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	boards map[string]*hydratedBoard
+	// cards maps the ID of every card on a held board to the board's ID
+	cards map[string]string
+}
+
+// board returns the held board boardID if it was fetched within the window
+func (s *boardStore) board(boardID string) (*hydratedBoard, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.boards[boardID]
+	if !ok || time.Since(b.fetchedAt) > s.window {
+		return nil, false
+	}
+	return b, true
+}
+
+// cardBoard returns the held board cardID is on if it was fetched within the window
+func (s *boardStore) cardBoard(cardID string) (*hydratedBoard, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	boardID, ok := s.cards[cardID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return s.board(boardID)
+}
+
+// add holds b, replacing an earlier fetch of the same board
+func (s *boardStore) add(b *hydratedBoard) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop boards past the window so the store does not grow forever
+	for id, held := range s.boards {
+		if time.Since(held.fetchedAt) > s.window {
+			s.forget(id)
+		}
+	}
+	s.forget(b.board.ID)
+	s.boards[b.board.ID] = b
+	for _, card := range b.included.Cards {
+		s.cards[card.ID] = b.board.ID
+	}
+}
+
+// forget drops boardID and its cards; s.mu must be held
+func (s *boardStore) forget(boardID string) {
+	for cardID, id := range s.cards {
+		if id == boardID {
+			delete(s.cards, cardID)
+		}
+	}
+	delete(s.boards, boardID)
+}
+
+// clear drops every held board
+func (s *boardStore) clear() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.boards)
+	clear(s.cards)
+}
+
+// fetchBoard returns boardID with its included sub-resources, from the store when it was
+// fetched within the hydration window
+func (c *Client) fetchBoard(ctx context.Context, boardID string) (*hydratedBoard, error) {
+	if b, ok := c.boards.board(boardID); ok && !freshReads(ctx) {
+		return b, nil
+	}
+
+	var resp itemResponse[Board]
+	if err := c.get(ctx, fmt.Sprintf("/api/boards/%s", boardID), &resp); err != nil {
+		return nil, err
+	}
+	b := newHydratedBoard(resp.Item, resp.Included)
+	c.lists.add(b.included.Lists...)
+	c.boards.add(b)
+	return b, nil
+}
@@ -0,0 +1,36 @@
+package planka
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ayushgarg/mcp-planka/internal/version"
+)
+
+// requestIDHeader carries a unique ID per request so it can be found in Planka's logs
+const requestIDHeader = "X-Request-Id"
+
+// WithUserAgent replaces the default User-Agent, planka-mcp/<version> (<go version>)
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// identify adds the headers that tell Planka's server logs the request came from planka-mcp
+func (c *Client) identify(header http.Header) {
+	userAgent := c.userAgent
+	if userAgent == "" {
+		userAgent = version.UserAgent()
+	}
+	header.Set("User-Agent", userAgent)
+	header.Set(requestIDHeader, newRequestID())
+}
+
+// newRequestID returns a random ID identifying a single request
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
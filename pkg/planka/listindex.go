@@ -0,0 +1,41 @@
+package planka
+
+import "sync"
+
+// listIndex remembers which board each list belongs to, so cards of a list can be
+// fetched from its board without searching the whole instance
+type listIndex struct {
+	mu     sync.RWMutex
+	boards map[string]string
+}
+
+// newListIndex creates an empty list index
+func newListIndex() *listIndex {
+	return &listIndex{boards: map[string]string{}}
+}
+
+// board returns the ID of the board list belongs to, if known
+func (i *listIndex) board(listID string) (string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	boardID, ok := i.boards[listID]
+	return boardID, ok
+}
+
+// add records that lists belong to their boards
+func (i *listIndex) add(lists ...List) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, list := range lists {
+		if list.ID != "" && list.BoardID != "" {
+			i.boards[list.ID] = list.BoardID
+		}
+	}
+}
+
+// remove forgets a deleted list
+func (i *listIndex) remove(listID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.boards, listID)
+}
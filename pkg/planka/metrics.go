@@ -0,0 +1,186 @@
+package planka
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencySamples is how many recent durations per endpoint percentiles are computed from
+const latencySamples = 1024
+
+// Metrics counts the requests clients send to Planka, per method and endpoint.
+// One Metrics may be shared by several clients to aggregate them. A nil Metrics records nothing.
+type Metrics struct {
+	mu        sync.Mutex
+	endpoints map[endpointKey]*endpointMetrics
+}
+
+// endpointKey identifies an endpoint with its IDs replaced, e.g. GET /api/cards/:id
+type endpointKey struct {
+	method   string
+	endpoint string
+}
+
+// endpointMetrics are the counters of a single endpoint
+type endpointMetrics struct {
+	requests int64
+	errors   int64
+	total    time.Duration
+	// recent is a ring of the latest durations
+	recent []time.Duration
+	next   int
+}
+
+// EndpointStats summarizes the requests sent to one endpoint
+type EndpointStats struct {
+	Method    string  `json:"method"`
+	Endpoint  string  `json:"endpoint"`
+	Requests  int64   `json:"requests"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	// Latency percentiles in milliseconds over the most recent requests
+	P50Ms float64 `json:"p50Ms"`
+	P90Ms float64 `json:"p90Ms"`
+	P99Ms float64 `json:"p99Ms"`
+	// TotalSeconds is the time spent in all requests
+	TotalSeconds float64 `json:"totalSeconds"`
+}
+
+// NewMetrics creates an empty set of client metrics
+func NewMetrics() *Metrics {
+	return &Metrics{endpoints: map[endpointKey]*endpointMetrics{}}
+}
+
+// WithMetrics records every request the client sends in m
+func WithMetrics(m *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// record counts a request; failed is true for network errors and error responses
+func (m *Metrics) record(method, path string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	key := endpointKey{method: method, endpoint: normalizeEndpoint(path)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.endpoints[key]
+	if !ok {
+		e = &endpointMetrics{}
+		m.endpoints[key] = e
+	}
+	e.requests++
+	if failed {
+		e.errors++
+	}
+	e.total += duration
+	if len(e.recent) < latencySamples {
+		e.recent = append(e.recent, duration)
+	} else {
+		e.recent[e.next] = duration
+		e.next = (e.next + 1) % latencySamples
+	}
+}
+
+// Snapshot returns the statistics of every endpoint requested so far, sorted by endpoint
+func (m *Metrics) Snapshot() []EndpointStats {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]EndpointStats, 0, len(m.endpoints))
+	for key, e := range m.endpoints {
+		sorted := append([]time.Duration(nil), e.recent...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		stats = append(stats, EndpointStats{
+			Method:       key.method,
+			Endpoint:     key.endpoint,
+			Requests:     e.requests,
+			Errors:       e.errors,
+			ErrorRate:    float64(e.errors) / float64(e.requests),
+			P50Ms:        milliseconds(percentile(sorted, 0.5)),
+			P90Ms:        milliseconds(percentile(sorted, 0.9)),
+			P99Ms:        milliseconds(percentile(sorted, 0.99)),
+			TotalSeconds: e.total.Seconds(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Endpoint != stats[j].Endpoint {
+			return stats[i].Endpoint < stats[j].Endpoint
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	stats := m.Snapshot()
+
+	fmt.Fprintln(w, "# HELP planka_client_requests_total Requests sent to Planka.")
+	fmt.Fprintln(w, "# TYPE planka_client_requests_total counter")
+	for _, s := range stats {
+		fmt.Fprintf(w, "planka_client_requests_total{%s} %d\n", labels(s), s.Requests)
+	}
+
+	fmt.Fprintln(w, "# HELP planka_client_request_errors_total Requests to Planka that failed or returned an error status.")
+	fmt.Fprintln(w, "# TYPE planka_client_request_errors_total counter")
+	for _, s := range stats {
+		fmt.Fprintf(w, "planka_client_request_errors_total{%s} %d\n", labels(s), s.Errors)
+	}
+
+	fmt.Fprintln(w, "# HELP planka_client_request_duration_seconds Duration of requests to Planka.")
+	fmt.Fprintln(w, "# TYPE planka_client_request_duration_seconds summary")
+	for _, s := range stats {
+		for _, q := range []struct {
+			quantile string
+			ms       float64
+		}{{"0.5", s.P50Ms}, {"0.9", s.P90Ms}, {"0.99", s.P99Ms}} {
+			fmt.Fprintf(w, "planka_client_request_duration_seconds{%s,quantile=%q} %g\n", labels(s), q.quantile, q.ms/1000)
+		}
+		fmt.Fprintf(w, "planka_client_request_duration_seconds_sum{%s} %g\n", labels(s), s.TotalSeconds)
+		fmt.Fprintf(w, "planka_client_request_duration_seconds_count{%s} %d\n", labels(s), s.Requests)
+	}
+}
+
+// labels formats the Prometheus labels of an endpoint
+func labels(s EndpointStats) string {
+	return fmt.Sprintf("method=%q,endpoint=%q", s.Method, s.Endpoint)
+}
+
+// normalizeEndpoint strips the query and replaces IDs in path, so requests for different
+// entities are counted together: /api/cards/123/comments becomes /api/cards/:id/comments
+func normalizeEndpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		// Planka's IDs are numeric; resource names never contain digits
+		if strings.ContainsAny(segment, "0123456789") {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// percentile returns the q-th percentile of sorted durations
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(q*float64(len(sorted)-1)+0.5)]
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,289 @@
+package planka
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User represents a Planka user
+type User struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// Project represents a Planka project
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Boards      []Board   `json:"boards,omitempty"`
+}
+
+// Board represents a Planka board
+type Board struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ProjectID   string    `json:"projectId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Lists       []List    `json:"lists,omitempty"`
+}
+
+// List represents a Planka list
+type List struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	BoardID   string    `json:"boardId"`
+	// Type is "active", "closed", "archive" or "trash" on Planka 2; empty before
+	Type      string    `json:"type,omitempty"`
+	Position  float64   `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Cards     []Card    `json:"cards,omitempty"`
+}
+
+// Card represents a Planka card
+type Card struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ListID      string    `json:"listId"`
+	Position    float64   `json:"position"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	// ListChangedAt is when the card last moved to another list; Planka 2 only
+	ListChangedAt *time.Time `json:"listChangedAt,omitempty"`
+	Tasks       []Task    `json:"tasks,omitempty"`
+	Comments    []Comment `json:"comments,omitempty"`
+	Labels      []Label   `json:"labels,omitempty"`
+}
+
+// Task represents a task within a card
+type Task struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CardID    string    `json:"cardId"`
+	Position  float64   `json:"position"`
+	IsCompleted bool    `json:"isCompleted"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Comment represents a comment on a card
+type Comment struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	CardID    string    `json:"cardId"`
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Attachment represents a file or link attached to a card
+type Attachment struct {
+	ID        string    `json:"id"`
+	CardID    string    `json:"cardId"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Label represents a label on a card
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// BoardMembership grants a user access to a board
+type BoardMembership struct {
+	ID      string `json:"id"`
+	BoardID string `json:"boardId"`
+	UserID  string `json:"userId"`
+	Role    string `json:"role"`
+}
+
+// CardMembership assigns a user to a card
+type CardMembership struct {
+	ID     string `json:"id"`
+	CardID string `json:"cardId"`
+	UserID string `json:"userId"`
+}
+
+// CardLabel attaches a board label to a card
+type CardLabel struct {
+	ID      string `json:"id"`
+	CardID  string `json:"cardId"`
+	LabelID string `json:"labelId"`
+}
+
+// CustomField is a field of a custom field group, e.g. "Start date"; Planka 2 only
+type CustomField struct {
+	ID                 string  `json:"id"`
+	CustomFieldGroupID string  `json:"customFieldGroupId"`
+	Name               string  `json:"name"`
+	Position           float64 `json:"position"`
+}
+
+// CustomFieldValue is the content of a custom field on a card; Planka 2 only
+type CustomFieldValue struct {
+	ID                 string `json:"id"`
+	CardID             string `json:"cardId"`
+	CustomFieldGroupID string `json:"customFieldGroupId"`
+	CustomFieldID      string `json:"customFieldId"`
+	Content            string `json:"content"`
+}
+
+// Included holds the related entities Planka sends alongside a response's item or items.
+// Collections the response did not include are nil, included but empty ones are not.
+type Included struct {
+	Users             []User             `json:"users"`
+	Projects          []Project          `json:"projects"`
+	Boards            []Board            `json:"boards"`
+	BoardMemberships  []BoardMembership  `json:"boardMemberships"`
+	Lists             []List             `json:"lists"`
+	Labels            []Label            `json:"labels"`
+	Cards             []Card             `json:"cards"`
+	CardMemberships   []CardMembership   `json:"cardMemberships"`
+	CardLabels        []CardLabel        `json:"cardLabels"`
+	CustomFields      []CustomField      `json:"customFields"`
+	CustomFieldValues []CustomFieldValue `json:"customFieldValues"`
+	Tasks             []Task             `json:"tasks"`
+	Comments          []Comment          `json:"comments"`
+	Attachments       []Attachment       `json:"attachments"`
+}
+
+// CardDetails is a card together with the related entities its response included
+type CardDetails struct {
+	Card Card
+	// BoardID is the board the card is on, which Planka sends with the card
+	BoardID  string
+	Included Included
+}
+
+// itemResponse is Planka's response for a single entity
+type itemResponse[T any] struct {
+	Item     T        `json:"item"`
+	Included Included `json:"included"`
+}
+
+// itemsResponse is Planka's response for a collection of entities
+type itemsResponse[T any] struct {
+	Items    []T      `json:"items"`
+	Included Included `json:"included"`
+}
+
+// Stopwatch represents a time tracking stopwatch
+type Stopwatch struct {
+	ID        string     `json:"id"`
+	CardID    string     `json:"cardId"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Duration  int64      `json:"duration"` // Duration in seconds
+}
+
+// CreateProjectRequest represents a request to create a project
+type CreateProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateBoardRequest represents a request to create a board
+type CreateBoardRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	ProjectID   string  `json:"projectId"`
+	Position    float64 `json:"position,omitempty"`
+}
+
+// CreateListRequest represents a request to create a list
+type CreateListRequest struct {
+	Name    string `json:"name"`
+	BoardID string `json:"boardId"`
+	// Position is required by the API; without one the list goes after the board's other lists
+	Position *float64 `json:"position,omitempty"`
+}
+
+// UpdateListRequest represents a request to update a list
+type UpdateListRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	Position *float64 `json:"position,omitempty"`
+}
+
+// CreateLabelRequest represents a request to create a label on a board
+type CreateLabelRequest struct {
+	BoardID  string  `json:"boardId"`
+	Name     string  `json:"name"`
+	// Color is one of Planka's label colors, e.g. "berry-red"
+	Color    string  `json:"color"`
+	Position float64 `json:"position"`
+}
+
+// CreateCardRequest represents a request to create a card
+type CreateCardRequest struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	ListID      string     `json:"listId"`
+	Position    *float64   `json:"position,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+}
+
+// UpdateCardRequest represents a request to update a card
+type UpdateCardRequest struct {
+	Name        *string     `json:"name,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	ListID      *string     `json:"listId,omitempty"`
+	Position    *float64    `json:"position,omitempty"`
+	DueDate     *time.Time  `json:"dueDate,omitempty"`
+	// ClearDescription and ClearDueDate remove the description and due date, taking
+	// precedence over Description and DueDate
+	ClearDescription bool `json:"-"`
+	ClearDueDate     bool `json:"-"`
+}
+
+// MarshalJSON encodes the fields to change, sending the fields to clear as null
+func (r UpdateCardRequest) MarshalJSON() ([]byte, error) {
+	// fields has the same fields without this method
+	type fields UpdateCardRequest
+	data, err := json.Marshal(fields(r))
+	if err != nil || (!r.ClearDescription && !r.ClearDueDate) {
+		return data, err
+	}
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	if r.ClearDescription {
+		body["description"] = json.RawMessage("null")
+	}
+	if r.ClearDueDate {
+		body["dueDate"] = json.RawMessage("null")
+	}
+	return json.Marshal(body)
+}
+
+// CreateTaskRequest represents a request to create a task
+type CreateTaskRequest struct {
+	Name     string   `json:"name"`
+	CardID   string   `json:"cardId"`
+	Position *float64 `json:"position,omitempty"`
+}
+
+// UpdateTaskRequest represents a request to update a task
+type UpdateTaskRequest struct {
+	Name        *string `json:"name,omitempty"`
+	IsCompleted *bool   `json:"isCompleted,omitempty"`
+	Position    *float64 `json:"position,omitempty"`
+}
+
+// CreateCommentRequest represents a request to create a comment
+type CreateCommentRequest struct {
+	Text   string `json:"text"`
+	CardID string `json:"cardId"`
+}
+
@@ -0,0 +1,79 @@
+package planka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// pageSize is the number of items Planka returns per page of a paginated collection
+	pageSize = 50
+	// defaultMaxItems bounds how many items are collected by following pages
+	defaultMaxItems = 10000
+)
+
+// ErrTruncated reports, wrapped, that a paginated collection has more items than the client
+// fetches (see WithMaxItems). It is returned along with the items that were fetched.
+var ErrTruncated = errors.New("collection has more items than the item limit")
+
+// WithMaxItems caps how many items of a paginated collection are fetched by following
+// its pages. Zero or less removes the cap.
+func WithMaxItems(n int) Option {
+	return func(c *Client) {
+		c.maxItems = n
+	}
+}
+
+// getAllPages fetches every page of a collection that Planka paginates with beforeId,
+// newest items first, up to the client's item cap. Servers that do not paginate return
+// the same items again, which ends the walk. A collection cut at the cap is returned with
+// an ErrTruncated error.
+func getAllPages[T any](ctx context.Context, c *Client, endpoint string, id func(T) string) ([]T, error) {
+	var all []T
+	seen := map[string]bool{}
+
+	pageURL := endpoint
+	for {
+		var resp itemsResponse[T]
+		if err := c.get(ctx, pageURL, &resp); err != nil {
+			return nil, err
+		}
+		items := resp.Items
+
+		added := 0
+		for i, item := range items {
+			key := id(item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			all = append(all, item)
+			added++
+
+			if c.maxItems > 0 && len(all) >= c.maxItems {
+				// More items follow on this page or, after a full page, likely on the next
+				if i < len(items)-1 || len(items) >= pageSize {
+					return all, fmt.Errorf("%w: stopped fetching %s at %d items", ErrTruncated, endpoint, c.maxItems)
+				}
+				return all, nil
+			}
+		}
+
+		if len(items) < pageSize || added == 0 {
+			return all, nil
+		}
+		pageURL = withQuery(endpoint, "beforeId", id(items[len(items)-1]))
+	}
+}
+
+// withQuery appends a query parameter to endpoint
+func withQuery(endpoint, key, value string) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s%s=%s", endpoint, sep, url.QueryEscape(key), url.QueryEscape(value))
+}
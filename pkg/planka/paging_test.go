@@ -0,0 +1,29 @@
+package planka_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestGetCommentsReportsItemLimit(t *testing.T) {
+	srv := plankatest.NewServer(t)
+	cardID := srv.AddCard(srv.AddList(srv.AddBoard(srv.AddProject("Project"), "Board"), "Todo"), "Card")
+	for i := 0; i < 5; i++ {
+		srv.AddComment(cardID, fmt.Sprintf("Comment %d", i))
+	}
+
+	comments, err := srv.Client(planka.WithMaxItems(3)).GetComments(context.Background(), cardID)
+	if !errors.Is(err, planka.ErrTruncated) || len(comments) != 3 {
+		t.Errorf("GetComments = %d comments, %v, want 3 with ErrTruncated", len(comments), err)
+	}
+
+	comments, err = srv.Client(planka.WithMaxItems(5)).GetComments(context.Background(), cardID)
+	if err != nil || len(comments) != 5 {
+		t.Errorf("GetComments at exactly the limit = %d comments, %v, want all 5", len(comments), err)
+	}
+}
@@ -0,0 +1,468 @@
+// Package plankamock provides a stand-in for the Planka client in tests. Each method
+// calls the function of the same name with a Func suffix, e.g. GetProjectsFunc, and
+// fails with ErrNotMocked when it is unset. Every call is recorded.
+package plankamock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+// ErrNotMocked is returned by methods whose function is not set
+var ErrNotMocked = errors.New("method not mocked")
+
+// Call is a recorded method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client is a Planka client whose methods are provided by the test
+type Client struct {
+	GetMeFunc                func(ctx context.Context) (*planka.User, error)
+	GetProjectsFunc          func(ctx context.Context) ([]planka.Project, error)
+	GetProjectFunc           func(ctx context.Context, projectID string) (*planka.Project, error)
+	CreateProjectFunc        func(ctx context.Context, req planka.CreateProjectRequest) (*planka.Project, error)
+	DeleteProjectFunc        func(ctx context.Context, projectID string) error
+	GetBoardsFunc            func(ctx context.Context, projectID string) ([]planka.Board, error)
+	GetBoardFunc             func(ctx context.Context, boardID string) (*planka.Board, error)
+	CreateBoardFunc          func(ctx context.Context, req planka.CreateBoardRequest) (*planka.Board, error)
+	DeleteBoardFunc          func(ctx context.Context, boardID string) error
+	GetListsFunc             func(ctx context.Context, boardID string) ([]planka.List, error)
+	GetLabelsFunc            func(ctx context.Context, boardID string) ([]planka.Label, error)
+	GetBoardCardsFunc        func(ctx context.Context, boardID string) ([]planka.Card, error)
+	GetCardMembershipsFunc   func(ctx context.Context, boardID string) ([]planka.CardMembership, error)
+	GetCardLabelsFunc        func(ctx context.Context, boardID string) ([]planka.CardLabel, error)
+	GetCustomFieldsFunc      func(ctx context.Context, boardID string) ([]planka.CustomField, []planka.CustomFieldValue, error)
+	GetBoardUsersFunc        func(ctx context.Context, boardID string) ([]planka.User, error)
+	CreateLabelFunc          func(ctx context.Context, req planka.CreateLabelRequest) (*planka.Label, error)
+	AddCardLabelFunc         func(ctx context.Context, cardID, labelID string) error
+	AddCardMemberFunc        func(ctx context.Context, cardID, userID string) error
+	GetListFunc              func(ctx context.Context, listID string) (*planka.List, error)
+	CreateListFunc           func(ctx context.Context, req planka.CreateListRequest) (*planka.List, error)
+	UpdateListFunc           func(ctx context.Context, listID string, req planka.UpdateListRequest) (*planka.List, error)
+	DeleteListFunc           func(ctx context.Context, listID string) error
+	GetCardsFunc             func(ctx context.Context, listID string) ([]planka.Card, error)
+	GetCardFunc              func(ctx context.Context, cardID string) (*planka.Card, error)
+	GetCardDetailsFunc       func(ctx context.Context, cardID string) (*planka.CardDetails, error)
+	CreateCardFunc           func(ctx context.Context, req planka.CreateCardRequest) (*planka.Card, error)
+	UpdateCardFunc           func(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error)
+	DeleteCardFunc           func(ctx context.Context, cardID string) error
+	MoveCardFunc             func(ctx context.Context, cardID, listID string, position float64) (*planka.Card, error)
+	GetTasksFunc             func(ctx context.Context, cardID string) ([]planka.Task, error)
+	CreateTaskFunc           func(ctx context.Context, req planka.CreateTaskRequest) (*planka.Task, error)
+	UpdateTaskFunc           func(ctx context.Context, taskID string, req planka.UpdateTaskRequest) (*planka.Task, error)
+	DeleteTaskFunc           func(ctx context.Context, taskID string) error
+	GetCommentsFunc          func(ctx context.Context, cardID string) ([]planka.Comment, error)
+	CreateCommentFunc        func(ctx context.Context, req planka.CreateCommentRequest) (*planka.Comment, error)
+	DeleteCommentFunc        func(ctx context.Context, commentID string) error
+	GetStopwatchFunc         func(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	StartStopwatchFunc       func(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	StopStopwatchFunc        func(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	ResetStopwatchFunc       func(ctx context.Context, cardID string) (*planka.Stopwatch, error)
+	SetStopwatchDurationFunc func(ctx context.Context, cardID string, duration int64) (*planka.Stopwatch, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls made so far, in order
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// record notes a call of method with args
+func (c *Client) record(method string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+}
+
+// notMocked returns the error of a method whose function is not set
+func notMocked(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotMocked)
+}
+
+// GetMe calls GetMeFunc
+func (c *Client) GetMe(ctx context.Context) (*planka.User, error) {
+	c.record("GetMe")
+	if c.GetMeFunc == nil {
+		return nil, notMocked("GetMe")
+	}
+	return c.GetMeFunc(ctx)
+}
+
+// GetProjects calls GetProjectsFunc
+func (c *Client) GetProjects(ctx context.Context) ([]planka.Project, error) {
+	c.record("GetProjects")
+	if c.GetProjectsFunc == nil {
+		return nil, notMocked("GetProjects")
+	}
+	return c.GetProjectsFunc(ctx)
+}
+
+// GetProject calls GetProjectFunc
+func (c *Client) GetProject(ctx context.Context, projectID string) (*planka.Project, error) {
+	c.record("GetProject", projectID)
+	if c.GetProjectFunc == nil {
+		return nil, notMocked("GetProject")
+	}
+	return c.GetProjectFunc(ctx, projectID)
+}
+
+// CreateProject calls CreateProjectFunc
+func (c *Client) CreateProject(ctx context.Context, req planka.CreateProjectRequest) (*planka.Project, error) {
+	c.record("CreateProject", req)
+	if c.CreateProjectFunc == nil {
+		return nil, notMocked("CreateProject")
+	}
+	return c.CreateProjectFunc(ctx, req)
+}
+
+// DeleteProject calls DeleteProjectFunc
+func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
+	c.record("DeleteProject", projectID)
+	if c.DeleteProjectFunc == nil {
+		return notMocked("DeleteProject")
+	}
+	return c.DeleteProjectFunc(ctx, projectID)
+}
+
+// GetBoards calls GetBoardsFunc
+func (c *Client) GetBoards(ctx context.Context, projectID string) ([]planka.Board, error) {
+	c.record("GetBoards", projectID)
+	if c.GetBoardsFunc == nil {
+		return nil, notMocked("GetBoards")
+	}
+	return c.GetBoardsFunc(ctx, projectID)
+}
+
+// GetBoard calls GetBoardFunc
+func (c *Client) GetBoard(ctx context.Context, boardID string) (*planka.Board, error) {
+	c.record("GetBoard", boardID)
+	if c.GetBoardFunc == nil {
+		return nil, notMocked("GetBoard")
+	}
+	return c.GetBoardFunc(ctx, boardID)
+}
+
+// CreateBoard calls CreateBoardFunc
+func (c *Client) CreateBoard(ctx context.Context, req planka.CreateBoardRequest) (*planka.Board, error) {
+	c.record("CreateBoard", req)
+	if c.CreateBoardFunc == nil {
+		return nil, notMocked("CreateBoard")
+	}
+	return c.CreateBoardFunc(ctx, req)
+}
+
+// DeleteBoard calls DeleteBoardFunc
+func (c *Client) DeleteBoard(ctx context.Context, boardID string) error {
+	c.record("DeleteBoard", boardID)
+	if c.DeleteBoardFunc == nil {
+		return notMocked("DeleteBoard")
+	}
+	return c.DeleteBoardFunc(ctx, boardID)
+}
+
+// GetLists calls GetListsFunc
+func (c *Client) GetLists(ctx context.Context, boardID string) ([]planka.List, error) {
+	c.record("GetLists", boardID)
+	if c.GetListsFunc == nil {
+		return nil, notMocked("GetLists")
+	}
+	return c.GetListsFunc(ctx, boardID)
+}
+
+// GetLabels calls GetLabelsFunc
+func (c *Client) GetLabels(ctx context.Context, boardID string) ([]planka.Label, error) {
+	c.record("GetLabels", boardID)
+	if c.GetLabelsFunc == nil {
+		return nil, notMocked("GetLabels")
+	}
+	return c.GetLabelsFunc(ctx, boardID)
+}
+
+// GetBoardCards calls GetBoardCardsFunc
+func (c *Client) GetBoardCards(ctx context.Context, boardID string) ([]planka.Card, error) {
+	c.record("GetBoardCards", boardID)
+	if c.GetBoardCardsFunc == nil {
+		return nil, notMocked("GetBoardCards")
+	}
+	return c.GetBoardCardsFunc(ctx, boardID)
+}
+
+// GetCardMemberships calls GetCardMembershipsFunc
+func (c *Client) GetCardMemberships(ctx context.Context, boardID string) ([]planka.CardMembership, error) {
+	c.record("GetCardMemberships", boardID)
+	if c.GetCardMembershipsFunc == nil {
+		return nil, notMocked("GetCardMemberships")
+	}
+	return c.GetCardMembershipsFunc(ctx, boardID)
+}
+
+// GetCardLabels calls GetCardLabelsFunc
+func (c *Client) GetCardLabels(ctx context.Context, boardID string) ([]planka.CardLabel, error) {
+	c.record("GetCardLabels", boardID)
+	if c.GetCardLabelsFunc == nil {
+		return nil, notMocked("GetCardLabels")
+	}
+	return c.GetCardLabelsFunc(ctx, boardID)
+}
+
+// GetCustomFields calls GetCustomFieldsFunc
+func (c *Client) GetCustomFields(ctx context.Context, boardID string) ([]planka.CustomField, []planka.CustomFieldValue, error) {
+	c.record("GetCustomFields", boardID)
+	if c.GetCustomFieldsFunc == nil {
+		return nil, nil, notMocked("GetCustomFields")
+	}
+	return c.GetCustomFieldsFunc(ctx, boardID)
+}
+
+// GetBoardUsers calls GetBoardUsersFunc
+func (c *Client) GetBoardUsers(ctx context.Context, boardID string) ([]planka.User, error) {
+	c.record("GetBoardUsers", boardID)
+	if c.GetBoardUsersFunc == nil {
+		return nil, notMocked("GetBoardUsers")
+	}
+	return c.GetBoardUsersFunc(ctx, boardID)
+}
+
+// CreateLabel calls CreateLabelFunc
+func (c *Client) CreateLabel(ctx context.Context, req planka.CreateLabelRequest) (*planka.Label, error) {
+	c.record("CreateLabel", req)
+	if c.CreateLabelFunc == nil {
+		return nil, notMocked("CreateLabel")
+	}
+	return c.CreateLabelFunc(ctx, req)
+}
+
+// AddCardLabel calls AddCardLabelFunc
+func (c *Client) AddCardLabel(ctx context.Context, cardID, labelID string) error {
+	c.record("AddCardLabel", cardID, labelID)
+	if c.AddCardLabelFunc == nil {
+		return notMocked("AddCardLabel")
+	}
+	return c.AddCardLabelFunc(ctx, cardID, labelID)
+}
+
+// AddCardMember calls AddCardMemberFunc
+func (c *Client) AddCardMember(ctx context.Context, cardID, userID string) error {
+	c.record("AddCardMember", cardID, userID)
+	if c.AddCardMemberFunc == nil {
+		return notMocked("AddCardMember")
+	}
+	return c.AddCardMemberFunc(ctx, cardID, userID)
+}
+
+// GetList calls GetListFunc
+func (c *Client) GetList(ctx context.Context, listID string) (*planka.List, error) {
+	c.record("GetList", listID)
+	if c.GetListFunc == nil {
+		return nil, notMocked("GetList")
+	}
+	return c.GetListFunc(ctx, listID)
+}
+
+// CreateList calls CreateListFunc
+func (c *Client) CreateList(ctx context.Context, req planka.CreateListRequest) (*planka.List, error) {
+	c.record("CreateList", req)
+	if c.CreateListFunc == nil {
+		return nil, notMocked("CreateList")
+	}
+	return c.CreateListFunc(ctx, req)
+}
+
+// UpdateList calls UpdateListFunc
+func (c *Client) UpdateList(ctx context.Context, listID string, req planka.UpdateListRequest) (*planka.List, error) {
+	c.record("UpdateList", listID, req)
+	if c.UpdateListFunc == nil {
+		return nil, notMocked("UpdateList")
+	}
+	return c.UpdateListFunc(ctx, listID, req)
+}
+
+// DeleteList calls DeleteListFunc
+func (c *Client) DeleteList(ctx context.Context, listID string) error {
+	c.record("DeleteList", listID)
+	if c.DeleteListFunc == nil {
+		return notMocked("DeleteList")
+	}
+	return c.DeleteListFunc(ctx, listID)
+}
+
+// GetCards calls GetCardsFunc
+func (c *Client) GetCards(ctx context.Context, listID string) ([]planka.Card, error) {
+	c.record("GetCards", listID)
+	if c.GetCardsFunc == nil {
+		return nil, notMocked("GetCards")
+	}
+	return c.GetCardsFunc(ctx, listID)
+}
+
+// GetCard calls GetCardFunc
+func (c *Client) GetCard(ctx context.Context, cardID string) (*planka.Card, error) {
+	c.record("GetCard", cardID)
+	if c.GetCardFunc == nil {
+		return nil, notMocked("GetCard")
+	}
+	return c.GetCardFunc(ctx, cardID)
+}
+
+// GetCardDetails calls GetCardDetailsFunc
+func (c *Client) GetCardDetails(ctx context.Context, cardID string) (*planka.CardDetails, error) {
+	c.record("GetCardDetails", cardID)
+	if c.GetCardDetailsFunc == nil {
+		return nil, notMocked("GetCardDetails")
+	}
+	return c.GetCardDetailsFunc(ctx, cardID)
+}
+
+// CreateCard calls CreateCardFunc
+func (c *Client) CreateCard(ctx context.Context, req planka.CreateCardRequest) (*planka.Card, error) {
+	c.record("CreateCard", req)
+	if c.CreateCardFunc == nil {
+		return nil, notMocked("CreateCard")
+	}
+	return c.CreateCardFunc(ctx, req)
+}
+
+// UpdateCard calls UpdateCardFunc
+func (c *Client) UpdateCard(ctx context.Context, cardID string, req planka.UpdateCardRequest) (*planka.Card, error) {
+	c.record("UpdateCard", cardID, req)
+	if c.UpdateCardFunc == nil {
+		return nil, notMocked("UpdateCard")
+	}
+	return c.UpdateCardFunc(ctx, cardID, req)
+}
+
+// DeleteCard calls DeleteCardFunc
+func (c *Client) DeleteCard(ctx context.Context, cardID string) error {
+	c.record("DeleteCard", cardID)
+	if c.DeleteCardFunc == nil {
+		return notMocked("DeleteCard")
+	}
+	return c.DeleteCardFunc(ctx, cardID)
+}
+
+// MoveCard calls MoveCardFunc
+func (c *Client) MoveCard(ctx context.Context, cardID, listID string, position float64) (*planka.Card, error) {
+	c.record("MoveCard", cardID, listID, position)
+	if c.MoveCardFunc == nil {
+		return nil, notMocked("MoveCard")
+	}
+	return c.MoveCardFunc(ctx, cardID, listID, position)
+}
+
+// GetTasks calls GetTasksFunc
+func (c *Client) GetTasks(ctx context.Context, cardID string) ([]planka.Task, error) {
+	c.record("GetTasks", cardID)
+	if c.GetTasksFunc == nil {
+		return nil, notMocked("GetTasks")
+	}
+	return c.GetTasksFunc(ctx, cardID)
+}
+
+// CreateTask calls CreateTaskFunc
+func (c *Client) CreateTask(ctx context.Context, req planka.CreateTaskRequest) (*planka.Task, error) {
+	c.record("CreateTask", req)
+	if c.CreateTaskFunc == nil {
+		return nil, notMocked("CreateTask")
+	}
+	return c.CreateTaskFunc(ctx, req)
+}
+
+// UpdateTask calls UpdateTaskFunc
+func (c *Client) UpdateTask(ctx context.Context, taskID string, req planka.UpdateTaskRequest) (*planka.Task, error) {
+	c.record("UpdateTask", taskID, req)
+	if c.UpdateTaskFunc == nil {
+		return nil, notMocked("UpdateTask")
+	}
+	return c.UpdateTaskFunc(ctx, taskID, req)
+}
+
+// DeleteTask calls DeleteTaskFunc
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	c.record("DeleteTask", taskID)
+	if c.DeleteTaskFunc == nil {
+		return notMocked("DeleteTask")
+	}
+	return c.DeleteTaskFunc(ctx, taskID)
+}
+
+// GetComments calls GetCommentsFunc
+func (c *Client) GetComments(ctx context.Context, cardID string) ([]planka.Comment, error) {
+	c.record("GetComments", cardID)
+	if c.GetCommentsFunc == nil {
+		return nil, notMocked("GetComments")
+	}
+	return c.GetCommentsFunc(ctx, cardID)
+}
+
+// CreateComment calls CreateCommentFunc
+func (c *Client) CreateComment(ctx context.Context, req planka.CreateCommentRequest) (*planka.Comment, error) {
+	c.record("CreateComment", req)
+	if c.CreateCommentFunc == nil {
+		return nil, notMocked("CreateComment")
+	}
+	return c.CreateCommentFunc(ctx, req)
+}
+
+// DeleteComment calls DeleteCommentFunc
+func (c *Client) DeleteComment(ctx context.Context, commentID string) error {
+	c.record("DeleteComment", commentID)
+	if c.DeleteCommentFunc == nil {
+		return notMocked("DeleteComment")
+	}
+	return c.DeleteCommentFunc(ctx, commentID)
+}
+
+// GetStopwatch calls GetStopwatchFunc
+func (c *Client) GetStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+	c.record("GetStopwatch", cardID)
+	if c.GetStopwatchFunc == nil {
+		return nil, notMocked("GetStopwatch")
+	}
+	return c.GetStopwatchFunc(ctx, cardID)
+}
+
+// StartStopwatch calls StartStopwatchFunc
+func (c *Client) StartStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+	c.record("StartStopwatch", cardID)
+	if c.StartStopwatchFunc == nil {
+		return nil, notMocked("StartStopwatch")
+	}
+	return c.StartStopwatchFunc(ctx, cardID)
+}
+
+// StopStopwatch calls StopStopwatchFunc
+func (c *Client) StopStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+	c.record("StopStopwatch", cardID)
+	if c.StopStopwatchFunc == nil {
+		return nil, notMocked("StopStopwatch")
+	}
+	return c.StopStopwatchFunc(ctx, cardID)
+}
+
+// ResetStopwatch calls ResetStopwatchFunc
+func (c *Client) ResetStopwatch(ctx context.Context, cardID string) (*planka.Stopwatch, error) {
+	c.record("ResetStopwatch", cardID)
+	if c.ResetStopwatchFunc == nil {
+		return nil, notMocked("ResetStopwatch")
+	}
+	return c.ResetStopwatchFunc(ctx, cardID)
+}
+
+// SetStopwatchDuration calls SetStopwatchDurationFunc
+func (c *Client) SetStopwatchDuration(ctx context.Context, cardID string, duration int64) (*planka.Stopwatch, error) {
+	c.record("SetStopwatchDuration", cardID, duration)
+	if c.SetStopwatchDurationFunc == nil {
+		return nil, notMocked("SetStopwatchDuration")
+	}
+	return c.SetStopwatchDurationFunc(ctx, cardID, duration)
+}
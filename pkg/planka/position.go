@@ -0,0 +1,32 @@
+package planka
+
+import (
+	"context"
+	"strings"
+)
+
+// PositionGap is the distance Planka leaves between the positions of neighboring entities
+const PositionGap = 65535
+
+// lastPosition returns the position after the highest of items, so an entity created
+// without a position goes to the end instead of on top of its siblings
+func lastPosition[T any](items []T, position func(T) float64) float64 {
+	last := 0.0
+	for _, item := range items {
+		last = max(last, position(item))
+	}
+	return last + PositionGap
+}
+
+// nextPosition returns the position after the siblings load returns. A parent created
+// earlier in the same dry run has no siblings yet, so it gets the first position.
+func nextPosition[T any](ctx context.Context, parentID string, load func(context.Context) ([]T, error), position func(T) float64) (float64, error) {
+	if DryRunFromContext(ctx) != nil && strings.HasPrefix(parentID, "dry-run-") {
+		return PositionGap, nil
+	}
+	items, err := load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return lastPosition(items, position), nil
+}
@@ -0,0 +1,98 @@
+package planka_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ayushgarg/mcp-planka/internal/plankatest"
+	"github.com/ayushgarg/mcp-planka/pkg/planka"
+)
+
+func TestCreateWithoutPositionGoesLast(t *testing.T) {
+	ctx := context.Background()
+	srv := plankatest.NewServer(t)
+	boardID := srv.AddBoard(srv.AddProject("Project"), "Board")
+	listID := srv.AddList(boardID, "Todo")
+	srv.AddList(boardID, "Done")
+	cardID := srv.AddCard(listID, "First")
+	srv.AddCard(listID, "Second")
+	srv.AddTask(cardID, "Outline")
+	client := srv.Client()
+
+	list, err := client.CreateList(ctx, planka.CreateListRequest{Name: "Later", BoardID: boardID})
+	if err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	if list.Position != 3*65535 {
+		t.Errorf("CreateList position = %v, want %v after the 2 lists", list.Position, 3*65535)
+	}
+
+	for i, want := range []float64{3 * 65535, 4 * 65535} {
+		card, err := client.CreateCard(ctx, planka.CreateCardRequest{Name: "New", ListID: listID})
+		if err != nil {
+			t.Fatalf("CreateCard: %v", err)
+		}
+		if card.Position != want {
+			t.Errorf("CreateCard #%d position = %v, want %v after the cards before it", i+1, card.Position, want)
+		}
+	}
+
+	task, err := client.CreateTask(ctx, planka.CreateTaskRequest{Name: "Draft", CardID: cardID})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if task.Position != 2*65535 {
+		t.Errorf("CreateTask position = %v, want %v after the task", task.Position, 2*65535)
+	}
+}
+
+func TestCreateKeepsExplicitPosition(t *testing.T) {
+	ctx := context.Background()
+	srv := plankatest.NewServer(t)
+	listID := srv.AddList(srv.AddBoard(srv.AddProject("Project"), "Board"), "Todo")
+	srv.AddCard(listID, "First")
+	client := srv.Client()
+
+	// 0 is a position like any other, e.g. the top of a list whose first card is at 0
+	top := 0.0
+	card, err := client.CreateCard(ctx, planka.CreateCardRequest{Name: "Top", ListID: listID, Position: &top})
+	if err != nil {
+		t.Fatalf("CreateCard: %v", err)
+	}
+	if card.Position != 0 {
+		t.Errorf("CreateCard position = %v, want the requested 0", card.Position)
+	}
+}
+
+func TestCreateReportsSiblingLoadError(t *testing.T) {
+	ctx := context.Background()
+	// Planka fails to return the card, so the task's siblings are unknown
+	var posted bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posted = true
+		}
+		http.Error(w, `{"code":"E_INTERNAL"}`, http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	client := planka.NewClient(srv.URL, "token", planka.WithRetry(planka.RetryPolicy{Attempts: 1}))
+
+	if _, err := client.CreateTask(ctx, planka.CreateTaskRequest{Name: "Draft", CardID: "1"}); err == nil {
+		t.Fatal("CreateTask succeeded, want the error loading the card's tasks")
+	}
+	if posted {
+		t.Error("CreateTask created the task without knowing its position")
+	}
+
+	// A card created earlier in the same dry run has no tasks to load
+	dryCtx, dryRun := planka.WithDryRun(ctx)
+	if _, err := client.CreateTask(dryCtx, planka.CreateTaskRequest{Name: "Draft", CardID: "dry-run-1"}); err != nil {
+		t.Fatalf("CreateTask on a dry-run card: %v", err)
+	}
+	if calls := dryRun.Calls(); len(calls) != 1 || !strings.Contains(string(calls[0].Body), `"position":65535`) {
+		t.Errorf("dry run recorded %+v, want one task at the first position", calls)
+	}
+}
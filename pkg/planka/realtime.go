@@ -0,0 +1,545 @@
+package planka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	// socketQuery identifies the client to Planka's sails.io socket (socket.io 2, Engine.IO 3)
+	socketQuery = "__sails_io_sdk_version=1.2.1&__sails_io_sdk_platform=node&__sails_io_sdk_language=javascript&EIO=3&transport=websocket"
+	// socketReadLimit bounds a single socket message; board snapshots can be several MB
+	socketReadLimit = 64 << 20
+	// socketRequestTimeout bounds a request sent over the socket
+	socketRequestTimeout = 30 * time.Second
+	// maxReconnectDelay caps the backoff between reconnection attempts
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Event is a change Planka pushed over its socket, e.g. a card being moved
+type Event struct {
+	// Name is Planka's event name, e.g. "cardUpdate"
+	Name string
+	// Entity and Action split the name, e.g. "card" and "update"
+	Entity string
+	Action string
+	// BoardID is the board the changed entity belongs to, if known
+	BoardID string
+	// Item is the created, updated or deleted entity
+	Item json.RawMessage
+}
+
+// BoardSnapshot is the current state of a watched board
+type BoardSnapshot struct {
+	Board  Board
+	Lists  []List
+	Cards  []Card
+	Tasks  []Task
+	Labels []Label
+}
+
+// entityRef holds the fields used to tell which board an entity belongs to
+type entityRef struct {
+	ID      string `json:"id"`
+	BoardID string `json:"boardId"`
+	ListID  string `json:"listId"`
+	CardID  string `json:"cardId"`
+}
+
+// boardMirror holds the entities of a watched board by type and ID, e.g. "card" → ID → JSON
+type boardMirror struct {
+	loaded   bool
+	entities map[string]map[string]json.RawMessage
+}
+
+// socketResponse is the reply to a sails virtual request sent over the socket
+type socketResponse struct {
+	Body       json.RawMessage `json:"body"`
+	StatusCode int             `json:"statusCode"`
+}
+
+// Realtime keeps a socket.io connection to Planka open, mirroring watched boards in memory
+// as Planka pushes changes to them. Every change also drops the client's cached responses
+// it makes stale, and is passed to the registered handlers.
+type Realtime struct {
+	client *Client
+
+	mu          sync.Mutex
+	boards      map[string]*boardMirror
+	handlers    map[int]func(Event)
+	nextHandler int
+	conn        *websocket.Conn
+	nextAck     int
+	pending     map[int]chan socketResponse
+}
+
+// NewRealtime creates a realtime connection for client. It connects once Run is called.
+func NewRealtime(client *Client) *Realtime {
+	return &Realtime{
+		client:   client,
+		boards:   map[string]*boardMirror{},
+		handlers: map[int]func(Event){},
+		pending:  map[int]chan socketResponse{},
+	}
+}
+
+// Run connects to Planka and processes its events until ctx is cancelled,
+// reconnecting with backoff whenever the connection drops
+func (r *Realtime) Run(ctx context.Context) error {
+	delay := time.Second
+	for {
+		start := time.Now()
+		err := r.serve(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// A connection that held up for a while starts the backoff over
+		if time.Since(start) > maxReconnectDelay {
+			delay = time.Second
+		}
+		slog.Warn("Planka socket disconnected, reconnecting", "error", err, "delay", delay)
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// Watch mirrors a board and subscribes to its changes. When disconnected the board is
+// subscribed to once the connection is back.
+func (r *Realtime) Watch(ctx context.Context, boardID string) error {
+	r.mu.Lock()
+	if _, ok := r.boards[boardID]; !ok {
+		r.boards[boardID] = &boardMirror{}
+	}
+	connected := r.conn != nil
+	r.mu.Unlock()
+
+	if !connected {
+		return nil
+	}
+	return r.subscribe(ctx, boardID)
+}
+
+// Unwatch stops mirroring a board
+func (r *Realtime) Unwatch(boardID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.boards, boardID)
+}
+
+// Watching reports whether a board is watched
+func (r *Realtime) Watching(boardID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.boards[boardID]
+	return ok
+}
+
+// Board returns the mirrored state of a watched board once it has been loaded
+func (r *Realtime) Board(boardID string) (*BoardSnapshot, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mirror, ok := r.boards[boardID]
+	if !ok || !mirror.loaded {
+		return nil, false
+	}
+	snapshot := &BoardSnapshot{
+		Lists:  decodeEntities[List](mirror.entities["list"]),
+		Cards:  decodeEntities[Card](mirror.entities["card"]),
+		Tasks:  decodeEntities[Task](mirror.entities["task"]),
+		Labels: decodeEntities[Label](mirror.entities["label"]),
+	}
+	json.Unmarshal(mirror.entities["board"][boardID], &snapshot.Board)
+	return snapshot, true
+}
+
+// Subscribe registers handler to be called with every event Planka pushes.
+// Handlers run on the connection's goroutine and must not block.
+func (r *Realtime) Subscribe(handler func(Event)) (unsubscribe func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextHandler
+	r.nextHandler++
+	r.handlers[id] = handler
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.handlers, id)
+	}
+}
+
+// serve runs a single socket connection until it drops
+func (r *Realtime) serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(socketReadLimit)
+
+	defer func() {
+		r.mu.Lock()
+		r.conn = nil
+		for ack, ch := range r.pending {
+			close(ch)
+			delete(r.pending, ack)
+		}
+		r.mu.Unlock()
+	}()
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if err := r.handlePacket(ctx, conn, string(data)); err != nil {
+			return err
+		}
+	}
+}
+
+// dial opens the websocket to Planka's socket.io endpoint
+func (r *Realtime) dial(ctx context.Context) (*websocket.Conn, error) {
+	endpoint := strings.TrimSuffix(r.client.baseURL, "/") + "/socket.io/?" + socketQuery
+	endpoint = "ws" + strings.TrimPrefix(endpoint, "http")
+
+	// The handshake shares the client's proxy, TLS settings and cookies, but websocket
+	// upgrades need HTTP/1.1 and no overall timeout
+	httpClient := *r.client.httpClient
+	httpClient.Timeout = 0
+	if transport, ok := httpClient.Transport.(*http.Transport); ok {
+		transport = transport.Clone()
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		}
+		httpClient.Transport = transport
+	}
+
+	header := http.Header{}
+	r.client.identify(header)
+	conn, _, err := websocket.Dial(ctx, endpoint, &websocket.DialOptions{HTTPClient: &httpClient, HTTPHeader: header})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Planka socket: %w", err)
+	}
+	return conn, nil
+}
+
+// handlePacket processes one Engine.IO packet
+func (r *Realtime) handlePacket(ctx context.Context, conn *websocket.Conn, packet string) error {
+	switch {
+	case strings.HasPrefix(packet, "0"):
+		// Engine.IO 3 clients keep the connection alive by pinging
+		var open struct {
+			PingInterval int `json:"pingInterval"`
+		}
+		if err := json.Unmarshal([]byte(packet[1:]), &open); err != nil {
+			return fmt.Errorf("invalid socket handshake: %w", err)
+		}
+		go ping(ctx, conn, time.Duration(open.PingInterval)*time.Millisecond)
+	case packet == "2":
+		return conn.Write(ctx, websocket.MessageText, []byte("3"))
+	case packet == "40":
+		r.connected(ctx, conn)
+	case packet == "41":
+		return errors.New("Planka closed the socket")
+	case strings.HasPrefix(packet, "42"):
+		r.handleEvent(strings.TrimLeft(packet[2:], "0123456789"))
+	case strings.HasPrefix(packet, "43"):
+		r.handleAck(packet[2:])
+	case strings.HasPrefix(packet, "44"):
+		return fmt.Errorf("Planka rejected the socket: %s", packet[2:])
+	}
+	return nil
+}
+
+// ping keeps the connection alive until ctx is cancelled
+func ping(ctx context.Context, conn *websocket.Conn, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.Write(ctx, websocket.MessageText, []byte("2")); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// connected resubscribes to the watched boards once the socket is up
+func (r *Realtime) connected(ctx context.Context, conn *websocket.Conn) {
+	r.mu.Lock()
+	r.conn = conn
+	boardIDs := make([]string, 0, len(r.boards))
+	for boardID := range r.boards {
+		boardIDs = append(boardIDs, boardID)
+	}
+	r.mu.Unlock()
+
+	// Changes made while disconnected were missed, so nothing cached can be trusted
+	r.client.cache.clear()
+	r.client.boards.clear()
+
+	// Replies arrive on the read loop, so subscribe in the background
+	go func() {
+		for _, boardID := range boardIDs {
+			if err := r.subscribe(ctx, boardID); err != nil {
+				slog.Warn("Failed to subscribe to Planka board", "board", boardID, "error", err)
+			}
+		}
+	}()
+}
+
+// subscribe fetches a board over the socket, which joins the socket to the board's room,
+// and replaces its mirror with the response
+func (r *Realtime) subscribe(ctx context.Context, boardID string) error {
+	body, err := r.request(ctx, "get", fmt.Sprintf("/api/boards/%s", boardID))
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Item     json.RawMessage              `json:"item"`
+		Included map[string][]json.RawMessage `json:"included"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to decode board %s: %w", boardID, err)
+	}
+
+	mirror := &boardMirror{
+		loaded:   true,
+		entities: map[string]map[string]json.RawMessage{"board": {boardID: resp.Item}},
+	}
+	for key, items := range resp.Included {
+		// Included collections are plural, e.g. "cards" holds entities of type "card"
+		entity := strings.TrimSuffix(key, "s")
+		mirror.entities[entity] = map[string]json.RawMessage{}
+		for _, item := range items {
+			var ref entityRef
+			if json.Unmarshal(item, &ref) == nil && ref.ID != "" {
+				mirror.entities[entity][ref.ID] = item
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The board may have been unwatched in the meantime
+	if _, ok := r.boards[boardID]; ok {
+		r.boards[boardID] = mirror
+	}
+	return nil
+}
+
+// request sends a sails virtual request over the socket and returns the response body
+func (r *Realtime) request(ctx context.Context, method, endpoint string) (json.RawMessage, error) {
+	r.client.checkExpiry(ctx)
+	headers := map[string]string{}
+	if token := r.client.session.currentToken(); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	payload, err := json.Marshal([]interface{}{method, map[string]interface{}{
+		"method":  method,
+		"url":     endpoint,
+		"data":    map[string]interface{}{},
+		"headers": headers,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan socketResponse, 1)
+	r.mu.Lock()
+	conn := r.conn
+	if conn == nil {
+		r.mu.Unlock()
+		return nil, errors.New("Planka socket is not connected")
+	}
+	ack := r.nextAck
+	r.nextAck++
+	r.pending[ack] = ch
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, ack)
+		r.mu.Unlock()
+	}()
+
+	if err := conn.Write(ctx, websocket.MessageText, []byte("42"+strconv.Itoa(ack)+string(payload))); err != nil {
+		return nil, fmt.Errorf("failed to send socket request: %w", err)
+	}
+
+	timer := time.NewTimer(socketRequestTimeout)
+	defer timer.Stop()
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, errors.New("Planka socket disconnected")
+		}
+		if resp.StatusCode >= 400 {
+			return nil, newAPIError(strings.ToUpper(method), endpoint, resp.StatusCode, resp.Body)
+		}
+		return resp.Body, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("socket request %s %s timed out", method, endpoint)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handleAck delivers the reply to a request
+func (r *Realtime) handleAck(packet string) {
+	data := strings.TrimLeft(packet, "0123456789")
+	ack, err := strconv.Atoi(packet[:len(packet)-len(data)])
+	if err != nil {
+		return
+	}
+	var args []socketResponse
+	if err := json.Unmarshal([]byte(data), &args); err != nil || len(args) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	ch, ok := r.pending[ack]
+	r.mu.Unlock()
+	if ok {
+		ch <- args[0]
+	}
+}
+
+// handleEvent applies a change pushed by Planka and passes it on to the handlers
+func (r *Realtime) handleEvent(data string) {
+	var args []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &args); err != nil || len(args) < 2 {
+		return
+	}
+	var name string
+	if err := json.Unmarshal(args[0], &name); err != nil {
+		return
+	}
+	entity, action := splitEventName(name)
+	if action == "" {
+		return
+	}
+
+	var payload struct {
+		Item json.RawMessage `json:"item"`
+	}
+	if err := json.Unmarshal(args[1], &payload); err != nil {
+		return
+	}
+	var ref entityRef
+	json.Unmarshal(payload.Item, &ref)
+
+	// Drop cached responses the change makes stale, as if the client had made it
+	r.client.Forget(Event{Entity: entity, Item: payload.Item})
+
+	r.mu.Lock()
+	boardID := r.apply(entity, action, ref, payload.Item)
+	handlers := make([]func(Event), 0, len(r.handlers))
+	for _, handler := range r.handlers {
+		handlers = append(handlers, handler)
+	}
+	r.mu.Unlock()
+
+	event := Event{Name: name, Entity: entity, Action: action, BoardID: boardID, Item: payload.Item}
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// apply updates the mirror of the board an entity belongs to and returns that board's ID.
+// It must be called with r.mu held.
+func (r *Realtime) apply(entity, action string, ref entityRef, item json.RawMessage) string {
+	boardID := ref.BoardID
+	if entity == "board" {
+		boardID = ref.ID
+	}
+	// Tasks and other card children only carry the ID of their card
+	if boardID == "" && ref.CardID != "" {
+		boardID = r.boardOf("card", ref.CardID)
+	}
+	if boardID == "" && ref.ListID != "" {
+		boardID = r.boardOf("list", ref.ListID)
+	}
+
+	mirror, ok := r.boards[boardID]
+	if !ok || !mirror.loaded || ref.ID == "" {
+		return boardID
+	}
+	if entity == "board" && action == "delete" {
+		delete(r.boards, boardID)
+		return boardID
+	}
+
+	if mirror.entities[entity] == nil {
+		mirror.entities[entity] = map[string]json.RawMessage{}
+	}
+	if action == "delete" {
+		delete(mirror.entities[entity], ref.ID)
+	} else {
+		mirror.entities[entity][ref.ID] = item
+	}
+	return boardID
+}
+
+// boardOf returns the watched board mirroring an entity, or "" if none does.
+// It must be called with r.mu held.
+func (r *Realtime) boardOf(entity, id string) string {
+	for boardID, mirror := range r.boards {
+		if _, ok := mirror.entities[entity][id]; ok {
+			return boardID
+		}
+	}
+	return ""
+}
+
+// splitEventName splits an event name such as "cardUpdate" into "card" and "update".
+// Events that do not describe an entity change return an empty action.
+func splitEventName(name string) (entity, action string) {
+	for _, suffix := range []string{"Create", "Update", "Delete"} {
+		if prefix, ok := strings.CutSuffix(name, suffix); ok && prefix != "" {
+			return prefix, strings.ToLower(suffix)
+		}
+	}
+	return name, ""
+}
+
+// decodeEntities decodes mirrored entities into their typed form
+func decodeEntities[T any](raw map[string]json.RawMessage) []T {
+	items := make([]T, 0, len(raw))
+	for _, data := range raw {
+		var item T
+		if json.Unmarshal(data, &item) == nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,155 @@
+package planka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultMaxRetryDelay caps the delay between retries when the policy sets no MaxDelay
+	defaultMaxRetryDelay = 5 * time.Second
+	// defaultMaxRateLimitWait bounds the wait for Planka's rate limit when the policy sets no MaxRateLimitWait
+	defaultMaxRateLimitWait = 30 * time.Second
+	// defaultRetryAfter is the wait after a 429 response without a usable Retry-After header,
+	// and the shortest wait after any 429
+	defaultRetryAfter = time.Second
+)
+
+// ErrRateLimited is returned when Planka keeps rejecting requests with 429 Too Many Requests
+// for longer than the policy is willing to wait
+var ErrRateLimited = errors.New("rate limited by Planka")
+
+// RetryPolicy configures how idempotent requests that fail with a network error or a
+// 5xx response are retried, so a restarting Planka server does not fail tool calls
+type RetryPolicy struct {
+	// Attempts is the total number of attempts including the first; 1 or less disables retries
+	Attempts int
+	// BaseDelay is the delay before the first retry; it doubles with every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries; zero uses defaultMaxRetryDelay
+	MaxDelay time.Duration
+	// Jitter randomizes each delay by up to this fraction (0 to 1) so clients do not retry in lockstep
+	Jitter float64
+	// MaxRateLimitWait bounds how long a request waits for Planka's rate limit to lift, as
+	// announced by Retry-After on 429 responses; zero uses defaultMaxRateLimitWait
+	MaxRateLimitWait time.Duration
+}
+
+// WithRetry retries failed idempotent requests according to policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// delay returns how long to wait before retry number retry (starting at 1)
+func (p RetryPolicy) delay(retry int) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+
+	delay := p.BaseDelay << (retry - 1)
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// isIdempotent reports whether a request with method can safely be sent again
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// sendWithRetry sends a request, retrying idempotent ones that fail with a network error or
+// a 5xx response. Requests rejected with 429 are retried once Planka's Retry-After has passed,
+// whatever their method, as long as that is within MaxRateLimitWait. It gives up early when
+// ctx is cancelled.
+func (c *Client) sendWithRetry(ctx context.Context, method, endpoint string, jsonData []byte, token string) (*http.Response, error) {
+	attempts := 1
+	if isIdempotent(method) && c.retry.Attempts > 1 {
+		attempts = c.retry.Attempts
+	}
+	maxWait := c.retry.MaxRateLimitWait
+	if maxWait <= 0 {
+		maxWait = defaultMaxRateLimitWait
+	}
+	deadline := time.Now().Add(maxWait)
+
+	for attempt := 1; ; {
+		if err := c.waitForRateLimit(ctx, deadline); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.send(ctx, method, endpoint, jsonData, token)
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			// Hold back every request sharing this session until the limit lifts
+			resp.Body.Close()
+			c.session.holdUntil(time.Now().Add(parseRetryAfter(resp.Header.Get("Retry-After"))))
+			continue
+		}
+
+		retryable := err != nil || resp.StatusCode >= 500
+		if !retryable || attempt >= attempts || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, c.retry.delay(attempt)); err != nil {
+			return nil, err
+		}
+		attempt++
+	}
+}
+
+// waitForRateLimit waits until Planka's rate limit has lifted, failing with ErrRateLimited
+// if that is after deadline
+func (c *Client) waitForRateLimit(ctx context.Context, deadline time.Time) error {
+	until := c.session.heldUntil()
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	if until.After(deadline) {
+		return fmt.Errorf("%w, retry after %s", ErrRateLimited, wait.Round(time.Second))
+	}
+	return sleep(ctx, wait)
+}
+
+// sleep waits for d unless ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+// The wait is at least defaultRetryAfter so a misbehaving server cannot make us spin.
+func parseRetryAfter(value string) time.Duration {
+	wait := defaultRetryAfter
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	}
+	return max(wait, defaultRetryAfter)
+}
@@ -0,0 +1,91 @@
+package planka
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenRefreshMargin is how long before it expires a token obtained by logging in is renewed
+	tokenRefreshMargin = 2 * time.Minute
+	// tokenWarnMargin is how long before it expires a static token starts being warned about
+	tokenWarnMargin = 24 * time.Hour
+	// tokenWarnInterval limits how often the expiry of a static token is warned about
+	tokenWarnInterval = time.Hour
+)
+
+// tokenExpiry returns the expiry time from the exp claim of a JWT access token.
+// The signature is not verified; the expiry is only used to renew the token in time.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+// TokenExpiry returns when the client's access token expires, read from its exp claim.
+// It reports false for tokens that are not JWTs or carry no exp claim.
+func (c *Client) TokenExpiry() (time.Time, bool) {
+	return tokenExpiry(c.session.currentToken())
+}
+
+// CanRenewToken reports whether the client logged in with a username and password, and so
+// logs in again once its access token expires
+func (c *Client) CanRenewToken() bool {
+	return c.session.canLogin()
+}
+
+// checkExpiry runs before each request: a token obtained by logging in is renewed shortly
+// before it expires, and a static token about to expire is warned about since it cannot be
+// renewed. Tokens that are not JWTs or carry no exp claim are left alone.
+func (c *Client) checkExpiry(ctx context.Context) {
+	token := c.session.currentToken()
+	expiresAt, ok := tokenExpiry(token)
+	if !ok {
+		return
+	}
+	remaining := time.Until(expiresAt)
+
+	if c.session.canLogin() {
+		if remaining < tokenRefreshMargin {
+			// A failed renewal is retried when the request is rejected with 401
+			if err := c.relogin(ctx, token); err != nil {
+				slog.Warn("Failed to renew Planka access token before it expires", "expires", expiresAt, "error", err)
+			}
+		}
+		return
+	}
+
+	if remaining < tokenWarnMargin {
+		c.session.mu.Lock()
+		warn := time.Since(c.session.lastWarning) > tokenWarnInterval
+		if warn {
+			c.session.lastWarning = time.Now()
+		}
+		c.session.mu.Unlock()
+
+		if !warn {
+			return
+		}
+		if remaining <= 0 {
+			slog.Error("Planka access token has expired; requests will fail until it is replaced", "expired", expiresAt)
+		} else {
+			slog.Warn("Planka access token expires soon and cannot be renewed automatically; replace PLANKA_TOKEN or use PLANKA_USERNAME/PLANKA_PASSWORD", "expires", expiresAt)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package planka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// webhookPayload is the body of a Planka webhook request
+type webhookPayload struct {
+	Event string `json:"event"`
+	Data  struct {
+		Item     json.RawMessage `json:"item"`
+		Included Included        `json:"included"`
+	} `json:"data"`
+}
+
+// ParseWebhook decodes the body Planka POSTs to a webhook into the Event it describes.
+// The board is taken from the item itself or, for tasks, comments and other card
+// children, from the board Planka included with it.
+func ParseWebhook(body []byte) (Event, error) {
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Event{}, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if payload.Event == "" {
+		return Event{}, errors.New("invalid webhook payload: missing event")
+	}
+
+	entity, action := splitEventName(payload.Event)
+	event := Event{Name: payload.Event, Entity: entity, Action: action, Item: payload.Data.Item}
+
+	var ref entityRef
+	json.Unmarshal(payload.Data.Item, &ref)
+	switch {
+	case entity == "board":
+		event.BoardID = ref.ID
+	case ref.BoardID != "":
+		event.BoardID = ref.BoardID
+	case len(payload.Data.Included.Boards) == 1:
+		event.BoardID = payload.Data.Included.Boards[0].ID
+	}
+	return event, nil
+}
+
+// Forget drops the cached responses and hydrated boards a change made elsewhere, e.g.
+// reported by a webhook, has made stale
+func (c *Client) Forget(event Event) {
+	var ref entityRef
+	json.Unmarshal(event.Item, &ref)
+	c.cache.invalidate(fmt.Sprintf("/api/%ss/%s", event.Entity, ref.ID))
+	c.boards.clear()
+}
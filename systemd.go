@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listenFDsStart is the first file descriptor systemd passes with socket activation
+const listenFDsStart = 3
+
+// systemdListener returns the socket systemd passed to the process through socket
+// activation (LISTEN_FDS), or nil if it passed none. Only the first socket is used.
+func systemdListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, nil
+	}
+	name, _, _ := strings.Cut(os.Getenv("LISTEN_FDNAMES"), ":")
+	// Processes the server starts must not take the sockets for theirs
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	if count > 1 {
+		log.Printf("Warning: systemd passed %d sockets, only the first is served", count)
+	}
+
+	file := os.NewFile(listenFDsStart, name)
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("socket %d passed by systemd: %w", listenFDsStart, err)
+	}
+	return listener, nil
+}
+
+// sdNotify sends a state such as "READY=1" to systemd when it started the process as a
+// Type=notify service, i.e. NOTIFY_SOCKET is set; otherwise it does nothing
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// Abstract sockets are given with a leading @
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	return nil
+}
+
+// notifyReady tells systemd the server is serving and, when the service has a watchdog
+// (WatchdogSec=), keeps telling it the server is alive at half the watchdog interval
+func notifyReady() {
+	if err := sdNotify("READY=1"); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	usec, err := strconv.Atoi(os.Getenv("WATCHDOG_USEC"))
+	if err != nil || usec <= 0 {
+		return
+	}
+	if pid, err := strconv.Atoi(os.Getenv("WATCHDOG_PID")); err == nil && pid != os.Getpid() {
+		return
+	}
+	interval := time.Duration(usec) * time.Microsecond / 2
+	go func() {
+		for range time.Tick(interval) {
+			if err := sdNotify("WATCHDOG=1"); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}()
+}
@@ -10,6 +10,16 @@ BASE_URL="${BASE_URL:-http://localhost:8080}"
 MCP_ENDPOINT="${BASE_URL}/mcp"
 HEALTH_ENDPOINT="${BASE_URL}/health"
 
+# API key for servers started with --http-api-keys / MCP_API_KEYS
+AUTH_ARGS=()
+if [ -n "$MCP_API_KEY" ]; then
+    AUTH_ARGS=(-H "Authorization: Bearer $MCP_API_KEY")
+fi
+
+# Session ID issued on initialize, shared across subshells through a temp file
+SESSION_FILE=$(mktemp)
+trap 'rm -f "$SESSION_FILE"' EXIT
+
 # Colors for output
 RED='\033[0;31m'
 GREEN='\033[0;32m'
@@ -75,10 +85,32 @@ EOF
         )
     fi
     
-    echo "$request" | curl -s -X POST \
-        -H "Content-Type: application/json" \
-        -d @- \
-        "$MCP_ENDPOINT"
+    mcp_post "$request"
+}
+
+# Helper function to POST a raw body to the MCP endpoint
+# The session ID is recorded from the initialize response and sent on every later request
+mcp_post() {
+    local body=$1
+    local session_id=$(cat "$SESSION_FILE")
+    local headers=$(mktemp)
+
+    local curl_args=(-s -X POST
+        -H "Content-Type: application/json"
+        -H "Accept: application/json, text/event-stream"
+        "${AUTH_ARGS[@]}"
+        -D "$headers")
+    if [ -n "$session_id" ]; then
+        curl_args+=(-H "Mcp-Session-Id: $session_id")
+    fi
+
+    echo "$body" | curl "${curl_args[@]}" -d @- "$MCP_ENDPOINT"
+
+    local new_session=$(grep -i '^Mcp-Session-Id:' "$headers" | cut -d' ' -f2 | tr -d '\r')
+    if [ -n "$new_session" ]; then
+        echo "$new_session" > "$SESSION_FILE"
+    fi
+    rm -f "$headers"
 }
 
 # Helper function to check JSON-RPC response
@@ -189,11 +221,19 @@ test_initialize() {
 test_initialized() {
     print_test "Initialized Notification (POST /mcp - notifications/initialized)"
     
-    response=$(jsonrpc_request "notifications/initialized" "" 2)
+    # Notifications carry no id and are acknowledged with 202 Accepted and an empty body
+    status=$(curl -s -o /dev/null -w "%{http_code}" -X POST \
+        -H "Content-Type: application/json" \
+        -H "Accept: application/json, text/event-stream" \
+        -H "Mcp-Session-Id: $(cat "$SESSION_FILE")" \
+        "${AUTH_ARGS[@]}" \
+        -d '{"jsonrpc": "2.0", "method": "notifications/initialized"}' \
+        "$MCP_ENDPOINT")
     
-    if check_jsonrpc_response "$response" "notifications/initialized"; then
+    if [ "$status" = "202" ]; then
         print_success "Initialized notification accepted"
     else
+        print_failure "Expected HTTP 202 for notification, got $status"
         return 1
     fi
 }
@@ -332,6 +372,9 @@ test_invalid_method() {
             echo "$response" | jq '.'
             return 1
         fi
+    elif [ -n "$response" ] && ! echo "$response" | jq -e . > /dev/null 2>&1; then
+        # Unknown methods are rejected by the transport with a plain-text HTTP 400
+        print_success "Invalid method correctly rejected: $response"
     else
         print_failure "Expected error for invalid method, but got success"
         return 1
@@ -342,17 +385,18 @@ test_invalid_method() {
 test_invalid_json() {
     print_test "Invalid JSON (POST /mcp - malformed JSON)"
     
-    response=$(echo "invalid json" | curl -s -X POST \
+    status=$(echo "invalid json" | curl -s -o /dev/null -w "%{http_code}" -X POST \
         -H "Content-Type: application/json" \
+        -H "Accept: application/json, text/event-stream" \
+        -H "Mcp-Session-Id: $(cat "$SESSION_FILE")" \
+        "${AUTH_ARGS[@]}" \
         -d @- \
         "$MCP_ENDPOINT")
     
-    if echo "$response" | jq -e '.error' > /dev/null 2>&1; then
-        print_success "Invalid JSON correctly rejected"
-        echo "$response" | jq '.error'
+    if [ "$status" = "400" ]; then
+        print_success "Invalid JSON correctly rejected (HTTP $status)"
     else
-        print_failure "Expected error for invalid JSON"
-        echo "$response"
+        print_failure "Expected HTTP 400 for invalid JSON, got $status"
         return 1
     fi
 }